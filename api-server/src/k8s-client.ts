@@ -31,6 +31,16 @@ export type ServerType =
   | 'QUILT'
   | 'NEOFORGE';
 
+// Bedrock Edition crossplay through Geyser (Paper-family server types only)
+export interface BedrockConfig {
+  enabled: boolean;
+  floodgate?: boolean;
+  port?: number;
+}
+
+// Desired power state; Auto sleeps when idle and wakes when a player connects
+export type PowerState = 'On' | 'Off' | 'Auto';
+
 // Level/world types for generation
 export type LevelType = 'default' | 'flat' | 'largeBiomes' | 'amplified' | 'singleBiome';
 
@@ -38,11 +48,37 @@ export interface MinecraftServerSpec {
   serverId: string;
   displayName: string;
   tenantId: string;
-  stopped?: boolean;
+  stopped?: boolean; // Deprecated: only read by the operator while powerState is unset
+  powerState?: PowerState;
   image?: string;
+  imageUpdate?: {
+    policy?: 'Manual' | 'Automatic'; // When a newer digest of the image tag is rolled out
+    window?: { schedule: string; duration: string; timezone?: string }; // Required for Automatic
+  };
   serverType?: ServerType;
   version: string;
   rconPassword?: string; // Unique RCON password for this server
+  templateRef?: { name: string }; // MinecraftServerTemplate the operator builds the spec from
+  size?: string; // Resource tier (XS-XL or custom); resources set explicitly override it
+  jvm?: {
+    profile?: 'aikar' | 'zgc' | 'default'; // Unset: aikar from 10 max players
+    xxOptions?: string[];
+    extraArgs?: string[];
+  };
+  modpack?: {
+    source: 'Modrinth' | 'CurseForge' | 'URL';
+    project?: string;
+    url?: string;
+    version?: string; // Unset: newest version for the server's Minecraft version
+    apiKeySecretRef?: { name: string; key: string }; // CurseForge API key
+  };
+  upgrade?: {
+    dryRun?: boolean; // Start the new version on a copy of the world first
+    timeout?: string; // Go duration to become Ready before rolling back (default 10m)
+    allowDowngrade?: boolean; // Acknowledge a version older than status.highestVersion
+    restoreBackup?: string; // Backup ID restored before a downgrade
+  };
+  maintenanceWindow?: { schedule: string; duration: string; timezone?: string }; // Default: the tenant's window
   resources: {
     cpuRequest: string;
     cpuLimit: string;
@@ -88,6 +124,7 @@ export interface MinecraftServerSpec {
   };
   autoStop?: AutoStopConfig;
   autoStart?: AutoStartConfig;
+  bedrock?: BedrockConfig;
 }
 
 export interface MinecraftServerStatus {
@@ -100,12 +137,18 @@ export interface MinecraftServerStatus {
   message?: string;
   externalIP?: string;
   port?: number;
+  hostname?: string;
+  bedrockEndpoint?: string;
   playerCount?: number;
   maxPlayers?: number;
   version?: string;
   serverType?: ServerType;
   lastPlayerActivity?: string;
   autoStoppedAt?: string;
+  powerState?: PowerState;
+  sleeping?: boolean;
+  wokenAt?: string;
+  wakeReason?: string;
   autoStop?: AutoStopConfig;
   autoStart?: AutoStartConfig;
   config?: MinecraftServerSpec['config'];
@@ -117,6 +160,7 @@ export interface MinecraftServer {
   metadata: {
     name: string;
     namespace: string;
+    annotations?: Record<string, string>;
   };
   spec: MinecraftServerSpec;
   status?: {
@@ -124,11 +168,29 @@ export interface MinecraftServer {
     message?: string;
     externalIP?: string;
     port?: number;
+    hostname?: string;
+    bedrockEndpoint?: string;
     playerCount?: number;
     maxPlayers?: number;
     version?: string;
     lastPlayerActivity?: string;
     autoStoppedAt?: string;
+    sleeping?: boolean;
+    wokenAt?: string;
+    wakeReason?: string;
+  };
+}
+
+// The power state a start request moves an Off server to, matching the operator's start command
+export function startPowerState(spec: Pick<MinecraftServerSpec, 'autoStop' | 'autoStart'>): PowerState {
+  return spec.autoStop?.enabled || spec.autoStart?.enabled ? 'Auto' : 'On';
+}
+
+// Ask the operator to wake a sleeping Auto server
+export function requestWake(metadata: { annotations?: Record<string, string> }): void {
+  metadata.annotations = {
+    ...metadata.annotations,
+    'minecraft.platform.com/wake-requested-at': new Date().toISOString(),
   };
 }
 
@@ -141,7 +203,8 @@ export class K8sClient {
   private k8sAvailable: boolean = false;
 
   private readonly group = 'minecraft.platform.com';
-  private readonly version = 'v1';
+  // v2 is the storage version; v1 is frozen and lacks powerState, size, templateRef and the newer status fields
+  private readonly version = 'v2';
   private readonly plural = 'minecraftservers';
   // Container name used by itzg/minecraft-server deployments
   private readonly containerName = 'minecraft-server';
@@ -553,8 +616,11 @@ export class K8sClient {
 
       const server = existing as unknown as MinecraftServer;
 
-      // Set stopped to true
+      // Switch the server off; the deprecated stopped field is kept in step for older operators
       server.spec.stopped = true;
+      if (server.spec.powerState) {
+        server.spec.powerState = 'Off';
+      }
 
       // Update the CRD
       await this.customApi!.replaceNamespacedCustomObject({
@@ -588,8 +654,12 @@ export class K8sClient {
 
       const server = existing as unknown as MinecraftServer;
 
-      // Set stopped to false
+      // Switch the server back on, and wake it if it is sleeping
       server.spec.stopped = false;
+      if (server.spec.powerState === 'Off') {
+        server.spec.powerState = startPowerState(server.spec);
+      }
+      requestWake(server.metadata);
 
       // Update the CRD
       await this.customApi!.replaceNamespacedCustomObject({
@@ -927,12 +997,18 @@ export class K8sClient {
       message: server.status?.message,
       externalIP: server.status?.externalIP,
       port: server.status?.port,
+      hostname: server.status?.hostname,
+      bedrockEndpoint: server.status?.bedrockEndpoint,
       playerCount: server.status?.playerCount || 0,
       maxPlayers: server.status?.maxPlayers || server.spec.config.maxPlayers,
       version: server.status?.version || server.spec.version,
       serverType: server.spec.serverType,
       lastPlayerActivity: server.status?.lastPlayerActivity,
       autoStoppedAt: server.status?.autoStoppedAt,
+      powerState: server.spec.powerState,
+      sleeping: server.status?.sleeping,
+      wokenAt: server.status?.wokenAt,
+      wakeReason: server.status?.wakeReason,
       autoStop: server.spec.autoStop,
       autoStart: server.spec.autoStart,
       config: server.spec.config,
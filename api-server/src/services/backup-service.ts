@@ -7,6 +7,7 @@ import { getEventBus, EventType } from '../events/event-bus.js';
 import { GoogleDriveService, createDriveServiceForUser } from './google-drive-service.js';
 import { userStore, User } from '../models/user.js';
 import { backupStore, BackupSchedule } from '../db/backup-store-db.js';
+import { MinecraftServerSpec, requestWake, startPowerState } from '../k8s-client.js';
 import * as WebSocket from 'ws';
 
 export interface BackupOptions {
@@ -29,9 +30,9 @@ interface MinecraftServerCRD {
     name: string;
     namespace: string;
     resourceVersion?: string;
+    annotations?: Record<string, string>;
   };
-  spec: {
-    stopped?: boolean;
+  spec: Pick<MinecraftServerSpec, 'stopped' | 'powerState' | 'autoStop' | 'autoStart'> & {
     [key: string]: unknown;
   };
 }
@@ -49,7 +50,7 @@ export class BackupService {
 
   // CRD constants for MinecraftServer
   private readonly crdGroup = 'minecraft.platform.com';
-  private readonly crdVersion = 'v1';
+  private readonly crdVersion = 'v2';
   private readonly crdPlural = 'minecraftservers';
 
   // Database-backed storage (persistent)
@@ -1185,8 +1186,11 @@ export class BackupService {
 
     const server = existing as MinecraftServerCRD;
 
-    // Set stopped to true
+    // Switch the server off; the deprecated stopped field is kept in step for older operators
     server.spec.stopped = true;
+    if (server.spec.powerState) {
+      server.spec.powerState = 'Off';
+    }
 
     // Update the CRD
     await this.customApi.replaceNamespacedCustomObject({
@@ -1217,8 +1221,12 @@ export class BackupService {
 
     const server = existing as MinecraftServerCRD;
 
-    // Set stopped to false
+    // Switch the server back on, and wake it if it is sleeping
     server.spec.stopped = false;
+    if (server.spec.powerState === 'Off') {
+      server.spec.powerState = startPowerState(server.spec);
+    }
+    requestWake(server.metadata);
 
     // Update the CRD
     await this.customApi.replaceNamespacedCustomObject({
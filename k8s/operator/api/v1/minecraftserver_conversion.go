@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	v2 "minecraft-platform-operator/api/v2"
+)
+
+// HubFieldsAnnotation keeps the v2 fields a v1 object has no place for, so a v1 client that reads
+// and writes back a server doesn't drop them
+const HubFieldsAnnotation = "minecraft.platform.com/v2-fields"
+
+var _ conversion.Convertible = &MinecraftServer{}
+
+// hubFields are the parts of a v2 MinecraftServer that v1 doesn't have
+// The JSON names match v2, so they are filled from and merged back into v2 objects through JSON
+type hubFields struct {
+	Spec   hubSpecFields   `json:"spec,omitempty"`
+	Status hubStatusFields `json:"status,omitempty"`
+}
+
+type hubSpecFields struct {
+	PowerState        v2.PowerState         `json:"powerState,omitempty"`
+	ImageUpdate       *v2.ImageUpdateConfig `json:"imageUpdate,omitempty"`
+	TemplateRef       *v2.TemplateReference `json:"templateRef,omitempty"`
+	Size              string                `json:"size,omitempty"`
+	JVM               *v2.JVMConfig         `json:"jvm,omitempty"`
+	Modpack           *v2.ModpackConfig     `json:"modpack,omitempty"`
+	Upgrade           *v2.UpgradeConfig     `json:"upgrade,omitempty"`
+	MaintenanceWindow *v2.MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+	Network           *v2.NetworkConfig     `json:"network,omitempty"`
+	Monitoring        *v2.MonitoringConfig  `json:"monitoring,omitempty"`
+	Bedrock           *v2.BedrockConfig     `json:"bedrock,omitempty"`
+}
+
+type hubStatusFields struct {
+	ObservedGeneration    int64              `json:"observedGeneration,omitempty"`
+	Conditions            []metav1.Condition `json:"conditions,omitempty"`
+	Hostname              string             `json:"hostname,omitempty"`
+	BedrockEndpoint       string             `json:"bedrockEndpoint,omitempty"`
+	TemplateRevision      int64              `json:"templateRevision,omitempty"`
+	Players               []string           `json:"players,omitempty"`
+	Image                 *v2.ImageStatus    `json:"image,omitempty"`
+	HighestVersion        string             `json:"highestVersion,omitempty"`
+	Upgrade               *v2.UpgradeStatus  `json:"upgrade,omitempty"`
+	PendingChanges        []v2.PendingChange `json:"pendingChanges,omitempty"`
+	NextMaintenanceWindow *metav1.Time       `json:"nextMaintenanceWindow,omitempty"`
+	Modpack               *v2.ModpackStatus  `json:"modpack,omitempty"`
+	Sleeping              bool               `json:"sleeping,omitempty"`
+	WokenAt               *metav1.Time       `json:"wokenAt,omitempty"`
+	WakeReason            string             `json:"wakeReason,omitempty"`
+}
+
+// ConvertTo converts this v1 MinecraftServer to the v2 hub version
+// Fields shared with v2 are converted through JSON, the v2-only fields are restored from HubFieldsAnnotation
+func (src *MinecraftServer) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v2.MinecraftServer)
+	if !ok {
+		return fmt.Errorf("expected a v2 MinecraftServer but got %T", dstRaw)
+	}
+
+	if err := convertJSON(src, dst); err != nil {
+		return err
+	}
+	dst.APIVersion = v2.GroupVersion.String()
+
+	// v1 omits false booleans, so they are copied explicitly
+	config := &src.Spec.Config
+	dst.Spec.Config.WhiteList = pointer.Bool(config.WhiteList)
+	dst.Spec.Config.OnlineMode = pointer.Bool(config.OnlineMode)
+	dst.Spec.Config.PVP = pointer.Bool(config.PVP)
+	dst.Spec.Config.EnableCommandBlock = pointer.Bool(config.EnableCommandBlock)
+	dst.Spec.Config.AllowFlight = pointer.Bool(config.AllowFlight)
+	dst.Spec.Config.AllowNether = pointer.Bool(config.AllowNether)
+	dst.Spec.Config.SpawnAnimals = pointer.Bool(config.SpawnAnimals)
+	dst.Spec.Config.SpawnMonsters = pointer.Bool(config.SpawnMonsters)
+	dst.Spec.Config.SpawnNPCs = pointer.Bool(config.SpawnNPCs)
+	dst.Spec.Config.GenerateStructures = pointer.Bool(config.GenerateStructures)
+	dst.Spec.Config.HardcoreMode = pointer.Bool(config.HardcoreMode)
+	dst.Spec.Config.ForceGamemode = pointer.Bool(config.ForceGamemode)
+	for i := range src.Spec.Plugins {
+		dst.Spec.Plugins[i].Enabled = pointer.Bool(src.Spec.Plugins[i].Enabled)
+	}
+
+	data, ok := src.Annotations[HubFieldsAnnotation]
+	if !ok {
+		return nil
+	}
+	delete(dst.Annotations, HubFieldsAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	if err := json.Unmarshal([]byte(data), dst); err != nil {
+		return fmt.Errorf("failed to restore v2 fields: %w", err)
+	}
+
+	// A v1 client only sees spec.stopped; when it was changed the deprecated fields decide the power state
+	if dst.Spec.PowerState != "" && (dst.Spec.PowerState == v2.PowerOff) != src.Spec.Stopped {
+		dst.Spec.PowerState = ""
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the v2 hub version to this v1 MinecraftServer
+// Unset v2 booleans take their defaults, since v1 cannot tell unset from false
+func (dst *MinecraftServer) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v2.MinecraftServer)
+	if !ok {
+		return fmt.Errorf("expected a v2 MinecraftServer but got %T", srcRaw)
+	}
+
+	hub := src.DeepCopy()
+	hub.Spec.ApplyBoolDefaults()
+	hub.Spec.Stopped = hub.Spec.EffectivePowerState() == v2.PowerOff
+
+	if err := convertJSON(hub, dst); err != nil {
+		return err
+	}
+	dst.APIVersion = GroupVersion.String()
+
+	var fields hubFields
+	if err := convertJSON(hub, &fields); err != nil {
+		return err
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal v2 fields: %w", err)
+	}
+	if string(data) != `{"spec":{},"status":{}}` {
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[HubFieldsAnnotation] = string(data)
+	}
+
+	return nil
+}
+
+// convertJSON copies src into dst through their shared JSON representation
+func convertJSON(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %T: %w", src, err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("failed to unmarshal into %T: %w", dst, err)
+	}
+	return nil
+}
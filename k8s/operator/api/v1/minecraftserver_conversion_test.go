@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v2 "minecraft-platform-operator/api/v2"
+)
+
+// hubServer is a v2 server using fields v1 doesn't have
+func hubServer() *v2.MinecraftServer {
+	return &v2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "default"},
+		Spec: v2.MinecraftServerSpec{
+			ServerID:   "server-1",
+			TenantID:   "tenant-a",
+			PowerState: v2.PowerAuto,
+			Size:       "M",
+			JVM:        &v2.JVMConfig{Profile: v2.JVMProfileAikar},
+			Config:     v2.MinecraftServerConfig{OnlineMode: pointer.Bool(false), PVP: pointer.Bool(true)},
+			Plugins:    []v2.MinecraftPlugin{{Name: "worldedit", Enabled: pointer.Bool(false)}},
+		},
+		Status: v2.MinecraftServerStatus{
+			Phase:          v2.PhaseRunning,
+			Sleeping:       true,
+			HighestVersion: "1.20.4",
+			Conditions:     []metav1.Condition{{Type: v2.ConditionReady, Status: metav1.ConditionTrue, Reason: "Ready"}},
+		},
+	}
+}
+
+func TestConvertFromKeepsV1Schema(t *testing.T) {
+	var server MinecraftServer
+	if err := server.ConvertFrom(hubServer()); err != nil {
+		t.Fatalf("ConvertFrom: %v", err)
+	}
+
+	data, err := json.Marshal(server.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"powerState", "size", "jvm"} {
+		if strings.Contains(string(data), `"`+field+`"`) {
+			t.Errorf("v1 spec contains %s: %s", field, data)
+		}
+	}
+	if !strings.Contains(server.Annotations[HubFieldsAnnotation], `"size":"M"`) {
+		t.Errorf("expected the v2 fields in the annotation, got %q", server.Annotations[HubFieldsAnnotation])
+	}
+	if server.Spec.Config.OnlineMode || !server.Spec.Config.PVP || !server.Spec.Config.SpawnAnimals {
+		t.Errorf("unexpected v1 booleans %+v", server.Spec.Config)
+	}
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	hub := hubServer()
+	var server MinecraftServer
+	if err := server.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom: %v", err)
+	}
+
+	var restored v2.MinecraftServer
+	if err := server.ConvertTo(&restored); err != nil {
+		t.Fatalf("ConvertTo: %v", err)
+	}
+
+	if restored.Spec.PowerState != v2.PowerAuto || restored.Spec.Size != "M" || restored.Spec.JVM == nil {
+		t.Errorf("v2 spec fields were lost: %+v", restored.Spec)
+	}
+	if !restored.Status.Sleeping || restored.Status.HighestVersion != "1.20.4" || len(restored.Status.Conditions) != 1 {
+		t.Errorf("v2 status fields were lost: %+v", restored.Status)
+	}
+	if *restored.Spec.Config.OnlineMode || !*restored.Spec.Config.SpawnAnimals {
+		t.Errorf("unexpected v2 booleans %+v", restored.Spec.Config)
+	}
+	if *restored.Spec.Plugins[0].Enabled {
+		t.Error("expected the disabled plugin to stay disabled")
+	}
+	if _, ok := restored.Annotations[HubFieldsAnnotation]; ok {
+		t.Error("expected the annotation to be removed from the v2 object")
+	}
+}
+
+func TestConvertToStoppedFromV1(t *testing.T) {
+	var server MinecraftServer
+	if err := server.ConvertFrom(hubServer()); err != nil {
+		t.Fatalf("ConvertFrom: %v", err)
+	}
+
+	// A v1 client stops the server
+	server.Spec.Stopped = true
+
+	var restored v2.MinecraftServer
+	if err := server.ConvertTo(&restored); err != nil {
+		t.Fatalf("ConvertTo: %v", err)
+	}
+	if restored.Spec.PowerState != "" {
+		t.Errorf("expected powerState to be cleared, got %s", restored.Spec.PowerState)
+	}
+	if state := restored.Spec.EffectivePowerState(); state != v2.PowerOff {
+		t.Errorf("expected the server to be Off, got %s", state)
+	}
+}
+
+func TestConvertFromWithoutV2Fields(t *testing.T) {
+	hub := &v2.MinecraftServer{Spec: v2.MinecraftServerSpec{ServerID: "server-1", TenantID: "tenant-a"}}
+	var server MinecraftServer
+	if err := server.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom: %v", err)
+	}
+	if _, ok := server.Annotations[HubFieldsAnnotation]; ok {
+		t.Errorf("unexpected annotation %q", server.Annotations[HubFieldsAnnotation])
+	}
+}
@@ -338,6 +338,7 @@ type NetworkIOStats struct {
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // MinecraftServer is the Schema for the minecraftservers API
+// v1 is frozen and still served for existing clients; objects are stored as v2 and converted by the conversion webhook
 type MinecraftServer struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -359,80 +360,6 @@ func init() {
 	SchemeBuilder.Register(&MinecraftServer{}, &MinecraftServerList{})
 }
 
-// Default sets default values for MinecraftServer
-func (m *MinecraftServer) Default() {
-	if m.Spec.Image == "" {
-		m.Spec.Image = "itzg/minecraft-server:latest"
-	}
-
-	if m.Spec.ServerType == "" {
-		m.Spec.ServerType = "VANILLA"
-	}
-
-	if m.Spec.Version == "" {
-		m.Spec.Version = "1.20.1"
-	}
-
-	if m.Spec.StorageClass == "" {
-		m.Spec.StorageClass = "standard"
-	}
-
-	if m.Spec.Config.MaxPlayers == 0 {
-		m.Spec.Config.MaxPlayers = 20
-	}
-
-	if m.Spec.Config.Gamemode == "" {
-		m.Spec.Config.Gamemode = "survival"
-	}
-
-	if m.Spec.Config.Difficulty == "" {
-		m.Spec.Config.Difficulty = "normal"
-	}
-
-	if m.Spec.Config.LevelName == "" {
-		m.Spec.Config.LevelName = "world"
-	}
-
-	if m.Spec.Config.LevelType == "" {
-		m.Spec.Config.LevelType = "default"
-	}
-
-	if m.Spec.Config.MOTD == "" {
-		m.Spec.Config.MOTD = "A Minecraft Server powered by Kubernetes"
-	}
-
-	if m.Spec.Config.SpawnProtection == 0 {
-		m.Spec.Config.SpawnProtection = 16
-	}
-
-	if m.Spec.Config.ViewDistance == 0 {
-		m.Spec.Config.ViewDistance = 10
-	}
-
-	if m.Spec.Config.SimulationDistance == 0 {
-		m.Spec.Config.SimulationDistance = 10
-	}
-
-	// Use annotations to track if defaults have been applied for boolean fields
-	// This prevents overwriting explicit false values
-	if m.ObjectMeta.Annotations == nil {
-		m.ObjectMeta.Annotations = make(map[string]string)
-	}
-
-	if _, exists := m.ObjectMeta.Annotations["defaults-applied"]; !exists {
-		// Default to true for these boolean fields
-		m.Spec.Config.OnlineMode = true
-		m.Spec.Config.PVP = true
-		m.Spec.Config.EnableCommandBlock = true
-		m.Spec.Config.AllowNether = true
-		m.Spec.Config.SpawnAnimals = true
-		m.Spec.Config.SpawnMonsters = true
-		m.Spec.Config.SpawnNPCs = true
-		m.Spec.Config.GenerateStructures = true
-		m.ObjectMeta.Annotations["defaults-applied"] = "true"
-	}
-}
-
 // GetTenantID returns the tenant ID for this server
 func (m *MinecraftServer) GetTenantID() string {
 	return m.Spec.TenantID
@@ -0,0 +1,57 @@
+package v2
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultImage is the server image when spec.image is unset
+const DefaultImage = "itzg/minecraft-server:latest"
+
+// UpdateImageAnnotation rolls out the newest digest of spec.image's tag; removed once the digest is pinned
+const UpdateImageAnnotation = "minecraft.platform.com/update-image"
+
+// ImageUpdatePolicy decides when a newer digest of spec.image's tag replaces the pinned one
+// +kubebuilder:validation:Enum=Manual;Automatic
+type ImageUpdatePolicy string
+
+const (
+	// ImageUpdateManual keeps the pinned digest until spec.image changes or the update-image annotation is set
+	ImageUpdateManual ImageUpdatePolicy = "Manual"
+	// ImageUpdateAutomatic rolls out a newer digest during the maintenance window
+	ImageUpdateAutomatic ImageUpdatePolicy = "Automatic"
+)
+
+// ImageUpdateConfig configures how the pinned image digest is kept up to date
+type ImageUpdateConfig struct {
+	// Policy is Manual or Automatic
+	// +kubebuilder:default=Manual
+	Policy ImageUpdatePolicy `json:"policy,omitempty"`
+
+	// Window is when Automatic updates restart the server; defaults to the server's maintenance window
+	Window *MaintenanceWindow `json:"window,omitempty"`
+}
+
+// ImageStatus is the image digest the server runs
+type ImageStatus struct {
+	// Reference is the spec.image the digest was resolved from
+	Reference string `json:"reference"`
+
+	// Digest is the pinned manifest digest
+	Digest string `json:"digest,omitempty"`
+
+	// LatestDigest is what the tag points at now, when it differs from Digest
+	LatestDigest string `json:"latestDigest,omitempty"`
+
+	// PinnedAt is when Digest was pinned
+	PinnedAt *metav1.Time `json:"pinnedAt,omitempty"`
+}
+
+// PinnedImage returns the image with its digest, e.g. itzg/minecraft-server:latest@sha256:...
+func (s *ImageStatus) PinnedImage() string {
+	if s.Digest == "" || strings.Contains(s.Reference, "@") {
+		return s.Reference
+	}
+	return s.Reference + "@" + s.Digest
+}
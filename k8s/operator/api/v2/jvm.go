@@ -0,0 +1,118 @@
+package v2
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// JVMProfile selects the garbage collector tuning of the server's JVM
+// +kubebuilder:validation:Enum=default;aikar;zgc
+type JVMProfile string
+
+const (
+	// JVMProfileDefault leaves the JVM's own defaults
+	JVMProfileDefault JVMProfile = "default"
+	// JVMProfileAikar uses Aikar's G1 flags, tuned for Minecraft's allocation pattern
+	JVMProfileAikar JVMProfile = "aikar"
+	// JVMProfileZGC uses the low-pause ZGC collector (Java 17+, Minecraft 1.17+)
+	JVMProfileZGC JVMProfile = "zgc"
+)
+
+// zgcMinimumVersion is the oldest Minecraft version whose Java runtime (17) has a production ZGC
+var zgcMinimumVersion = MinecraftVersion{Major: 1, Minor: 17}
+
+// JVMConfig tunes the Java VM running the server
+type JVMConfig struct {
+	// Profile is the garbage collector tuning: aikar (G1 tuned for Minecraft), zgc, or default (the JVM defaults)
+	// When unset the JVM defaults are kept; aikar is recommended for servers with 10 or more max players
+	Profile JVMProfile `json:"profile,omitempty"`
+
+	// XXOptions are -XX options without the prefix, e.g. "+AlwaysPreTouch" or "MaxGCPauseMillis=100"
+	// Options that conflict with the operator-managed heap and collector, or run commands, are rejected
+	// +kubebuilder:validation:MaxItems=32
+	XXOptions []string `json:"xxOptions,omitempty"`
+
+	// ExtraArgs are further JVM arguments, e.g. "-Dlog4j2.formatMsgNoLookups=true"
+	// Heap sizes come from resources and -XX options from xxOptions; agents can't be loaded
+	// +kubebuilder:validation:MaxItems=32
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+}
+
+// ResolveJVMProfile returns the profile a server runs with
+// An unset profile keeps the JVM defaults, so existing servers aren't restarted onto new flags
+func ResolveJVMProfile(jvm *JVMConfig) JVMProfile {
+	if jvm != nil && jvm.Profile != "" {
+		return jvm.Profile
+	}
+	return JVMProfileDefault
+}
+
+// xxOptionPattern is a boolean (+Name, -Name) or valued (Name=value) -XX option
+var xxOptionPattern = regexp.MustCompile(`^([+-][A-Za-z][A-Za-z0-9]*|[A-Za-z][A-Za-z0-9]*=[^\s]+)$`)
+
+// deniedXXOptions are -XX options that break the container or the operator's tuning
+// Heap and RAM sizing come from resources, the collector from the profile, and the On* hooks run shell commands
+var deniedXXOptions = map[string]string{
+	"MaxHeapSize":                 "the heap is set from resources.memory",
+	"InitialHeapSize":             "the heap is set from resources.memory",
+	"MinHeapSize":                 "the heap is set from resources.memory",
+	"MaxRAM":                      "the heap is set from resources.memory",
+	"MaxRAMPercentage":            "the heap is set from resources.memory",
+	"InitialRAMPercentage":        "the heap is set from resources.memory",
+	"MinRAMPercentage":            "the heap is set from resources.memory",
+	"UseContainerSupport":         "the JVM must respect the container limits",
+	"UseSerialGC":                 "the collector is selected by jvm.profile",
+	"UseParallelGC":               "the collector is selected by jvm.profile",
+	"UseG1GC":                     "the collector is selected by jvm.profile",
+	"UseZGC":                      "the collector is selected by jvm.profile",
+	"UseShenandoahGC":             "the collector is selected by jvm.profile",
+	"UseEpsilonGC":                "the collector is selected by jvm.profile",
+	"UnlockExperimentalVMOptions": "experimental options are not supported",
+	"UnlockDiagnosticVMOptions":   "diagnostic options are not supported",
+	"OnError":                     "it runs shell commands",
+	"OnOutOfMemoryError":          "it runs shell commands",
+	"HeapDumpPath":                "heap dumps would fill the world volume",
+	"HeapDumpOnOutOfMemoryError":  "heap dumps would fill the world volume",
+}
+
+// deniedArgPrefixes are JVM arguments that extraArgs can't contain
+var deniedArgPrefixes = []struct {
+	prefix string
+	reason string
+}{
+	{"-Xmx", "the heap is set from resources.memory"},
+	{"-Xms", "the heap is set from resources.memory"},
+	{"-Xmn", "the heap is set from resources.memory"},
+	{"-XX:", "use jvm.xxOptions"},
+	{"-javaagent", "agents can't be loaded"},
+	{"-agentlib", "agents can't be loaded"},
+	{"-agentpath", "agents can't be loaded"},
+	{"-jar", "the server jar is chosen by the image"},
+}
+
+// CheckXXOption verifies a -XX option (without the -XX: prefix) against the syntax and the denylist
+func CheckXXOption(option string) error {
+	if !xxOptionPattern.MatchString(option) {
+		return fmt.Errorf("expected +Name, -Name or Name=value without the -XX: prefix")
+	}
+	name := strings.TrimLeft(option, "+-")
+	name, _, _ = strings.Cut(name, "=")
+	if reason, denied := deniedXXOptions[name]; denied {
+		return fmt.Errorf("-XX:%s is not allowed: %s", name, reason)
+	}
+	return nil
+}
+
+// CheckJVMArg verifies an extra JVM argument against the denylist
+func CheckJVMArg(arg string) error {
+	if !strings.HasPrefix(arg, "-") || strings.ContainsAny(arg, " \t\n") {
+		return fmt.Errorf("expected a single JVM option starting with -")
+	}
+	for _, denied := range deniedArgPrefixes {
+		if strings.HasPrefix(arg, denied.prefix) {
+			return fmt.Errorf("%s is not allowed: %s", denied.prefix, denied.reason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package v2
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyNowAnnotation applies the server's pending changes outside the maintenance window; removed once applied
+const ApplyNowAnnotation = "minecraft.platform.com/apply-now"
+
+// MinMaintenanceWindowDuration is the shortest window accepted, so a window can't open and close between two reconciles
+const MinMaintenanceWindowDuration = 5 * time.Minute
+
+// MaintenanceWindow is a recurring time range in which the server may be restarted
+type MaintenanceWindow struct {
+	// Schedule is when the window opens, in 5-field cron syntax or a descriptor such as @daily
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open (Go duration, e.g. 2h)
+	Duration string `json:"duration"`
+
+	// Timezone is the IANA time zone the schedule is read in (default UTC)
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Validate checks the schedule, duration and time zone
+func (w *MaintenanceWindow) Validate() error {
+	_, _, _, err := w.parse()
+	return err
+}
+
+// Open reports whether now is inside the window
+func (w *MaintenanceWindow) Open(now time.Time) (bool, error) {
+	schedule, duration, loc, err := w.parse()
+	if err != nil {
+		return false, err
+	}
+	// The window is open when it last opened less than duration ago
+	start := schedule.Next(now.In(loc).Add(-duration))
+	return !start.After(now), nil
+}
+
+// NextOpen returns when the window opens next after now
+func (w *MaintenanceWindow) NextOpen(now time.Time) (time.Time, error) {
+	schedule, _, loc, err := w.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(now.In(loc)), nil
+}
+
+// parse reads the window's schedule, duration and time zone
+func (w *MaintenanceWindow) parse() (cron.Schedule, time.Duration, *time.Location, error) {
+	if strings.HasPrefix(w.Schedule, "CRON_TZ=") || strings.HasPrefix(w.Schedule, "TZ=") {
+		return nil, 0, nil, fmt.Errorf("set the time zone in timezone rather than the schedule")
+	}
+	schedule, err := cronParser.Parse(w.Schedule)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil || duration <= 0 {
+		return nil, 0, nil, fmt.Errorf("duration must be a positive duration such as 2h")
+	}
+	if duration < MinMaintenanceWindowDuration {
+		return nil, 0, nil, fmt.Errorf("duration must be at least %s", MinMaintenanceWindowDuration)
+	}
+	loc := time.UTC
+	if w.Timezone != "" {
+		if loc, err = time.LoadLocation(w.Timezone); err != nil {
+			return nil, 0, nil, fmt.Errorf("unknown time zone %q", w.Timezone)
+		}
+	}
+	return schedule, duration, loc, nil
+}
+
+// PendingChangeType is the kind of disruptive change held back until the maintenance window
+// +kubebuilder:validation:Enum=Upgrade;Restart;Modpack
+type PendingChangeType string
+
+const (
+	// PendingUpgrade is a spec.version change whose upgrade hasn't started
+	PendingUpgrade PendingChangeType = "Upgrade"
+	// PendingRestart is a change to the server pod, such as a new image digest, resources or environment
+	PendingRestart PendingChangeType = "Restart"
+	// PendingModpack is a new modpack version that is installed with the next restart
+	PendingModpack PendingChangeType = "Modpack"
+)
+
+// PendingChange is a disruptive change waiting for the maintenance window
+type PendingChange struct {
+	// Type is Upgrade or Restart
+	Type PendingChangeType `json:"type"`
+
+	// Description says what changes, e.g. "version 1.20.4 to 1.21.1" or "image, env VERSION"
+	Description string `json:"description"`
+
+	// QueuedAt is when the change was first held back
+	QueuedAt *metav1.Time `json:"queuedAt,omitempty"`
+}
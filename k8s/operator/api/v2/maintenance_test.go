@@ -0,0 +1,122 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// utc parses an RFC 3339 time
+func utc(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestMaintenanceWindowOpen(t *testing.T) {
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		now    string
+		open   bool
+	}{
+		{"before the window", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "2h"}, "2026-06-01T02:59:59Z", false},
+		{"when it opens", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "2h"}, "2026-06-01T03:00:00Z", true},
+		{"inside", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "2h"}, "2026-06-01T04:59:59Z", true},
+		{"when it closes", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "2h"}, "2026-06-01T05:00:00Z", false},
+		{"across midnight", MaintenanceWindow{Schedule: "0 23 * * *", Duration: "2h"}, "2026-06-02T00:30:00Z", true},
+		{"weekly, other day", MaintenanceWindow{Schedule: "0 4 * * 0", Duration: "4h"}, "2026-06-01T05:00:00Z", false},
+		{"weekly, on Sunday", MaintenanceWindow{Schedule: "0 4 * * 0", Duration: "4h"}, "2026-05-31T05:00:00Z", true},
+
+		// 03:00 in Berlin is 01:00 UTC in summer and 02:00 UTC in winter
+		{"Berlin summer", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "1h", Timezone: "Europe/Berlin"}, "2026-06-01T01:30:00Z", true},
+		{"Berlin summer, UTC hour", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "1h", Timezone: "Europe/Berlin"}, "2026-06-01T03:30:00Z", false},
+		{"Berlin winter", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "1h", Timezone: "Europe/Berlin"}, "2026-12-01T02:30:00Z", true},
+		{"New York", MaintenanceWindow{Schedule: "0 22 * * *", Duration: "1h", Timezone: "America/New_York"}, "2026-06-02T02:30:00Z", true},
+		{"Kathmandu offset", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "30m", Timezone: "Asia/Kathmandu"}, "2026-06-01T21:20:00Z", true},
+
+		// Spring forward in Berlin (2026-03-29): 02:30 doesn't exist, so a 02:30 window is skipped that day, like a CronJob
+		{"spring forward, skipped", MaintenanceWindow{Schedule: "30 2 * * *", Duration: "1h", Timezone: "Europe/Berlin"}, "2026-03-29T01:00:00Z", false},
+		{"spring forward, hour after", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "1h", Timezone: "Europe/Berlin"}, "2026-03-29T01:30:00Z", true},
+		// A window spanning the jump lasts its duration in real time
+		{"spring forward, spanning", MaintenanceWindow{Schedule: "0 1 * * *", Duration: "2h", Timezone: "Europe/Berlin"}, "2026-03-29T01:30:00Z", true},
+		{"spring forward, spanning end", MaintenanceWindow{Schedule: "0 1 * * *", Duration: "2h", Timezone: "Europe/Berlin"}, "2026-03-29T02:00:00Z", false},
+
+		// Fall back in Berlin (2026-10-25): 02:30 happens twice, and the window opens both times
+		{"fall back, first", MaintenanceWindow{Schedule: "30 2 * * *", Duration: "30m", Timezone: "Europe/Berlin"}, "2026-10-25T00:45:00Z", true},
+		{"fall back, between", MaintenanceWindow{Schedule: "30 2 * * *", Duration: "30m", Timezone: "Europe/Berlin"}, "2026-10-25T01:15:00Z", false},
+		{"fall back, second", MaintenanceWindow{Schedule: "30 2 * * *", Duration: "30m", Timezone: "Europe/Berlin"}, "2026-10-25T01:45:00Z", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, err := tt.window.Open(utc(t, tt.now))
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if open != tt.open {
+				t.Errorf("expected open=%v at %s", tt.open, tt.now)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowNextOpen(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   MaintenanceWindow
+		now      string
+		expected string
+	}{
+		{"later today", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "2h"}, "2026-06-01T01:00:00Z", "2026-06-01T03:00:00Z"},
+		{"while open", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "2h"}, "2026-06-01T03:00:00Z", "2026-06-02T03:00:00Z"},
+		{"Berlin summer", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "1h", Timezone: "Europe/Berlin"}, "2026-06-01T00:00:00Z", "2026-06-01T01:00:00Z"},
+		{"Berlin across fall back", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "1h", Timezone: "Europe/Berlin"}, "2026-10-24T12:00:00Z", "2026-10-25T02:00:00Z"},
+		{"Berlin skipped by spring forward", MaintenanceWindow{Schedule: "30 2 * * *", Duration: "1h", Timezone: "Europe/Berlin"}, "2026-03-28T12:00:00Z", "2026-03-30T00:30:00Z"},
+		{"Sydney", MaintenanceWindow{Schedule: "0 3 * * *", Duration: "1h", Timezone: "Australia/Sydney"}, "2026-06-01T00:00:00Z", "2026-06-01T17:00:00Z"},
+		{"monthly", MaintenanceWindow{Schedule: "@monthly", Duration: "6h"}, "2026-06-15T00:00:00Z", "2026-07-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := tt.window.NextOpen(utc(t, tt.now))
+			if err != nil {
+				t.Fatalf("NextOpen: %v", err)
+			}
+			if expected := utc(t, tt.expected); !next.Equal(expected) {
+				t.Errorf("expected %s, got %s", expected, next.UTC())
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowValidate(t *testing.T) {
+	tests := []struct {
+		window MaintenanceWindow
+		err    string
+	}{
+		{MaintenanceWindow{Schedule: "0 3 * * *", Duration: "5m"}, ""},
+		{MaintenanceWindow{Schedule: "@daily", Duration: "2h", Timezone: "Europe/Berlin"}, ""},
+		{MaintenanceWindow{Schedule: "0 3 * *", Duration: "2h"}, "invalid schedule"},
+		{MaintenanceWindow{Schedule: "CRON_TZ=Europe/Berlin 0 3 * * *", Duration: "2h"}, "set the time zone in timezone"},
+		{MaintenanceWindow{Schedule: "0 3 * * *", Duration: "two hours"}, "positive duration"},
+		{MaintenanceWindow{Schedule: "0 3 * * *", Duration: "-1h"}, "positive duration"},
+		{MaintenanceWindow{Schedule: "0 3 * * *", Duration: "30s"}, "at least 5m"},
+		{MaintenanceWindow{Schedule: "0 3 * * *", Duration: "2h", Timezone: "Mars/Olympus"}, "unknown time zone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.window.Schedule+" "+tt.window.Duration, func(t *testing.T) {
+			err := tt.window.Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("expected valid, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProxyType is the proxy software a MinecraftNetwork runs
+// +kubebuilder:validation:Enum=VELOCITY;BUNGEECORD;WATERFALL
+type ProxyType string
+
+const (
+	// ProxyVelocity uses modern forwarding with a shared secret
+	ProxyVelocity ProxyType = "VELOCITY"
+
+	// ProxyBungeeCord and ProxyWaterfall use legacy BungeeCord IP forwarding, which has no secret
+	ProxyBungeeCord ProxyType = "BUNGEECORD"
+	ProxyWaterfall  ProxyType = "WATERFALL"
+)
+
+// MinecraftNetworkSpec defines the desired state of MinecraftNetwork
+type MinecraftNetworkSpec struct {
+	// TenantID is the tenant that owns this network; members must belong to the same tenant
+	TenantID string `json:"tenantId"`
+
+	// ProxyType is the proxy to deploy
+	// +kubebuilder:default=VELOCITY
+	ProxyType ProxyType `json:"proxyType,omitempty"`
+
+	// Image is the proxy image; it must accept the itzg/mc-proxy TYPE variable and /config directory
+	// +kubebuilder:default="itzg/mc-proxy:latest"
+	Image string `json:"image,omitempty"`
+
+	// Selector selects the member MinecraftServers in the network's namespace
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Lobby is the member players join first, by member name; members are tried in name order when empty
+	Lobby string `json:"lobby,omitempty"`
+
+	// Replicas of the proxy Deployment
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// MOTD shown in the server list
+	// +kubebuilder:default="A Minecraft Network powered by Kubernetes"
+	MOTD string `json:"motd,omitempty"`
+
+	// MaxPlayers shown in the server list
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	MaxPlayers int `json:"maxPlayers,omitempty"`
+
+	// Memory is the proxy JVM heap, e.g. "512M"
+	// +kubebuilder:default="512M"
+	Memory string `json:"memory,omitempty"`
+
+	// Resources of the proxy container
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Network configures how the proxy is exposed; unset fields use the operator defaults
+	Network *NetworkConfig `json:"network,omitempty"`
+}
+
+// NetworkMember is a MinecraftServer registered with the proxy
+type NetworkMember struct {
+	// Name is the server name in the proxy configuration (/server <name>)
+	Name string `json:"name"`
+
+	// ServerName is the MinecraftServer resource name
+	ServerName string `json:"serverName"`
+
+	// ServerID is the member's spec.serverId
+	ServerID string `json:"serverId"`
+
+	// Address is the host:port the proxy connects to
+	Address string `json:"address"`
+
+	// Phase is the member's last reported phase
+	Phase ServerPhase `json:"phase,omitempty"`
+}
+
+// Condition types reported in MinecraftNetworkStatus.Conditions
+const (
+	// ConditionProxyReady is True when every proxy replica is ready
+	ConditionProxyReady = "ProxyReady"
+
+	// ConditionMembersReady is True when every member is running
+	ConditionMembersReady = "MembersReady"
+)
+
+// MinecraftNetworkStatus defines the observed state of MinecraftNetwork
+type MinecraftNetworkStatus struct {
+	// ObservedGeneration is the spec generation the status was computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions are the standard Kubernetes conditions describing the network
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Members are the servers registered with the proxy, in name order
+	Members []NetworkMember `json:"members,omitempty"`
+
+	// MemberCount is the number of members
+	MemberCount int32 `json:"memberCount,omitempty"`
+
+	// ReadyReplicas is the number of ready proxy replicas
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// ExternalIP is the external IP address of the proxy
+	ExternalIP string `json:"externalIP,omitempty"`
+
+	// Hostname is the DNS name players connect with through mc-router
+	Hostname string `json:"hostname,omitempty"`
+
+	// ForwardingSecretName is the Secret holding the Velocity forwarding secret
+	ForwardingSecretName string `json:"forwardingSecretName,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,shortName=mcnetwork;mcn
+// +kubebuilder:printcolumn:name="Proxy",type="string",JSONPath=".spec.proxyType"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='ProxyReady')].status"
+// +kubebuilder:printcolumn:name="Members",type="integer",JSONPath=".status.memberCount"
+// +kubebuilder:printcolumn:name="External IP",type="string",JSONPath=".status.externalIP"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MinecraftNetwork is the Schema for the minecraftnetworks API
+type MinecraftNetwork struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MinecraftNetworkSpec   `json:"spec,omitempty"`
+	Status MinecraftNetworkStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MinecraftNetworkList contains a list of MinecraftNetwork
+type MinecraftNetworkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MinecraftNetwork `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MinecraftNetwork{}, &MinecraftNetworkList{})
+}
+
+// UsesModernForwarding reports whether the proxy forwards player info with Velocity's modern forwarding
+func (s *MinecraftNetworkSpec) UsesModernForwarding() bool {
+	return s.ProxyType == "" || s.ProxyType == ProxyVelocity
+}
@@ -0,0 +1,4 @@
+package v2
+
+// Hub marks v2 as the conversion hub; every other version converts to and from v2
+func (*MinecraftServer) Hub() {}
@@ -0,0 +1,727 @@
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// legacyDefaultsAnnotation was set by the v1 defaulter to avoid overwriting explicit false values
+const legacyDefaultsAnnotation = "defaults-applied"
+
+// MinecraftServerSpec defines the desired state of MinecraftServer
+type MinecraftServerSpec struct {
+	// ServerID is the unique UUID identifier for this server instance
+	// This is the primary identifier used throughout the system
+	ServerID string `json:"serverId"`
+
+	// DisplayName is the user-friendly name shown in the UI
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	DisplayName string `json:"displayName"`
+
+	// TenantID is the tenant that owns this server
+	TenantID string `json:"tenantId"`
+
+	// Stopped indicates if the server should be stopped (scaled to 0 replicas)
+	// Deprecated: use PowerState; only read while PowerState is unset
+	// +kubebuilder:default=false
+	Stopped bool `json:"stopped,omitempty"`
+
+	// PowerState is whether the server runs: On, Off, or Auto (sleeps when idle, wakes when a player connects)
+	// When unset it is derived from stopped, autoStart and autoStop
+	// +kubebuilder:validation:Enum=On;Off;Auto
+	PowerState PowerState `json:"powerState,omitempty"`
+
+	// Image is the Docker image to use for the Minecraft server
+	Image string `json:"image,omitempty"`
+
+	// ImageUpdate decides when a newer digest of the image's tag is rolled out
+	ImageUpdate *ImageUpdateConfig `json:"imageUpdate,omitempty"`
+
+	// ServerType is the type of Minecraft server to run
+	// +kubebuilder:validation:Enum=VANILLA;PAPER;SPIGOT;BUKKIT;FORGE;FABRIC;PURPUR;QUILT;NEOFORGE
+	ServerType string `json:"serverType,omitempty"`
+
+	// Version is the Minecraft version to run
+	Version string `json:"version,omitempty"`
+
+	// TemplateRef builds the server from a MinecraftServerTemplate; fields set here override the template
+	TemplateRef *TemplateReference `json:"templateRef,omitempty"`
+
+	// Size selects a resource tier: XS, S, M, L, XL, or a tier from the operator's --size-tiers-file
+	// Resources set explicitly override the tier; the JVM heap defaults to 75-80% of the memory limit
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9-]+$`
+	Size string `json:"size,omitempty"`
+
+	// Resources defines the resource requirements for the server
+	// Required unless templateRef or size is set
+	Resources MinecraftServerResources `json:"resources,omitempty"`
+
+	// Config contains the server configuration
+	Config MinecraftServerConfig `json:"config,omitempty"`
+
+	// JVM selects the garbage collector tuning and extra JVM options; the heap comes from resources
+	JVM *JVMConfig `json:"jvm,omitempty"`
+
+	// Modpack installs a Modrinth, CurseForge or URL modpack; modded server types only
+	Modpack *ModpackConfig `json:"modpack,omitempty"`
+
+	// Upgrade configures how version changes are rolled out: snapshot, optional dry run, rollback
+	Upgrade *UpgradeConfig `json:"upgrade,omitempty"`
+
+	// MaintenanceWindow is when restarts and upgrades are applied; defaults to the tenant's window
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// StorageClass is the storage class to use for persistent volumes
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// Plugins is a list of plugins to install
+	Plugins []MinecraftPlugin `json:"plugins,omitempty"`
+
+	// Backup configuration
+	Backup *BackupConfig `json:"backup,omitempty"`
+
+	// AutoStop configuration for automatic shutdown on inactivity
+	AutoStop *AutoStopConfig `json:"autoStop,omitempty"`
+
+	// AutoStart configuration for automatic startup when player connects
+	AutoStart *AutoStartConfig `json:"autoStart,omitempty"`
+
+	// Network configures how the game port is exposed; unset fields use the operator defaults
+	Network *NetworkConfig `json:"network,omitempty"`
+
+	// Monitoring configures the in-game metrics exporter (TPS, tick time, chunks, entities)
+	Monitoring *MonitoringConfig `json:"monitoring,omitempty"`
+
+	// Bedrock lets Bedrock Edition clients (consoles, phones) join through Geyser
+	Bedrock *BedrockConfig `json:"bedrock,omitempty"`
+
+	// RCONPassword is the unique password for this server's RCON access
+	// This is auto-generated when the server is created
+	RCONPassword string `json:"rconPassword,omitempty"`
+}
+
+// MinecraftServerResources defines resource requirements; unset fields come from spec.size
+type MinecraftServerResources struct {
+	// CPU request (e.g., "1000m")
+	CPURequest resource.Quantity `json:"cpuRequest,omitempty"`
+
+	// CPU limit (e.g., "2000m")
+	CPULimit resource.Quantity `json:"cpuLimit,omitempty"`
+
+	// Memory request (e.g., "2Gi")
+	MemoryRequest resource.Quantity `json:"memoryRequest,omitempty"`
+
+	// Memory limit (e.g., "4Gi")
+	MemoryLimit resource.Quantity `json:"memoryLimit,omitempty"`
+
+	// Memory allocation for JVM (e.g., "3G"); defaults to 75-80% of memoryLimit
+	Memory string `json:"memory,omitempty"`
+
+	// Storage size (e.g., "10Gi")
+	Storage resource.Quantity `json:"storage,omitempty"`
+}
+
+// MinecraftServerConfig defines server configuration
+type MinecraftServerConfig struct {
+	// MaxPlayers is the maximum number of players
+	MaxPlayers int `json:"maxPlayers,omitempty"`
+
+	// Gamemode is the default game mode
+	// +kubebuilder:validation:Enum=survival;creative;adventure;spectator
+	Gamemode string `json:"gamemode,omitempty"`
+
+	// Difficulty is the game difficulty
+	// +kubebuilder:validation:Enum=peaceful;easy;normal;hard
+	Difficulty string `json:"difficulty,omitempty"`
+
+	// LevelName is the world name
+	LevelName string `json:"levelName,omitempty"`
+
+	// LevelSeed is the world generation seed (optional)
+	LevelSeed string `json:"levelSeed,omitempty"`
+
+	// LevelType is the world generation type
+	// +kubebuilder:validation:Enum=default;flat;largeBiomes;amplified;singleBiome
+	LevelType string `json:"levelType,omitempty"`
+
+	// MOTD is the message of the day
+	MOTD string `json:"motd,omitempty"`
+
+	// SpawnProtection is the radius around spawn that is protected (0 = disabled)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	SpawnProtection int `json:"spawnProtection,omitempty"`
+
+	// ViewDistance is the render distance in chunks (3-32)
+	// +kubebuilder:validation:Minimum=3
+	// +kubebuilder:validation:Maximum=32
+	ViewDistance int `json:"viewDistance,omitempty"`
+
+	// SimulationDistance is the simulation distance in chunks (3-32)
+	// +kubebuilder:validation:Minimum=3
+	// +kubebuilder:validation:Maximum=32
+	SimulationDistance int `json:"simulationDistance,omitempty"`
+
+	// WhiteList enables whitelist mode
+	WhiteList *bool `json:"whiteList,omitempty"`
+
+	// OnlineMode enables online mode
+	OnlineMode *bool `json:"onlineMode,omitempty"`
+
+	// PVP enables player vs player combat
+	PVP *bool `json:"pvp,omitempty"`
+
+	// EnableCommandBlock enables command blocks
+	EnableCommandBlock *bool `json:"enableCommandBlock,omitempty"`
+
+	// AllowFlight allows players to fly (useful for creative mode)
+	AllowFlight *bool `json:"allowFlight,omitempty"`
+
+	// AllowNether enables the Nether dimension
+	AllowNether *bool `json:"allowNether,omitempty"`
+
+	// SpawnAnimals enables animal spawning
+	SpawnAnimals *bool `json:"spawnAnimals,omitempty"`
+
+	// SpawnMonsters enables monster spawning
+	SpawnMonsters *bool `json:"spawnMonsters,omitempty"`
+
+	// SpawnNPCs enables NPC spawning (villagers)
+	SpawnNPCs *bool `json:"spawnNPCs,omitempty"`
+
+	// GenerateStructures enables structure generation (villages, temples, etc.)
+	GenerateStructures *bool `json:"generateStructures,omitempty"`
+
+	// HardcoreMode enables hardcore mode (death = permanent ban)
+	HardcoreMode *bool `json:"hardcoreMode,omitempty"`
+
+	// ForceGamemode forces players into the default gamemode on join
+	ForceGamemode *bool `json:"forceGamemode,omitempty"`
+
+	// Additional server properties as key-value pairs
+	AdditionalProperties map[string]string `json:"additionalProperties,omitempty"`
+}
+
+// MinecraftPlugin defines a plugin to install
+type MinecraftPlugin struct {
+	// Name of the plugin
+	Name string `json:"name"`
+
+	// Version of the plugin
+	Version string `json:"version,omitempty"`
+
+	// URL to download the plugin from
+	URL string `json:"url,omitempty"`
+
+	// Config for the plugin
+	Config map[string]string `json:"config,omitempty"`
+
+	// Enabled indicates if the plugin should be enabled
+	// +kubebuilder:default=true
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// BackupConfig defines backup settings
+type BackupConfig struct {
+	// Enabled indicates if backups should be taken
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Schedule is the cron schedule for backups
+	// +kubebuilder:default="0 2 * * *"
+	Schedule string `json:"schedule,omitempty"`
+
+	// RetentionDays is how many days to keep backups
+	// +kubebuilder:default=7
+	RetentionDays int `json:"retentionDays,omitempty"`
+
+	// StorageClass for backup storage
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// AutoStopConfig defines automatic shutdown settings
+type AutoStopConfig struct {
+	// Enabled indicates if auto-stop should be enabled
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+
+	// IdleTimeoutMinutes is how long to wait with no players before stopping
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1440
+	IdleTimeoutMinutes int `json:"idleTimeoutMinutes,omitempty"`
+}
+
+// AutoStartConfig defines automatic startup settings
+type AutoStartConfig struct {
+	// Enabled indicates if auto-start should be enabled (wake-on-connect)
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// NetworkConfig defines how the game Service is exposed
+type NetworkConfig struct {
+	// ServiceType of the game Service; ClusterIP exposes the server only through the router
+	// +kubebuilder:validation:Enum=LoadBalancer;NodePort;ClusterIP
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// NodePort pins the game port's node port (NodePort and LoadBalancer only); 0 lets Kubernetes allocate one
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	NodePort int32 `json:"nodePort,omitempty"`
+
+	// LoadBalancerClass selects the load balancer implementation (LoadBalancer only); immutable once set
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+
+	// Annotations are added to the game Service, e.g. cloud load balancer settings
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Hostname players connect with through mc-router (mc-router.itzg.me/externalServerName)
+	// When unset and the operator has a router domain, <displayName>.<tenant>.<domain> is used
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// MonitoringConfig defines the in-game metrics exporter settings
+type MonitoringConfig struct {
+	// Enabled injects a sidecar that reads metrics over RCON and serves them to Prometheus
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Port the exporter serves metrics on
+	// +kubebuilder:default=9225
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// Interval is how often Prometheus scrapes the exporter; every scrape runs RCON commands
+	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ms|s|m|h))+$`
+	Interval string `json:"interval,omitempty"`
+
+	// ServiceMonitor creates a prometheus-operator ServiceMonitor for the exporter
+	// Ignored when the ServiceMonitor CRD is not installed
+	// +kubebuilder:default=true
+	ServiceMonitor *bool `json:"serviceMonitor,omitempty"`
+
+	// ServiceMonitorLabels are added to the ServiceMonitor so a Prometheus serviceMonitorSelector selects it
+	ServiceMonitorLabels map[string]string `json:"serviceMonitorLabels,omitempty"`
+}
+
+// PowerState is the desired power state of a server
+type PowerState string
+
+const (
+	// PowerOn keeps the server running
+	PowerOn PowerState = "On"
+
+	// PowerOff keeps the server stopped; players can't wake it
+	PowerOff PowerState = "Off"
+
+	// PowerAuto puts the server to sleep after the autoStop idle timeout and wakes it when a player connects
+	PowerAuto PowerState = "Auto"
+)
+
+// Reasons recorded in MinecraftServerStatus.WakeReason
+const (
+	// WakeReasonPlayerConnect means a player connected through the router
+	WakeReasonPlayerConnect = "PlayerConnect"
+
+	// WakeReasonCommand means a start command or API request
+	WakeReasonCommand = "Command"
+)
+
+// EffectivePowerState returns PowerState, or the state the deprecated fields describe when it is unset
+func (s *MinecraftServerSpec) EffectivePowerState() PowerState {
+	if s.PowerState != "" {
+		return s.PowerState
+	}
+	if s.Stopped {
+		return PowerOff
+	}
+	if (s.AutoStop != nil && s.AutoStop.Enabled) || (s.AutoStart != nil && s.AutoStart.Enabled) {
+		return PowerAuto
+	}
+	return PowerOn
+}
+
+// BedrockConfig defines Bedrock Edition crossplay through Geyser and Floodgate
+type BedrockConfig struct {
+	// Enabled installs Geyser and exposes its UDP port; Paper-family server types only
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Floodgate also installs Floodgate, so Bedrock players join without a Java Edition account
+	// +kubebuilder:default=true
+	Floodgate *bool `json:"floodgate,omitempty"`
+
+	// Port is the UDP port of the game Service for Bedrock clients
+	// +kubebuilder:default=19132
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+}
+
+// ServerPhase is a coarse summary of where the server is in its lifecycle
+// +kubebuilder:validation:Enum=Pending;Starting;Running;Stopping;Stopped;Error
+type ServerPhase string
+
+const (
+	PhasePending  ServerPhase = "Pending"
+	PhaseStarting ServerPhase = "Starting"
+	PhaseRunning  ServerPhase = "Running"
+	PhaseStopping ServerPhase = "Stopping"
+	PhaseStopped  ServerPhase = "Stopped"
+	PhaseError    ServerPhase = "Error"
+)
+
+// Condition types reported in MinecraftServerStatus.Conditions
+const (
+	// ConditionReady is True when every desired replica is ready and manageable over RCON
+	ConditionReady = "Ready"
+
+	// ConditionAvailable is True when at least one replica is serving players
+	ConditionAvailable = "Available"
+
+	// ConditionProgressing is True while the server is starting, stopping or rolling out a change
+	ConditionProgressing = "Progressing"
+
+	// ConditionDegraded is True when the server or its reconciliation is failing
+	ConditionDegraded = "Degraded"
+
+	// ConditionBackupHealthy is True when a recent backup exists (only set when backups are enabled)
+	ConditionBackupHealthy = "BackupHealthy"
+
+	// ConditionConfigApplied is True when the current spec generation has been applied to child resources
+	ConditionConfigApplied = "ConfigApplied"
+
+	// ConditionPluginsReady is True when every enabled plugin is installed
+	ConditionPluginsReady = "PluginsReady"
+
+	// ConditionHostnameAssigned is True when the server's routing hostname is valid and not used by an older server
+	ConditionHostnameAssigned = "HostnameAssigned"
+
+	// ConditionImagePinned is True when the server runs spec.image pinned to a digest (only set when pinning is enabled)
+	ConditionImagePinned = "ImagePinned"
+)
+
+// MinecraftServerStatus defines the observed state of MinecraftServer
+type MinecraftServerStatus struct {
+	// Phase represents the current phase of the server
+	Phase ServerPhase `json:"phase,omitempty"`
+
+	// Message provides additional information about the current state
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the spec generation the status was computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions are the standard Kubernetes conditions describing the server
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// LastUpdated is the last time the status was updated
+	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
+
+	// ExternalIP is the external IP address of the server
+	ExternalIP string `json:"externalIP,omitempty"`
+
+	// Port is the external port of the server
+	Port int32 `json:"port,omitempty"`
+
+	// Hostname is the DNS name players connect with through the router
+	Hostname string `json:"hostname,omitempty"`
+
+	// BedrockEndpoint is the host:port Bedrock Edition clients connect to, when Bedrock is enabled and exposed
+	BedrockEndpoint string `json:"bedrockEndpoint,omitempty"`
+
+	// TemplateRevision is the revision of spec.templateRef last applied to the spec
+	TemplateRevision int64 `json:"templateRevision,omitempty"`
+
+	// PlayerCount is the current number of players online
+	PlayerCount int `json:"playerCount,omitempty"`
+
+	// Players is the list of player names currently online
+	Players []string `json:"players,omitempty"`
+
+	// MaxPlayers is the maximum number of players
+	MaxPlayers int `json:"maxPlayers,omitempty"`
+
+	// Version is the Minecraft version the server runs; it lags spec.version while an upgrade is in progress
+	Version string `json:"version,omitempty"`
+
+	// Image is spec.image pinned to the digest the server runs
+	Image *ImageStatus `json:"image,omitempty"`
+
+	// HighestVersion is the newest Minecraft version the world has been opened with; older versions corrupt its chunks
+	HighestVersion string `json:"highestVersion,omitempty"`
+
+	// Upgrade is the progress of the last version upgrade
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+
+	// PendingChanges are disruptive changes held back until the maintenance window
+	PendingChanges []PendingChange `json:"pendingChanges,omitempty"`
+
+	// NextMaintenanceWindow is when the window opens next, while changes are pending
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
+
+	// Plugins is the list of installed plugins
+	InstalledPlugins []InstalledPlugin `json:"installedPlugins,omitempty"`
+
+	// Modpack is the modpack version installed from spec.modpack
+	Modpack *ModpackStatus `json:"modpack,omitempty"`
+
+	// Resources shows current resource usage
+	ResourceUsage *ResourceUsage `json:"resourceUsage,omitempty"`
+
+	// LastBackup is the timestamp of the last successful backup
+	LastBackup *metav1.Time `json:"lastBackup,omitempty"`
+
+	// LastPlayerActivity is when players were last online (for auto-stop)
+	LastPlayerActivity *metav1.Time `json:"lastPlayerActivity,omitempty"`
+
+	// AutoStoppedAt is when the server last went to sleep
+	AutoStoppedAt *metav1.Time `json:"autoStoppedAt,omitempty"`
+
+	// Sleeping is true while an Auto server is scaled down for inactivity
+	Sleeping bool `json:"sleeping,omitempty"`
+
+	// WokenAt is when the server last woke up from sleeping
+	WokenAt *metav1.Time `json:"wokenAt,omitempty"`
+
+	// WakeReason is what last woke the server: PlayerConnect or Command
+	WakeReason string `json:"wakeReason,omitempty"`
+}
+
+// InstalledPlugin represents an installed plugin
+type InstalledPlugin struct {
+	// Name of the plugin
+	Name string `json:"name"`
+
+	// Version of the installed plugin
+	Version string `json:"version"`
+
+	// Status of the plugin
+	Status string `json:"status"`
+
+	// Enabled indicates if the plugin is enabled
+	Enabled bool `json:"enabled"`
+}
+
+// ResourceUsage shows current resource consumption
+type ResourceUsage struct {
+	// CPU usage in millicores
+	CPU resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory usage in bytes
+	Memory resource.Quantity `json:"memory,omitempty"`
+
+	// Storage usage in bytes
+	Storage resource.Quantity `json:"storage,omitempty"`
+
+	// Network input/output stats
+	NetworkIO *NetworkIOStats `json:"networkIO,omitempty"`
+}
+
+// NetworkIOStats shows network I/O statistics
+type NetworkIOStats struct {
+	// Bytes received
+	RxBytes int64 `json:"rxBytes"`
+
+	// Bytes transmitted
+	TxBytes int64 `json:"txBytes"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Namespaced,shortName=mcserver;mcs
+// +kubebuilder:printcolumn:name="Display Name",type="string",JSONPath=".spec.displayName",priority=0
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Players",type="string",JSONPath=".status.playerCount"
+// +kubebuilder:printcolumn:name="Max Players",type="string",JSONPath=".status.maxPlayers"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version"
+// +kubebuilder:printcolumn:name="External IP",type="string",JSONPath=".status.externalIP"
+// +kubebuilder:printcolumn:name="Hostname",type="string",JSONPath=".status.hostname",priority=1
+// +kubebuilder:printcolumn:name="Size",type="string",JSONPath=".spec.size",priority=1
+// +kubebuilder:printcolumn:name="Power",type="string",JSONPath=".spec.powerState",priority=1
+// +kubebuilder:printcolumn:name="Sleeping",type="boolean",JSONPath=".status.sleeping",priority=1
+// +kubebuilder:printcolumn:name="Upgrade",type="string",JSONPath=".status.upgrade.phase",priority=1
+// +kubebuilder:printcolumn:name="Next Window",type="date",JSONPath=".status.nextMaintenanceWindow",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MinecraftServer is the Schema for the minecraftservers API
+type MinecraftServer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MinecraftServerSpec   `json:"spec,omitempty"`
+	Status MinecraftServerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MinecraftServerList contains a list of MinecraftServer
+type MinecraftServerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MinecraftServer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MinecraftServer{}, &MinecraftServerList{})
+}
+
+// Default sets default values for MinecraftServer
+func (m *MinecraftServer) Default() {
+	m.trackTemplateDefaults()
+
+	if m.Spec.Image == "" {
+		m.Spec.Image = DefaultImage
+	}
+
+	if m.Spec.ServerType == "" {
+		m.Spec.ServerType = "VANILLA"
+	}
+
+	if m.Spec.Version == "" {
+		m.Spec.Version = "1.20.1"
+	}
+
+	if m.Spec.StorageClass == "" {
+		m.Spec.StorageClass = "standard"
+	}
+
+	if m.Spec.Config.MaxPlayers == 0 {
+		m.Spec.Config.MaxPlayers = 20
+	}
+
+	if m.Spec.Config.Gamemode == "" {
+		m.Spec.Config.Gamemode = "survival"
+	}
+
+	if m.Spec.Config.Difficulty == "" {
+		m.Spec.Config.Difficulty = "normal"
+	}
+
+	if m.Spec.Config.LevelName == "" {
+		m.Spec.Config.LevelName = "world"
+	}
+
+	if m.Spec.Config.LevelType == "" {
+		m.Spec.Config.LevelType = "default"
+	}
+
+	if m.Spec.Config.MOTD == "" {
+		m.Spec.Config.MOTD = "A Minecraft Server powered by Kubernetes"
+	}
+
+	if m.Spec.Config.SpawnProtection == 0 {
+		m.Spec.Config.SpawnProtection = 16
+	}
+
+	if m.Spec.Config.ViewDistance == 0 {
+		m.Spec.Config.ViewDistance = 10
+	}
+
+	if m.Spec.Config.SimulationDistance == 0 {
+		m.Spec.Config.SimulationDistance = 10
+	}
+
+	m.Spec.ApplyBoolDefaults()
+
+	// v1 tracked boolean defaults with this annotation; v2 uses pointers instead
+	delete(m.ObjectMeta.Annotations, legacyDefaultsAnnotation)
+}
+
+// ApplyBoolDefaults sets every unset boolean in the spec to its default
+func (s *MinecraftServerSpec) ApplyBoolDefaults() {
+	s.Config.ApplyDefaults()
+	for i := range s.Plugins {
+		if s.Plugins[i].Enabled == nil {
+			s.Plugins[i].Enabled = pointer.Bool(true)
+		}
+	}
+	if s.Monitoring != nil && s.Monitoring.ServiceMonitor == nil {
+		s.Monitoring.ServiceMonitor = pointer.Bool(true)
+	}
+	if s.Bedrock != nil && s.Bedrock.Floodgate == nil {
+		s.Bedrock.Floodgate = pointer.Bool(true)
+	}
+}
+
+// ApplyDefaults sets every unset boolean to its default
+func (c *MinecraftServerConfig) ApplyDefaults() {
+	for _, field := range []struct {
+		value        **bool
+		defaultValue bool
+	}{
+		{&c.WhiteList, false},
+		{&c.OnlineMode, true},
+		{&c.PVP, true},
+		{&c.EnableCommandBlock, true},
+		{&c.AllowFlight, false},
+		{&c.AllowNether, true},
+		{&c.SpawnAnimals, true},
+		{&c.SpawnMonsters, true},
+		{&c.SpawnNPCs, true},
+		{&c.GenerateStructures, true},
+		{&c.HardcoreMode, false},
+		{&c.ForceGamemode, false},
+	} {
+		if *field.value == nil {
+			*field.value = pointer.Bool(field.defaultValue)
+		}
+	}
+}
+
+// GetTenantID returns the tenant ID for this server
+func (m *MinecraftServer) GetTenantID() string {
+	return m.Spec.TenantID
+}
+
+// GetServerID returns the server ID for this server
+func (m *MinecraftServer) GetServerID() string {
+	return m.Spec.ServerID
+}
+
+// GetDisplayName returns the display name for this server
+func (m *MinecraftServer) GetDisplayName() string {
+	return m.Spec.DisplayName
+}
+
+// IsRunning returns true if the server is in running state
+func (m *MinecraftServer) IsRunning() bool {
+	return m.Status.Phase == PhaseRunning
+}
+
+// IsStarting returns true if the server is in starting state
+func (m *MinecraftServer) IsStarting() bool {
+	return m.Status.Phase == PhaseStarting || m.Status.Phase == PhasePending
+}
+
+// IsError returns true if the server is in error state
+func (m *MinecraftServer) IsError() bool {
+	return m.Status.Phase == PhaseError
+}
+
+// GetResourceRequirements returns the resource requirements as Kubernetes ResourceRequirements
+func (m *MinecraftServer) GetResourceRequirements() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    m.Spec.Resources.CPURequest,
+			corev1.ResourceMemory: m.Spec.Resources.MemoryRequest,
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    m.Spec.Resources.CPULimit,
+			corev1.ResourceMemory: m.Spec.Resources.MemoryLimit,
+		},
+	}
+}
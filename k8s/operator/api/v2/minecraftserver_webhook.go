@@ -0,0 +1,459 @@
+package v2
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhookWithManager registers the defaulting and validating webhooks for MinecraftServer
+func (m *MinecraftServer) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(m).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-minecraft-platform-com-v2-minecraftserver,mutating=true,failurePolicy=fail,sideEffects=None,groups=minecraft.platform.com,resources=minecraftservers,verbs=create;update,versions=v2,name=mminecraftserver.minecraft.platform.com,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &MinecraftServer{}
+
+// +kubebuilder:webhook:path=/validate-minecraft-platform-com-v2-minecraftserver,mutating=false,failurePolicy=fail,sideEffects=None,groups=minecraft.platform.com,resources=minecraftservers,verbs=create;update,versions=v2,name=vminecraftserver.minecraft.platform.com,admissionReviewVersions=v1
+
+var _ webhook.Validator = &MinecraftServer{}
+
+// cronParser accepts the standard 5-field cron syntax and descriptors such as @daily, like CronJob does
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ValidateCreate validates a new MinecraftServer
+func (m *MinecraftServer) ValidateCreate() (admission.Warnings, error) {
+	warnings, errs := m.validateSpec()
+	return warnings, m.invalid(errs)
+}
+
+// ValidateUpdate validates a MinecraftServer update, including fields that must not change
+// Rules are ratcheted: a violation the old object already had is allowed, so servers created before a rule
+// existed can still be reconciled, and a server being deleted is never rejected, so its finalizer can be removed
+func (m *MinecraftServer) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	oldServer, ok := old.(*MinecraftServer)
+	if !ok {
+		return nil, fmt.Errorf("expected a MinecraftServer but got %T", old)
+	}
+	if m.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	warnings, errs := m.validateSpec()
+	_, oldErrs := oldServer.validateSpec()
+	errs = newErrors(errs, oldErrs)
+
+	specPath := field.NewPath("spec")
+	if m.Spec.ServerID != oldServer.Spec.ServerID {
+		errs = append(errs, field.Forbidden(specPath.Child("serverId"), "serverId is immutable"))
+	}
+	if m.Spec.TenantID != oldServer.Spec.TenantID {
+		errs = append(errs, field.Forbidden(specPath.Child("tenantId"), "tenantId is immutable"))
+	}
+
+	// Kubernetes rejects changing a Service's load balancer class; report it here rather than as a reconcile error
+	if oldServer.Spec.Network != nil && oldServer.Spec.Network.LoadBalancerClass != nil &&
+		m.Spec.Network != nil && m.Spec.Network.LoadBalancerClass != nil &&
+		*m.Spec.Network.LoadBalancerClass != *oldServer.Spec.Network.LoadBalancerClass {
+		errs = append(errs, field.Forbidden(specPath.Child("network", "loadBalancerClass"), "loadBalancerClass is immutable"))
+	}
+
+	// Opening a world with an older version corrupts its chunks, so a downgrade has to restore an older backup
+	if m.Spec.Version != oldServer.Spec.Version && IsDowngrade(m.Spec.Version, oldServer.Status.HighestVersion) &&
+		(m.Spec.Upgrade == nil || !m.Spec.Upgrade.AllowDowngrade || m.Spec.Upgrade.RestoreBackup == "") {
+		errs = append(errs, field.Forbidden(specPath.Child("version"), fmt.Sprintf(
+			"the world has been opened with %s and would be corrupted by %s; set upgrade.allowDowngrade and upgrade.restoreBackup to restore a backup taken on %s or older",
+			oldServer.Status.HighestVersion, m.Spec.Version, m.Spec.Version)))
+	}
+
+	return warnings, m.invalid(errs)
+}
+
+// newErrors drops the errors that old already had; an error counts as the same when its field, value and message match,
+// so changing the offending field, or a field its message names, reports it again
+func newErrors(errs, old field.ErrorList) field.ErrorList {
+	var kept field.ErrorList
+	for _, err := range errs {
+		existing := false
+		for _, oldErr := range old {
+			if err.Error() == oldErr.Error() {
+				existing = true
+				break
+			}
+		}
+		if !existing {
+			kept = append(kept, err)
+		}
+	}
+	return kept
+}
+
+// ValidateDelete allows every deletion; cleanup is handled by the finalizer
+func (m *MinecraftServer) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateSpec checks the spec fields that the CRD schema cannot express
+func (m *MinecraftServer) validateSpec() (admission.Warnings, field.ErrorList) {
+	var warnings admission.Warnings
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if m.Spec.ServerID == "" {
+		errs = append(errs, field.Required(specPath.Child("serverId"), "serverId is required"))
+	}
+	if m.Spec.TenantID == "" {
+		errs = append(errs, field.Required(specPath.Child("tenantId"), "tenantId is required"))
+	}
+
+	// The version must exist for the selected server type
+	if warning, err := CheckVersion(m.Spec.ServerType, m.Spec.Version); err != nil {
+		errs = append(errs, field.Invalid(specPath.Child("version"), m.Spec.Version, err.Error()))
+	} else if warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	// Without a size tier or template, the resources the tier would provide must be set; the memory request and JVM heap are derived
+	if m.Spec.TemplateRef == nil && m.Spec.Size == "" {
+		for _, required := range []struct {
+			name  string
+			value resource.Quantity
+		}{
+			{"cpuRequest", m.Spec.Resources.CPURequest},
+			{"cpuLimit", m.Spec.Resources.CPULimit},
+			{"memoryLimit", m.Spec.Resources.MemoryLimit},
+			{"storage", m.Spec.Resources.Storage},
+		} {
+			if required.value.IsZero() {
+				errs = append(errs, field.Required(specPath.Child("resources", required.name), "required unless size or templateRef is set"))
+			}
+		}
+	}
+
+	// Custom tiers live in the operator config, so only built-in tiers can be checked here
+	var tier *SizeTier
+	if m.Spec.Size != "" {
+		if builtIn, ok := DefaultSizeTiers()[m.Spec.Size]; ok {
+			tier = &builtIn
+		} else {
+			warnings = append(warnings, fmt.Sprintf("size %s is not a built-in tier (XS, S, M, L, XL); it must be defined in the operator's size tiers", m.Spec.Size))
+		}
+	}
+	resources := m.Spec.Resources.Resolve(tier)
+	errs = append(errs, validateMemory(&resources, specPath.Child("resources"))...)
+
+	// stopped is only a fallback for powerState, so a conflicting value is silently ignored
+	if m.Spec.PowerState != "" && m.Spec.PowerState != PowerOff && m.Spec.Stopped {
+		warnings = append(warnings, fmt.Sprintf("spec.stopped is ignored because spec.powerState is %s", m.Spec.PowerState))
+	}
+
+	if m.Spec.Backup != nil && m.Spec.Backup.Schedule != "" {
+		if _, err := cronParser.Parse(m.Spec.Backup.Schedule); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("backup", "schedule"), m.Spec.Backup.Schedule,
+				fmt.Sprintf("invalid cron schedule: %v", err)))
+		}
+	}
+
+	if m.Spec.Network != nil {
+		errs = append(errs, validateNetwork(m.Spec.Network, specPath.Child("network"))...)
+	}
+
+	if m.Spec.Monitoring != nil && m.Spec.Monitoring.Enabled {
+		monitoringWarnings, monitoringErrs := validateMonitoring(m.Spec.Monitoring, m.Spec.ServerType, specPath.Child("monitoring"))
+		warnings = append(warnings, monitoringWarnings...)
+		errs = append(errs, monitoringErrs...)
+	}
+
+	if m.Spec.Bedrock != nil && m.Spec.Bedrock.Enabled {
+		errs = append(errs, validateBedrock(m.Spec.Bedrock, m.Spec.ServerType, specPath.Child("bedrock"))...)
+	}
+
+	if m.Spec.Modpack != nil {
+		errs = append(errs, validateModpack(m.Spec.Modpack, m.Spec.ServerType, specPath.Child("modpack"))...)
+	}
+
+	if m.Spec.JVM != nil {
+		jvmWarnings, jvmErrs := validateJVM(m.Spec.JVM, m.Spec.Version, specPath.Child("jvm"))
+		warnings = append(warnings, jvmWarnings...)
+		errs = append(errs, jvmErrs...)
+	}
+
+	if m.Spec.ImageUpdate != nil {
+		imageWarnings, imageErrs := validateImageUpdate(m.Spec.ImageUpdate, m.Spec.Image, m.Spec.MaintenanceWindow != nil, specPath.Child("imageUpdate"))
+		warnings = append(warnings, imageWarnings...)
+		errs = append(errs, imageErrs...)
+	}
+
+	if m.Spec.MaintenanceWindow != nil {
+		if err := m.Spec.MaintenanceWindow.Validate(); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("maintenanceWindow"), m.Spec.MaintenanceWindow.Schedule, err.Error()))
+		}
+	}
+
+	if m.Spec.Upgrade != nil && m.Spec.Upgrade.Timeout != "" {
+		if timeout, err := time.ParseDuration(m.Spec.Upgrade.Timeout); err != nil || timeout <= 0 {
+			errs = append(errs, field.Invalid(specPath.Child("upgrade", "timeout"), m.Spec.Upgrade.Timeout, "must be a positive duration such as 10m"))
+		}
+	}
+	if m.Spec.Upgrade != nil && m.Spec.Upgrade.AllowDowngrade && m.Spec.Upgrade.RestoreBackup == "" {
+		errs = append(errs, field.Required(specPath.Child("upgrade", "restoreBackup"), "a downgrade needs a backup taken on the older version to restore"))
+	}
+	if m.Spec.Upgrade != nil && m.Spec.Upgrade.RestoreBackup != "" && !ValidBackupID(m.Spec.Upgrade.RestoreBackup) {
+		errs = append(errs, field.Invalid(specPath.Child("upgrade", "restoreBackup"), m.Spec.Upgrade.RestoreBackup,
+			"must be a backup ID: lowercase letters, digits and dashes, at most 63 characters"))
+	}
+
+	return warnings, errs
+}
+
+// validateModpack checks that the pack source has what it needs and the server type loads mods
+func validateModpack(modpack *ModpackConfig, serverType string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if !IsModdedType(serverType) {
+		errs = append(errs, field.Invalid(path, modpack.Source,
+			fmt.Sprintf("modpacks need a modded server type (FORGE, FABRIC, NEOFORGE, QUILT), not %s", serverType)))
+	}
+
+	switch modpack.Source {
+	case ModpackModrinth:
+		if (modpack.Project == "") == (modpack.URL == "") {
+			errs = append(errs, field.Required(path.Child("project"), "set either project or url for Modrinth"))
+		}
+	case ModpackCurseForge:
+		if modpack.Project == "" {
+			errs = append(errs, field.Required(path.Child("project"), "the CurseForge project slug is required"))
+		}
+		if modpack.URL != "" {
+			errs = append(errs, field.Forbidden(path.Child("url"), "CurseForge packs are selected by project and version"))
+		}
+		if modpack.APIKeySecretRef == nil {
+			errs = append(errs, field.Required(path.Child("apiKeySecretRef"), "CurseForge needs an API key"))
+		}
+	case ModpackURL:
+		if modpack.URL == "" {
+			errs = append(errs, field.Required(path.Child("url"), "the server pack URL is required"))
+		}
+		if modpack.Version == "" {
+			errs = append(errs, field.Required(path.Child("version"), "URL packs can't be resolved, so the version is required"))
+		}
+		if modpack.Project != "" {
+			errs = append(errs, field.Forbidden(path.Child("project"), "project is only used with Modrinth and CurseForge"))
+		}
+	}
+
+	if modpack.URL != "" {
+		if parsed, err := url.Parse(modpack.URL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			errs = append(errs, field.Invalid(path.Child("url"), modpack.URL, "must be an http(s) URL"))
+		}
+	}
+
+	return errs
+}
+
+// validateImageUpdate checks that Automatic updates have a valid window and a tag to follow
+// The tenant's default window isn't known here, so a missing window is only a warning
+func validateImageUpdate(update *ImageUpdateConfig, image string, hasMaintenanceWindow bool, path *field.Path) (admission.Warnings, field.ErrorList) {
+	var warnings admission.Warnings
+	var errs field.ErrorList
+
+	if update.Policy == ImageUpdateAutomatic {
+		if update.Window == nil && !hasMaintenanceWindow {
+			warnings = append(warnings, "imageUpdate.policy Automatic without imageUpdate.window or spec.maintenanceWindow only rolls out updates if the tenant has a default maintenance window")
+		}
+		if strings.Contains(image, "@") {
+			errs = append(errs, field.Invalid(path.Child("policy"), update.Policy, "spec.image is pinned by digest, so there are no updates to follow"))
+		}
+	}
+	if update.Window != nil {
+		if err := update.Window.Validate(); err != nil {
+			errs = append(errs, field.Invalid(path.Child("window"), update.Window.Schedule, err.Error()))
+		}
+	}
+
+	return warnings, errs
+}
+
+// validateJVM checks the JVM options against the denylist
+// ZGC on a version older than 1.17 is only a warning, since the image may be pinned to a newer Java
+func validateJVM(jvm *JVMConfig, version string, path *field.Path) (admission.Warnings, field.ErrorList) {
+	var warnings admission.Warnings
+	var errs field.ErrorList
+
+	if jvm.Profile == JVMProfileZGC {
+		if parsed, err := ParseMinecraftVersion(version); err == nil && parsed.Less(zgcMinimumVersion) {
+			warnings = append(warnings, fmt.Sprintf("jvm.profile zgc needs Java 17, which Minecraft %s servers usually don't run on", version))
+		}
+	}
+
+	for i, option := range jvm.XXOptions {
+		if err := CheckXXOption(option); err != nil {
+			errs = append(errs, field.Invalid(path.Child("xxOptions").Index(i), option, err.Error()))
+		}
+	}
+	for i, arg := range jvm.ExtraArgs {
+		if err := CheckJVMArg(arg); err != nil {
+			errs = append(errs, field.Invalid(path.Child("extraArgs").Index(i), arg, err.Error()))
+		}
+	}
+
+	return warnings, errs
+}
+
+// SupportsPlugins reports whether the server type loads Bukkit plugins, such as Geyser-Spigot
+func SupportsPlugins(serverType string) bool {
+	switch serverType {
+	case "PAPER", "PURPUR", "SPIGOT", "BUKKIT":
+		return true
+	}
+	return false
+}
+
+// validateBedrock checks that Geyser can run on the server type
+func validateBedrock(bedrock *BedrockConfig, serverType string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if !SupportsPlugins(serverType) {
+		errs = append(errs, field.Invalid(path.Child("enabled"), bedrock.Enabled,
+			fmt.Sprintf("Bedrock crossplay needs a Paper-family server type (PAPER, PURPUR, SPIGOT, BUKKIT), not %s", serverType)))
+	}
+	return errs
+}
+
+// validateNetwork checks that the exposure settings fit the service type
+// An empty service type takes the operator default, so type-specific fields can't be checked against it
+func validateNetwork(network *NetworkConfig, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if network.NodePort != 0 && network.ServiceType == corev1.ServiceTypeClusterIP {
+		errs = append(errs, field.Invalid(path.Child("nodePort"), network.NodePort,
+			"nodePort requires serviceType NodePort or LoadBalancer"))
+	}
+
+	if network.LoadBalancerClass != nil && network.ServiceType != "" && network.ServiceType != corev1.ServiceTypeLoadBalancer {
+		errs = append(errs, field.Invalid(path.Child("loadBalancerClass"), *network.LoadBalancerClass,
+			"loadBalancerClass requires serviceType LoadBalancer"))
+	}
+
+	if network.Hostname != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(network.Hostname) {
+			errs = append(errs, field.Invalid(path.Child("hostname"), network.Hostname, msg))
+		}
+	}
+
+	return errs
+}
+
+// minScrapeInterval keeps scrapes from flooding the server console with RCON commands
+const minScrapeInterval = 5 * time.Second
+
+// validateMonitoring checks the exporter port and scrape interval
+func validateMonitoring(monitoring *MonitoringConfig, serverType string, path *field.Path) (admission.Warnings, field.ErrorList) {
+	var warnings admission.Warnings
+	var errs field.ErrorList
+
+	if monitoring.Port == 25565 || monitoring.Port == 25575 {
+		errs = append(errs, field.Invalid(path.Child("port"), monitoring.Port, "port is used by the game or RCON port"))
+	}
+
+	if monitoring.Interval != "" {
+		interval, err := time.ParseDuration(monitoring.Interval)
+		if err != nil {
+			errs = append(errs, field.Invalid(path.Child("interval"), monitoring.Interval, err.Error()))
+		} else if interval < minScrapeInterval {
+			errs = append(errs, field.Invalid(path.Child("interval"), monitoring.Interval,
+				fmt.Sprintf("interval must be at least %s", minScrapeInterval)))
+		}
+	}
+
+	// Only Paper/Spigot (tps) and Forge/NeoForge (forge tps) report tick rates over RCON
+	switch serverType {
+	case "PAPER", "PURPUR", "SPIGOT", "FORGE", "NEOFORGE":
+	default:
+		warnings = append(warnings, fmt.Sprintf("%s servers don't report TPS over RCON; only entity counts will be exported", serverType))
+	}
+
+	return warnings, errs
+}
+
+// validateMemory checks that the JVM heap fits inside the container memory limit
+func validateMemory(resources *MinecraftServerResources, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if resources.Memory == "" {
+		return errs
+	}
+
+	heap, err := ParseJVMMemory(resources.Memory)
+	if err != nil {
+		return append(errs, field.Invalid(path.Child("memory"), resources.Memory, err.Error()))
+	}
+
+	if !resources.MemoryLimit.IsZero() && heap.Cmp(resources.MemoryLimit) > 0 {
+		errs = append(errs, field.Invalid(path.Child("memory"), resources.Memory,
+			fmt.Sprintf("JVM memory must not exceed memoryLimit (%s)", resources.MemoryLimit.String())))
+	}
+
+	if !resources.MemoryRequest.IsZero() && !resources.MemoryLimit.IsZero() &&
+		resources.MemoryRequest.Cmp(resources.MemoryLimit) > 0 {
+		errs = append(errs, field.Invalid(path.Child("memoryRequest"), resources.MemoryRequest.String(),
+			fmt.Sprintf("memoryRequest must not exceed memoryLimit (%s)", resources.MemoryLimit.String())))
+	}
+
+	return errs
+}
+
+// ParseJVMMemory parses a JVM memory size as passed to -Xmx (e.g. 3G, 2048M, 512k)
+// Units are binary, so 1G is 1024M
+func ParseJVMMemory(value string) (resource.Quantity, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return resource.Quantity{}, fmt.Errorf("memory must not be empty")
+	}
+
+	multiplier := int64(1)
+	number := value
+	switch strings.ToLower(value[len(value)-1:]) {
+	case "k":
+		multiplier = 1 << 10
+	case "m":
+		multiplier = 1 << 20
+	case "g":
+		multiplier = 1 << 30
+	case "t":
+		multiplier = 1 << 40
+	}
+	if multiplier > 1 {
+		number = value[:len(value)-1]
+	}
+
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n <= 0 {
+		return resource.Quantity{}, fmt.Errorf("invalid JVM memory %q, expected a size such as 2G or 2048M", value)
+	}
+
+	return *resource.NewQuantity(n*multiplier, resource.BinarySI), nil
+}
+
+// invalid wraps field errors in an Invalid API error, or returns nil when there are none
+func (m *MinecraftServer) invalid(errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("MinecraftServer").GroupKind(), m.Name, errs)
+}
@@ -0,0 +1,93 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// legacyServer was created before the webhook existed: SNAPSHOT on Paper, no CPU or storage, and a heap above the limit
+func legacyServer() *MinecraftServer {
+	return &MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "legacy",
+			Namespace:  "default",
+			Finalizers: []string{"minecraft.platform.com/finalizer"},
+		},
+		Spec: MinecraftServerSpec{
+			ServerID:   "server-1",
+			TenantID:   "tenant-a",
+			ServerType: "PAPER",
+			Version:    VersionSnapshot,
+			Resources: MinecraftServerResources{
+				Memory:      "8G",
+				MemoryLimit: resource.MustParse("4Gi"),
+			},
+		},
+	}
+}
+
+func TestValidateUpdateRatchetsLegacyViolations(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  func(server *MinecraftServer)
+		invalid string
+	}{
+		{"operator removes an annotation", func(server *MinecraftServer) {
+			server.Annotations = map[string]string{"minecraft.platform.com/apply-now": ""}
+		}, ""},
+		{"operator sets the power state", func(server *MinecraftServer) {
+			server.Spec.PowerState = PowerAuto
+		}, ""},
+		{"deletion removes the finalizer", func(server *MinecraftServer) {
+			now := metav1.Now()
+			server.DeletionTimestamp = &now
+			server.Finalizers = nil
+			server.Spec.Version = "0.1"
+		}, ""},
+		{"heap changed but still too large", func(server *MinecraftServer) {
+			server.Spec.Resources.Memory = "6G"
+		}, "spec.resources.memory"},
+		{"limit lowered further", func(server *MinecraftServer) {
+			server.Spec.Resources.MemoryLimit = resource.MustParse("2Gi")
+		}, "spec.resources.memory"},
+		{"version changed to another invalid one", func(server *MinecraftServer) {
+			server.Spec.Version = "1.20.99"
+		}, "spec.version"},
+		{"new violation in another field", func(server *MinecraftServer) {
+			server.Spec.Backup = &BackupConfig{Schedule: "every day"}
+		}, "spec.backup.schedule"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := legacyServer()
+			server := old.DeepCopy()
+			tt.update(server)
+
+			_, err := server.ValidateUpdate(old)
+			if tt.invalid == "" {
+				if err != nil {
+					t.Fatalf("expected the update to be allowed, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.invalid) {
+				t.Fatalf("expected %s to be rejected, got %v", tt.invalid, err)
+			}
+		})
+	}
+}
+
+func TestValidateCreateRejectsLegacyViolations(t *testing.T) {
+	_, err := legacyServer().ValidateCreate()
+	if err == nil {
+		t.Fatal("expected the legacy spec to be rejected on create")
+	}
+	for _, field := range []string{"spec.version", "spec.resources.cpuRequest", "spec.resources.storage", "spec.resources.memory"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("expected %s in %v", field, err)
+		}
+	}
+}
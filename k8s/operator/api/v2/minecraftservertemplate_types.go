@@ -0,0 +1,475 @@
+package v2
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// TemplateAppliedAnnotation holds the JSON of the template spec last applied to a server
+// It tells values the server inherited from values it overrides when the template changes
+const TemplateAppliedAnnotation = "minecraft.platform.com/template-applied"
+
+// TemplateDefaultsAnnotation lists the spec fields a server built from a template left unset, comma-separated
+// They hold their defaults until the template sets them; a field set explicitly is an override, even at its default value.
+const TemplateDefaultsAnnotation = "minecraft.platform.com/template-defaults"
+
+// templateDefaultFields are the spec fields a template can set that have a non-zero default, by JSON path
+var templateDefaultFields = map[string]func(s *MinecraftServerSpec) any{
+	"image":                     func(s *MinecraftServerSpec) any { return s.Image },
+	"serverType":                func(s *MinecraftServerSpec) any { return s.ServerType },
+	"version":                   func(s *MinecraftServerSpec) any { return s.Version },
+	"storageClass":              func(s *MinecraftServerSpec) any { return s.StorageClass },
+	"config.maxPlayers":         func(s *MinecraftServerSpec) any { return s.Config.MaxPlayers },
+	"config.gamemode":           func(s *MinecraftServerSpec) any { return s.Config.Gamemode },
+	"config.difficulty":         func(s *MinecraftServerSpec) any { return s.Config.Difficulty },
+	"config.levelName":          func(s *MinecraftServerSpec) any { return s.Config.LevelName },
+	"config.levelType":          func(s *MinecraftServerSpec) any { return s.Config.LevelType },
+	"config.motd":               func(s *MinecraftServerSpec) any { return s.Config.MOTD },
+	"config.spawnProtection":    func(s *MinecraftServerSpec) any { return s.Config.SpawnProtection },
+	"config.viewDistance":       func(s *MinecraftServerSpec) any { return s.Config.ViewDistance },
+	"config.simulationDistance": func(s *MinecraftServerSpec) any { return s.Config.SimulationDistance },
+	"config.whiteList":          func(s *MinecraftServerSpec) any { return s.Config.WhiteList },
+	"config.onlineMode":         func(s *MinecraftServerSpec) any { return s.Config.OnlineMode },
+	"config.pvp":                func(s *MinecraftServerSpec) any { return s.Config.PVP },
+	"config.enableCommandBlock": func(s *MinecraftServerSpec) any { return s.Config.EnableCommandBlock },
+	"config.allowFlight":        func(s *MinecraftServerSpec) any { return s.Config.AllowFlight },
+	"config.allowNether":        func(s *MinecraftServerSpec) any { return s.Config.AllowNether },
+	"config.spawnAnimals":       func(s *MinecraftServerSpec) any { return s.Config.SpawnAnimals },
+	"config.spawnMonsters":      func(s *MinecraftServerSpec) any { return s.Config.SpawnMonsters },
+	"config.spawnNPCs":          func(s *MinecraftServerSpec) any { return s.Config.SpawnNPCs },
+	"config.generateStructures": func(s *MinecraftServerSpec) any { return s.Config.GenerateStructures },
+	"config.hardcoreMode":       func(s *MinecraftServerSpec) any { return s.Config.HardcoreMode },
+	"config.forceGamemode":      func(s *MinecraftServerSpec) any { return s.Config.ForceGamemode },
+}
+
+// TemplateDefaults is the set of fields a server holds at their defaults, as recorded in TemplateDefaultsAnnotation
+// +kubebuilder:object:generate=false
+type TemplateDefaults map[string]bool
+
+// ParseTemplateDefaults reads TemplateDefaultsAnnotation; ok is false when the server has none
+func ParseTemplateDefaults(annotations map[string]string) (defaults TemplateDefaults, ok bool) {
+	value, ok := annotations[TemplateDefaultsAnnotation]
+	if !ok {
+		return nil, false
+	}
+	defaults = TemplateDefaults{}
+	for _, path := range strings.Split(value, ",") {
+		if path != "" {
+			defaults[path] = true
+		}
+	}
+	return defaults, true
+}
+
+// String formats the set for TemplateDefaultsAnnotation
+func (d TemplateDefaults) String() string {
+	paths := make([]string, 0, len(d))
+	for path := range d {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, ",")
+}
+
+// UnsetFields returns the template fields that are unset in the spec
+func (s *MinecraftServerSpec) UnsetFields() TemplateDefaults {
+	unset := TemplateDefaults{}
+	for path, get := range templateDefaultFields {
+		if reflect.ValueOf(get(s)).IsZero() {
+			unset[path] = true
+		}
+	}
+	return unset
+}
+
+// FieldsAtDefault returns the template fields that are unset or at their default
+// For servers without TemplateDefaultsAnnotation, which can't tell a default from an explicit value
+func (s *MinecraftServerSpec) FieldsAtDefault() TemplateDefaults {
+	var defaults MinecraftServer
+	defaults.Default()
+	atDefault := s.UnsetFields()
+	for path, get := range templateDefaultFields {
+		if reflect.DeepEqual(get(s), get(&defaults.Spec)) {
+			atDefault[path] = true
+		}
+	}
+	return atDefault
+}
+
+// trackTemplateDefaults adds the template fields left unset to TemplateDefaultsAnnotation before they are defaulted
+// Tracking starts when a server is created from a template; servers attached later start at their first apply.
+func (m *MinecraftServer) trackTemplateDefaults() {
+	if m.Spec.TemplateRef == nil {
+		return
+	}
+	defaults, ok := ParseTemplateDefaults(m.Annotations)
+	if !ok && !m.CreationTimestamp.IsZero() {
+		return
+	}
+	if defaults == nil {
+		defaults = TemplateDefaults{}
+	}
+	for path := range m.Spec.UnsetFields() {
+		defaults[path] = true
+	}
+	if m.Annotations == nil {
+		m.Annotations = map[string]string{}
+	}
+	m.Annotations[TemplateDefaultsAnnotation] = defaults.String()
+}
+
+// TemplateReference names the MinecraftServerTemplate a server is built from
+type TemplateReference struct {
+	// Name of the cluster-scoped MinecraftServerTemplate
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// MinecraftServerTemplateSpec is a reusable server preset; every field is optional
+type MinecraftServerTemplateSpec struct {
+	// Description is shown when choosing a template
+	Description string `json:"description,omitempty"`
+
+	// Image is the Docker image to use for the Minecraft server
+	Image string `json:"image,omitempty"`
+
+	// ImageUpdate decides when a newer digest of the image's tag is rolled out
+	ImageUpdate *ImageUpdateConfig `json:"imageUpdate,omitempty"`
+
+	// ServerType is the type of Minecraft server to run
+	// +kubebuilder:validation:Enum=VANILLA;PAPER;SPIGOT;BUKKIT;FORGE;FABRIC;PURPUR;QUILT;NEOFORGE
+	ServerType string `json:"serverType,omitempty"`
+
+	// Version is the Minecraft version to run
+	Version string `json:"version,omitempty"`
+
+	// Size selects a resource tier; see MinecraftServerSpec.Size
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9-]+$`
+	Size string `json:"size,omitempty"`
+
+	// Resources defines the resource requirements for the server
+	Resources *TemplateResources `json:"resources,omitempty"`
+
+	// Config contains the server configuration
+	Config *MinecraftServerConfig `json:"config,omitempty"`
+
+	// JVM selects the garbage collector tuning and extra JVM options
+	JVM *JVMConfig `json:"jvm,omitempty"`
+
+	// Modpack installs a Modrinth, CurseForge or URL modpack
+	Modpack *ModpackConfig `json:"modpack,omitempty"`
+
+	// Upgrade configures how version changes are rolled out
+	Upgrade *UpgradeConfig `json:"upgrade,omitempty"`
+
+	// MaintenanceWindow is when restarts and upgrades are applied
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// StorageClass is the storage class to use for persistent volumes
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// Plugins are installed in addition to the server's own; a server plugin with the same name replaces the template's
+	Plugins []MinecraftPlugin `json:"plugins,omitempty"`
+
+	// Backup configuration
+	Backup *BackupConfig `json:"backup,omitempty"`
+
+	// AutoStop configuration for automatic shutdown on inactivity
+	AutoStop *AutoStopConfig `json:"autoStop,omitempty"`
+
+	// AutoStart configuration for automatic startup when player connects
+	AutoStart *AutoStartConfig `json:"autoStart,omitempty"`
+
+	// Monitoring configures the in-game metrics exporter
+	Monitoring *MonitoringConfig `json:"monitoring,omitempty"`
+
+	// Bedrock lets Bedrock Edition clients join through Geyser
+	Bedrock *BedrockConfig `json:"bedrock,omitempty"`
+}
+
+// TemplateResources are the server resources of a template; every field is optional
+type TemplateResources struct {
+	// CPU request (e.g., "1000m")
+	CPURequest *resource.Quantity `json:"cpuRequest,omitempty"`
+
+	// CPU limit (e.g., "2000m")
+	CPULimit *resource.Quantity `json:"cpuLimit,omitempty"`
+
+	// Memory request (e.g., "2Gi")
+	MemoryRequest *resource.Quantity `json:"memoryRequest,omitempty"`
+
+	// Memory limit (e.g., "4Gi")
+	MemoryLimit *resource.Quantity `json:"memoryLimit,omitempty"`
+
+	// Memory allocation for JVM (e.g., "3G")
+	Memory string `json:"memory,omitempty"`
+
+	// Storage size (e.g., "10Gi")
+	Storage *resource.Quantity `json:"storage,omitempty"`
+}
+
+// TemplateServer is a server built from a template
+type TemplateServer struct {
+	// Namespace of the MinecraftServer
+	Namespace string `json:"namespace"`
+
+	// Name of the MinecraftServer
+	Name string `json:"name"`
+
+	// ServerID is the server's spec.serverId
+	ServerID string `json:"serverId"`
+
+	// Revision is the template revision last applied to the server; 0 until it is first applied
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// MinecraftServerTemplateStatus defines the observed state of MinecraftServerTemplate
+type MinecraftServerTemplateStatus struct {
+	// Revision is the current template revision (its metadata.generation)
+	Revision int64 `json:"revision,omitempty"`
+
+	// Servers are the servers referencing the template, with the revision each was built from
+	Servers []TemplateServer `json:"servers,omitempty"`
+
+	// ServerCount is the number of servers referencing the template
+	ServerCount int32 `json:"serverCount,omitempty"`
+
+	// OutdatedServers is the number of servers not yet built from the current revision
+	OutdatedServers int32 `json:"outdatedServers,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=mctemplate;mct
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.serverType"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version"
+// +kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=".status.revision"
+// +kubebuilder:printcolumn:name="Servers",type="integer",JSONPath=".status.serverCount"
+// +kubebuilder:printcolumn:name="Outdated",type="integer",JSONPath=".status.outdatedServers"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MinecraftServerTemplate is the Schema for the minecraftservertemplates API
+type MinecraftServerTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MinecraftServerTemplateSpec   `json:"spec,omitempty"`
+	Status MinecraftServerTemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MinecraftServerTemplateList contains a list of MinecraftServerTemplate
+type MinecraftServerTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MinecraftServerTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MinecraftServerTemplate{}, &MinecraftServerTemplateList{})
+}
+
+// ApplyTemplate merges a template into the spec; applied is the template spec applied last time, or nil
+// A field follows the template while it is unset, still at the default it was left at (listed in unset),
+// or still at the value applied last time; any other value is an override and is kept, even the default.
+// Fields the template leaves unset are not touched. Fields that no longer hold their default are removed from unset.
+// Sections are shared with template, so pass a copy.
+func (s *MinecraftServerSpec) ApplyTemplate(template, applied *MinecraftServerTemplateSpec, unset TemplateDefaults) {
+	if applied == nil {
+		applied = &MinecraftServerTemplateSpec{}
+	}
+	var defaults MinecraftServer
+	defaults.Default()
+
+	follow(&s.Image, defaults.Spec.Image, applied.Image, template.Image, unset["image"])
+	follow(&s.ServerType, defaults.Spec.ServerType, applied.ServerType, template.ServerType, unset["serverType"])
+	follow(&s.Version, defaults.Spec.Version, applied.Version, template.Version, unset["version"])
+	follow(&s.StorageClass, defaults.Spec.StorageClass, applied.StorageClass, template.StorageClass, unset["storageClass"])
+	follow(&s.Size, "", applied.Size, template.Size, true)
+
+	if template.Resources != nil {
+		s.Resources.applyTemplate(template.Resources, applied.Resources)
+	}
+	if template.Config != nil {
+		appliedConfig := applied.Config
+		if appliedConfig == nil {
+			appliedConfig = &MinecraftServerConfig{}
+		}
+		s.Config.applyTemplate(template.Config, appliedConfig, &defaults.Spec.Config, unset)
+	}
+
+	s.Plugins = mergePlugins(s.Plugins, applied.Plugins, template.Plugins)
+
+	followStruct(&s.ImageUpdate, applied.ImageUpdate, template.ImageUpdate)
+	followStruct(&s.JVM, applied.JVM, template.JVM)
+	followStruct(&s.Modpack, applied.Modpack, template.Modpack)
+	followStruct(&s.Upgrade, applied.Upgrade, template.Upgrade)
+	followStruct(&s.MaintenanceWindow, applied.MaintenanceWindow, template.MaintenanceWindow)
+	followStruct(&s.Backup, applied.Backup, template.Backup)
+	followStruct(&s.AutoStop, applied.AutoStop, template.AutoStop)
+	followStruct(&s.AutoStart, applied.AutoStart, template.AutoStart)
+	followStruct(&s.Monitoring, applied.Monitoring, template.Monitoring)
+	followStruct(&s.Bedrock, applied.Bedrock, template.Bedrock)
+
+	for path := range unset {
+		get, ok := templateDefaultFields[path]
+		if ok && !reflect.ValueOf(get(s)).IsZero() && !reflect.DeepEqual(get(s), get(&defaults.Spec)) {
+			delete(unset, path)
+		}
+	}
+}
+
+// applyTemplate merges template resources; zero quantities count as unset
+func (r *MinecraftServerResources) applyTemplate(template, applied *TemplateResources) {
+	if applied == nil {
+		applied = &TemplateResources{}
+	}
+	followQuantity(&r.CPURequest, applied.CPURequest, template.CPURequest)
+	followQuantity(&r.CPULimit, applied.CPULimit, template.CPULimit)
+	followQuantity(&r.MemoryRequest, applied.MemoryRequest, template.MemoryRequest)
+	followQuantity(&r.MemoryLimit, applied.MemoryLimit, template.MemoryLimit)
+	follow(&r.Memory, "", applied.Memory, template.Memory, true)
+	followQuantity(&r.Storage, applied.Storage, template.Storage)
+}
+
+// applyTemplate merges a template config; defaults holds the schema defaults, unset the fields left at them
+func (c *MinecraftServerConfig) applyTemplate(template, applied, defaults *MinecraftServerConfig, unset TemplateDefaults) {
+	follow(&c.MaxPlayers, defaults.MaxPlayers, applied.MaxPlayers, template.MaxPlayers, unset["config.maxPlayers"])
+	follow(&c.Gamemode, defaults.Gamemode, applied.Gamemode, template.Gamemode, unset["config.gamemode"])
+	follow(&c.Difficulty, defaults.Difficulty, applied.Difficulty, template.Difficulty, unset["config.difficulty"])
+	follow(&c.LevelName, defaults.LevelName, applied.LevelName, template.LevelName, unset["config.levelName"])
+	follow(&c.LevelSeed, defaults.LevelSeed, applied.LevelSeed, template.LevelSeed, true)
+	follow(&c.LevelType, defaults.LevelType, applied.LevelType, template.LevelType, unset["config.levelType"])
+	follow(&c.MOTD, defaults.MOTD, applied.MOTD, template.MOTD, unset["config.motd"])
+	follow(&c.SpawnProtection, defaults.SpawnProtection, applied.SpawnProtection, template.SpawnProtection, unset["config.spawnProtection"])
+	follow(&c.ViewDistance, defaults.ViewDistance, applied.ViewDistance, template.ViewDistance, unset["config.viewDistance"])
+	follow(&c.SimulationDistance, defaults.SimulationDistance, applied.SimulationDistance, template.SimulationDistance, unset["config.simulationDistance"])
+
+	for _, field := range []struct {
+		path                                   string
+		value, defaultValue, applied, template *bool
+		target                                 **bool
+	}{
+		{"config.whiteList", c.WhiteList, defaults.WhiteList, applied.WhiteList, template.WhiteList, &c.WhiteList},
+		{"config.onlineMode", c.OnlineMode, defaults.OnlineMode, applied.OnlineMode, template.OnlineMode, &c.OnlineMode},
+		{"config.pvp", c.PVP, defaults.PVP, applied.PVP, template.PVP, &c.PVP},
+		{"config.enableCommandBlock", c.EnableCommandBlock, defaults.EnableCommandBlock, applied.EnableCommandBlock, template.EnableCommandBlock, &c.EnableCommandBlock},
+		{"config.allowFlight", c.AllowFlight, defaults.AllowFlight, applied.AllowFlight, template.AllowFlight, &c.AllowFlight},
+		{"config.allowNether", c.AllowNether, defaults.AllowNether, applied.AllowNether, template.AllowNether, &c.AllowNether},
+		{"config.spawnAnimals", c.SpawnAnimals, defaults.SpawnAnimals, applied.SpawnAnimals, template.SpawnAnimals, &c.SpawnAnimals},
+		{"config.spawnMonsters", c.SpawnMonsters, defaults.SpawnMonsters, applied.SpawnMonsters, template.SpawnMonsters, &c.SpawnMonsters},
+		{"config.spawnNPCs", c.SpawnNPCs, defaults.SpawnNPCs, applied.SpawnNPCs, template.SpawnNPCs, &c.SpawnNPCs},
+		{"config.generateStructures", c.GenerateStructures, defaults.GenerateStructures, applied.GenerateStructures, template.GenerateStructures, &c.GenerateStructures},
+		{"config.hardcoreMode", c.HardcoreMode, defaults.HardcoreMode, applied.HardcoreMode, template.HardcoreMode, &c.HardcoreMode},
+		{"config.forceGamemode", c.ForceGamemode, defaults.ForceGamemode, applied.ForceGamemode, template.ForceGamemode, &c.ForceGamemode},
+	} {
+		if field.template == nil {
+			continue
+		}
+		if field.value == nil || (unset[field.path] && *field.value == *field.defaultValue) || (field.applied != nil && *field.value == *field.applied) {
+			value := *field.template
+			*field.target = &value
+		}
+	}
+
+	// Template properties are the base; the server's own keys win
+	if len(template.AdditionalProperties) > 0 {
+		properties := make(map[string]string, len(template.AdditionalProperties)+len(c.AdditionalProperties))
+		for key, value := range template.AdditionalProperties {
+			properties[key] = value
+		}
+		for key, value := range c.AdditionalProperties {
+			if appliedValue, ok := applied.AdditionalProperties[key]; ok && appliedValue == value {
+				continue
+			}
+			properties[key] = value
+		}
+		c.AdditionalProperties = properties
+	}
+}
+
+// ApplyBoolDefaults sets the unset booleans the server webhook would default,
+// so inherited sections compare equal to the applied template after the server is defaulted
+func (t *MinecraftServerTemplateSpec) ApplyBoolDefaults() {
+	for i := range t.Plugins {
+		if t.Plugins[i].Enabled == nil {
+			t.Plugins[i].Enabled = pointer.Bool(true)
+		}
+	}
+	if t.Monitoring != nil && t.Monitoring.ServiceMonitor == nil {
+		t.Monitoring.ServiceMonitor = pointer.Bool(true)
+	}
+	if t.Bedrock != nil && t.Bedrock.Floodgate == nil {
+		t.Bedrock.Floodgate = pointer.Bool(true)
+	}
+}
+
+// follow sets value to the template's unless it was overridden; atDefault is whether the default counts as unset
+func follow[T comparable](value *T, defaultValue, applied, template T, atDefault bool) {
+	var zero T
+	if template == zero {
+		return
+	}
+	if *value == zero || (atDefault && *value == defaultValue) || *value == applied {
+		*value = template
+	}
+}
+
+// followQuantity is follow for resource quantities
+func followQuantity(value *resource.Quantity, applied, template *resource.Quantity) {
+	if template == nil {
+		return
+	}
+	if value.IsZero() || (applied != nil && value.Cmp(*applied) == 0) {
+		*value = template.DeepCopy()
+	}
+}
+
+// followStruct is follow for optional sections, which are replaced as a whole
+func followStruct[T any](value **T, applied, template *T) {
+	if template == nil {
+		return
+	}
+	if *value == nil || (applied != nil && reflect.DeepEqual(*value, applied)) {
+		copied := *template
+		*value = &copied
+	}
+}
+
+// mergePlugins puts the template plugins first, followed by the server's own plugins
+// Plugins that are unchanged from the last applied template are replaced by the current template's
+func mergePlugins(current, applied, template []MinecraftPlugin) []MinecraftPlugin {
+	if len(template) == 0 && len(applied) == 0 {
+		return current
+	}
+
+	appliedByName := make(map[string]MinecraftPlugin, len(applied))
+	for _, plugin := range applied {
+		appliedByName[plugin.Name] = plugin
+	}
+
+	var own []MinecraftPlugin
+	ownNames := map[string]bool{}
+	for _, plugin := range current {
+		if inherited, ok := appliedByName[plugin.Name]; ok && reflect.DeepEqual(inherited, plugin) {
+			continue
+		}
+		own = append(own, plugin)
+		ownNames[plugin.Name] = true
+	}
+
+	var merged []MinecraftPlugin
+	for _, plugin := range template {
+		if !ownNames[plugin.Name] {
+			merged = append(merged, *plugin.DeepCopy())
+		}
+	}
+	return append(merged, own...)
+}
@@ -0,0 +1,105 @@
+package v2
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// admit defaults a server the way the webhook does on create, then marks it as stored
+func admit(spec MinecraftServerSpec) *MinecraftServer {
+	server := &MinecraftServer{Spec: spec}
+	server.Spec.TemplateRef = &TemplateReference{Name: "survival"}
+	server.Default()
+	server.CreationTimestamp = metav1.Now()
+	return server
+}
+
+// applyTemplate applies template like the controller does and returns it as the applied spec for the next call
+func applyTemplate(server *MinecraftServer, template, applied *MinecraftServerTemplateSpec) *MinecraftServerTemplateSpec {
+	unset, ok := ParseTemplateDefaults(server.Annotations)
+	if !ok {
+		unset = server.Spec.FieldsAtDefault()
+	}
+	server.Spec.ApplyTemplate(template.DeepCopy(), applied, unset)
+	server.Default()
+	server.Annotations[TemplateDefaultsAnnotation] = unset.String()
+	return template
+}
+
+func TestApplyTemplateKeepsExplicitDefaults(t *testing.T) {
+	template := &MinecraftServerTemplateSpec{Config: &MinecraftServerConfig{MaxPlayers: 50, MOTD: "Welcome"}}
+
+	explicit := admit(MinecraftServerSpec{Config: MinecraftServerConfig{MaxPlayers: 20}})
+	applyTemplate(explicit, template, nil)
+	if explicit.Spec.Config.MaxPlayers != 20 || explicit.Spec.Config.MOTD != "Welcome" {
+		t.Errorf("explicit maxPlayers 20: got maxPlayers %d, motd %q", explicit.Spec.Config.MaxPlayers, explicit.Spec.Config.MOTD)
+	}
+
+	unset := admit(MinecraftServerSpec{})
+	applyTemplate(unset, template, nil)
+	if unset.Spec.Config.MaxPlayers != 50 {
+		t.Errorf("unset maxPlayers: expected 50, got %d", unset.Spec.Config.MaxPlayers)
+	}
+}
+
+func TestApplyTemplateOverrideBackToDefault(t *testing.T) {
+	server := admit(MinecraftServerSpec{})
+	applied := applyTemplate(server, &MinecraftServerTemplateSpec{Config: &MinecraftServerConfig{MaxPlayers: 50}}, nil)
+
+	// The server goes back to the default, then the template changes
+	server.Spec.Config.MaxPlayers = 20
+	applyTemplate(server, &MinecraftServerTemplateSpec{Config: &MinecraftServerConfig{MaxPlayers: 60}}, applied)
+	if server.Spec.Config.MaxPlayers != 20 {
+		t.Errorf("expected the override 20 to be kept, got %d", server.Spec.Config.MaxPlayers)
+	}
+}
+
+func TestApplyTemplateAddsFieldsLeftAtDefault(t *testing.T) {
+	server := admit(MinecraftServerSpec{Config: MinecraftServerConfig{PVP: pointer.Bool(true)}})
+	applied := applyTemplate(server, &MinecraftServerTemplateSpec{Version: "1.20.4"}, nil)
+
+	// Fields the first revision didn't set still follow later revisions, unless the server set them
+	template := &MinecraftServerTemplateSpec{Version: "1.20.4", Config: &MinecraftServerConfig{Difficulty: "hard", PVP: pointer.Bool(false)}}
+	applyTemplate(server, template, applied)
+	if server.Spec.Config.Difficulty != "hard" {
+		t.Errorf("expected difficulty from the template, got %q", server.Spec.Config.Difficulty)
+	}
+	if !*server.Spec.Config.PVP {
+		t.Error("expected the explicit pvp: true to be kept")
+	}
+}
+
+func TestApplyTemplateAttachedLater(t *testing.T) {
+	// Created without a template, so the defaults weren't recorded; values at their default follow
+	server := &MinecraftServer{Spec: MinecraftServerSpec{Config: MinecraftServerConfig{MaxPlayers: 30}}}
+	server.Default()
+	server.CreationTimestamp = metav1.Now()
+	server.Spec.TemplateRef = &TemplateReference{Name: "survival"}
+	server.Default()
+	if _, ok := server.Annotations[TemplateDefaultsAnnotation]; ok {
+		t.Fatal("expected no recorded defaults for an existing server")
+	}
+
+	server.Annotations = map[string]string{}
+	applyTemplate(server, &MinecraftServerTemplateSpec{Config: &MinecraftServerConfig{MaxPlayers: 50, ViewDistance: 12}}, nil)
+	if server.Spec.Config.MaxPlayers != 30 || server.Spec.Config.ViewDistance != 12 {
+		t.Errorf("expected maxPlayers 30 and viewDistance 12, got %d and %d", server.Spec.Config.MaxPlayers, server.Spec.Config.ViewDistance)
+	}
+}
+
+func TestUnsetFieldsRecordedOnUpdate(t *testing.T) {
+	server := admit(MinecraftServerSpec{Config: MinecraftServerConfig{MaxPlayers: 30}})
+	unset, _ := ParseTemplateDefaults(server.Annotations)
+	if unset["config.maxPlayers"] || !unset["config.motd"] || !unset["version"] {
+		t.Fatalf("unexpected unset fields %q", unset.String())
+	}
+
+	// Removing a field from the manifest makes it follow the template again
+	server.Spec.Config.MaxPlayers = 0
+	server.Default()
+	if unset, _ := ParseTemplateDefaults(server.Annotations); !unset["config.maxPlayers"] {
+		t.Errorf("expected config.maxPlayers to be recorded, got %q", unset.String())
+	}
+}
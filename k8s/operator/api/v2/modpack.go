@@ -0,0 +1,78 @@
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ModpackSource is where a modpack is downloaded from
+// +kubebuilder:validation:Enum=Modrinth;CurseForge;URL
+type ModpackSource string
+
+const (
+	// ModpackModrinth is a Modrinth project or a .mrpack URL
+	ModpackModrinth ModpackSource = "Modrinth"
+	// ModpackCurseForge is a CurseForge modpack project; needs a CurseForge API key
+	ModpackCurseForge ModpackSource = "CurseForge"
+	// ModpackURL is a zip of server files (mods, config) extracted over the data volume
+	ModpackURL ModpackSource = "URL"
+)
+
+// ModpackConfig installs a pinned set of mods on a modded server (FORGE, FABRIC, NEOFORGE, QUILT)
+type ModpackConfig struct {
+	// Source is Modrinth, CurseForge or URL
+	Source ModpackSource `json:"source"`
+
+	// Project is the Modrinth project slug or ID, or the CurseForge project slug
+	// +kubebuilder:validation:MaxLength=128
+	Project string `json:"project,omitempty"`
+
+	// URL is a .mrpack file (Modrinth, instead of project) or a server pack zip (URL)
+	// +kubebuilder:validation:MaxLength=2048
+	URL string `json:"url,omitempty"`
+
+	// Version is the Modrinth version number or ID, the CurseForge file ID, or a label for a URL pack
+	// When unset, the newest version for the server's Minecraft version and loader is used. Required for URL.
+	// +kubebuilder:validation:MaxLength=128
+	Version string `json:"version,omitempty"`
+
+	// APIKeySecretRef is the Secret key holding the CurseForge API key; required for CurseForge
+	APIKeySecretRef *corev1.SecretKeySelector `json:"apiKeySecretRef,omitempty"`
+}
+
+// ModpackStatus is the modpack version installed on the server
+type ModpackStatus struct {
+	// Source and Project (or URL) the pack came from
+	Source  ModpackSource `json:"source"`
+	Project string        `json:"project,omitempty"`
+	URL     string        `json:"url,omitempty"`
+
+	// Version is the resolved pack version, VersionID its Modrinth version or CurseForge file ID
+	Version   string `json:"version,omitempty"`
+	VersionID string `json:"versionId,omitempty"`
+
+	// GameVersion is the Minecraft version the pack is built for
+	GameVersion string `json:"gameVersion,omitempty"`
+
+	// Loader and LoaderVersion are the mod loader the pack pins (e.g. fabric 0.15.11), when the manifest names one
+	Loader        string `json:"loader,omitempty"`
+	LoaderVersion string `json:"loaderVersion,omitempty"`
+
+	// PendingVersion is a newer resolved version waiting for its pre-upgrade backup or the maintenance window
+	PendingVersion string `json:"pendingVersion,omitempty"`
+
+	// UpgradeBackup is the backup Job taken before the installed version replaced the previous one
+	UpgradeBackup string `json:"upgradeBackup,omitempty"`
+
+	// InstalledAt is when the installed version was rolled out
+	InstalledAt *metav1.Time `json:"installedAt,omitempty"`
+}
+
+// IsModdedType reports whether the server type loads mods, which modpacks need
+func IsModdedType(serverType string) bool {
+	switch serverType {
+	case "FORGE", "FABRIC", "NEOFORGE", "QUILT":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package v2
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// SizeTier is a named set of consistent server resources selected with spec.size
+type SizeTier struct {
+	// CPURequest and CPULimit of the server container
+	CPURequest resource.Quantity `json:"cpuRequest"`
+	CPULimit   resource.Quantity `json:"cpuLimit"`
+
+	// Memory is both the memory request and limit of the server container; the JVM heap is derived from it
+	Memory resource.Quantity `json:"memory"`
+
+	// Storage is the size of the world volume
+	Storage resource.Quantity `json:"storage"`
+}
+
+// DefaultSizeTiers are the built-in tiers; the operator's --size-tiers-file can replace them or add more
+func DefaultSizeTiers() map[string]SizeTier {
+	tier := func(cpuRequest, cpuLimit, memory, storage string) SizeTier {
+		return SizeTier{
+			CPURequest: resource.MustParse(cpuRequest),
+			CPULimit:   resource.MustParse(cpuLimit),
+			Memory:     resource.MustParse(memory),
+			Storage:    resource.MustParse(storage),
+		}
+	}
+	return map[string]SizeTier{
+		"XS": tier("250m", "1", "1Gi", "5Gi"),
+		"S":  tier("500m", "2", "2Gi", "10Gi"),
+		"M":  tier("1", "3", "4Gi", "20Gi"),
+		"L":  tier("2", "4", "8Gi", "40Gi"),
+		"XL": tier("4", "8", "16Gi", "80Gi"),
+	}
+}
+
+// HeapFor returns the JVM heap (-Xmx) for a container memory limit
+// The JVM needs memory outside the heap (metaspace, threads, direct buffers), which matters more on small
+// containers, so the heap is 75% of limits below 4Gi and 80% from 4Gi on
+func HeapFor(limit resource.Quantity) string {
+	percent := int64(80)
+	if limit.Cmp(resource.MustParse("4Gi")) < 0 {
+		percent = 75
+	}
+	return fmt.Sprintf("%dM", limit.Value()*percent/100/(1<<20))
+}
+
+// Resolve fills the resources left unset from tier (nil for no tier) and derives what follows from them
+// Explicit values always win. The memory request defaults to the limit, and the JVM heap to HeapFor(limit).
+func (r MinecraftServerResources) Resolve(tier *SizeTier) MinecraftServerResources {
+	resolved := *r.DeepCopy()
+	if tier != nil {
+		for _, field := range []struct {
+			value    *resource.Quantity
+			fallback resource.Quantity
+		}{
+			{&resolved.CPURequest, tier.CPURequest},
+			{&resolved.CPULimit, tier.CPULimit},
+			{&resolved.MemoryLimit, tier.Memory},
+			{&resolved.Storage, tier.Storage},
+		} {
+			if field.value.IsZero() {
+				*field.value = field.fallback.DeepCopy()
+			}
+		}
+	}
+
+	if resolved.MemoryRequest.IsZero() && !resolved.MemoryLimit.IsZero() {
+		resolved.MemoryRequest = resolved.MemoryLimit.DeepCopy()
+	}
+	if resolved.Memory == "" && !resolved.MemoryLimit.IsZero() {
+		resolved.Memory = HeapFor(resolved.MemoryLimit)
+	}
+	return resolved
+}
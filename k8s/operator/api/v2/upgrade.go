@@ -0,0 +1,122 @@
+package v2
+
+import (
+	"regexp"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RetryUpgradeAnnotation retries an upgrade to spec.version that was rolled back or failed; removed once the retry starts
+const RetryUpgradeAnnotation = "minecraft.platform.com/retry-upgrade"
+
+// DefaultUpgradeTimeout is how long a new version has to become Ready when upgrade.timeout is unset
+const DefaultUpgradeTimeout = 10 * time.Minute
+
+// UpgradeConfig configures how spec.version changes are rolled out
+type UpgradeConfig struct {
+	// DryRun starts the new version on a copy of the world first; the server is only upgraded once the copy is Ready
+	// The copy needs the server's resources a second time while it runs
+	// +kubebuilder:default=false
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Timeout is how long the new version has to become Ready before it is rolled back (Go duration, default 10m)
+	Timeout string `json:"timeout,omitempty"`
+
+	// AllowDowngrade acknowledges that spec.version is older than status.highestVersion
+	// A downgrade replaces the world with RestoreBackup, so progress since that backup is lost
+	AllowDowngrade bool `json:"allowDowngrade,omitempty"`
+
+	// RestoreBackup is the backup ID restored before a downgrade; it must have been taken on spec.version or older
+	// Backup IDs are UUIDs or snapshot job names such as snapshot-<serverId>-1a2b3c4d
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	RestoreBackup string `json:"restoreBackup,omitempty"`
+}
+
+// backupIDPattern matches backup IDs: lowercase DNS labels, which covers UUIDs and snapshot job names
+var backupIDPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ValidBackupID reports whether id can name a backup archive
+// Backup IDs end up in paths on the shared backup volume, so anything else is rejected
+func ValidBackupID(id string) bool {
+	return len(id) <= 63 && backupIDPattern.MatchString(id)
+}
+
+// TimeoutDuration returns the upgrade timeout, falling back to DefaultUpgradeTimeout
+func (c *UpgradeConfig) TimeoutDuration() time.Duration {
+	if c != nil && c.Timeout != "" {
+		if timeout, err := time.ParseDuration(c.Timeout); err == nil && timeout > 0 {
+			return timeout
+		}
+	}
+	return DefaultUpgradeTimeout
+}
+
+// UpgradePhase is the step a version upgrade is at
+// +kubebuilder:validation:Enum=Snapshotting;DryRun;Restoring;RollingOut;RollingBack;Succeeded;RolledBack;Failed
+type UpgradePhase string
+
+const (
+	// UpgradeSnapshotting backs up the world before anything changes
+	UpgradeSnapshotting UpgradePhase = "Snapshotting"
+	// UpgradeDryRun runs the new version on a copy of the world restored from the snapshot
+	UpgradeDryRun UpgradePhase = "DryRun"
+	// UpgradeRestoring stops the server and restores upgrade.restoreBackup before a downgrade
+	UpgradeRestoring UpgradePhase = "Restoring"
+	// UpgradeRollingOut runs the new version on the server and waits for it to become Ready
+	UpgradeRollingOut UpgradePhase = "RollingOut"
+	// UpgradeRollingBack stops the server and restores the snapshot
+	UpgradeRollingBack UpgradePhase = "RollingBack"
+	// UpgradeSucceeded means the server is Ready on the new version
+	UpgradeSucceeded UpgradePhase = "Succeeded"
+	// UpgradeRolledBack means the server runs the previous version on the restored snapshot
+	UpgradeRolledBack UpgradePhase = "RolledBack"
+	// UpgradeFailed means the upgrade stopped before the server ran the new version, or the restore failed
+	UpgradeFailed UpgradePhase = "Failed"
+)
+
+// UpgradeStatus is the progress of the last version upgrade
+type UpgradeStatus struct {
+	// FromVersion is the version before the upgrade, ToVersion the version being upgraded to
+	FromVersion string `json:"fromVersion"`
+	ToVersion   string `json:"toVersion"`
+
+	// Phase is the current step
+	Phase UpgradePhase `json:"phase"`
+
+	// Snapshot is the backup Job taken before the upgrade; its archive is restored on rollback
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// RestoreBackup is the backup restored before a downgrade
+	RestoreBackup string `json:"restoreBackup,omitempty"`
+
+	// PhaseStartedAt is when the current phase started; the timeout counts from here
+	PhaseStartedAt *metav1.Time `json:"phaseStartedAt,omitempty"`
+
+	// Message explains the phase, including why an upgrade was rolled back
+	Message string `json:"message,omitempty"`
+}
+
+// IsDowngrade reports whether version is older than the newest version the world has been opened with
+// Aliases such as LATEST can't be compared and are never a downgrade
+func IsDowngrade(version, highestVersion string) bool {
+	parsed, err := ParseMinecraftVersion(version)
+	if err != nil {
+		return false
+	}
+	highest, err := ParseMinecraftVersion(highestVersion)
+	if err != nil {
+		return false
+	}
+	return parsed.Less(highest)
+}
+
+// InProgress reports whether the upgrade still has steps to run
+func (s *UpgradeStatus) InProgress() bool {
+	switch s.Phase {
+	case UpgradeSucceeded, UpgradeRolledBack, UpgradeFailed:
+		return false
+	}
+	return true
+}
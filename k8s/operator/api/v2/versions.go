@@ -0,0 +1,118 @@
+package v2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version aliases understood by the itzg/minecraft-server image
+const (
+	VersionLatest   = "LATEST"
+	VersionSnapshot = "SNAPSHOT"
+)
+
+// releasedPatches maps each 1.x release line to its highest released patch version
+// Keep in sync with MINECRAFT_VERSIONS in the frontend when new releases are added
+var releasedPatches = map[int]int{
+	0: 0, 1: 1, 2: 5, 3: 2, 4: 7, 5: 2, 6: 4, 7: 10, 8: 9, 9: 4, 10: 2,
+	11: 2, 12: 2, 13: 2, 14: 4, 15: 2, 16: 5, 17: 1, 18: 2, 19: 4, 20: 6, 21: 11,
+}
+
+// latestReleaseLine is the newest 1.x release line in releasedPatches
+const latestReleaseLine = 21
+
+// minimumVersions is the oldest Minecraft version each server type can run
+var minimumVersions = map[string]MinecraftVersion{
+	"VANILLA":  {Major: 1, Minor: 0},
+	"BUKKIT":   {Major: 1, Minor: 0},
+	"SPIGOT":   {Major: 1, Minor: 8},
+	"PAPER":    {Major: 1, Minor: 8, Patch: 8},
+	"FORGE":    {Major: 1, Minor: 1},
+	"FABRIC":   {Major: 1, Minor: 14},
+	"PURPUR":   {Major: 1, Minor: 14, Patch: 1},
+	"QUILT":    {Major: 1, Minor: 14, Patch: 4},
+	"NEOFORGE": {Major: 1, Minor: 20, Patch: 1},
+}
+
+// MinecraftVersion is a parsed <major>.<minor>.<patch> release version
+// Releases up to 1.21.x use major 1; from 2026 Mojang numbers releases by year, e.g. 26.1 and 26.1.1
+type MinecraftVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// String formats the version the way Mojang does (1.20 rather than 1.20.0)
+func (v MinecraftVersion) String() string {
+	if v.Patch == 0 {
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less returns true if v is older than other
+func (v MinecraftVersion) Less(other MinecraftVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// ParseMinecraftVersion parses a release version such as 1.20, 1.20.1 or 26.1
+func ParseMinecraftVersion(version string) (MinecraftVersion, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return MinecraftVersion{}, fmt.Errorf("invalid Minecraft version %q, expected <major>.<minor>[.<patch>]", version)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return MinecraftVersion{}, fmt.Errorf("invalid Minecraft version %q, expected <major>.<minor>[.<patch>]", version)
+		}
+		numbers[i] = n
+	}
+	if numbers[0] == 0 {
+		return MinecraftVersion{}, fmt.Errorf("invalid Minecraft version %q, the major version starts at 1", version)
+	}
+
+	return MinecraftVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// CheckVersion verifies that version exists and is supported by serverType
+// Versions newer than the known release lines cannot be verified and are reported as a warning instead
+func CheckVersion(serverType, version string) (warning string, err error) {
+	if version == VersionLatest {
+		return "", nil
+	}
+	if version == VersionSnapshot {
+		if serverType != "VANILLA" {
+			return "", fmt.Errorf("version %s is only available for VANILLA servers", VersionSnapshot)
+		}
+		return "", nil
+	}
+
+	parsed, err := ParseMinecraftVersion(version)
+	if err != nil {
+		return "", err
+	}
+
+	if parsed.Major > 1 || parsed.Minor > latestReleaseLine {
+		return fmt.Sprintf("version %s is newer than the versions known to the operator and could not be verified", version), nil
+	}
+	if parsed.Patch > releasedPatches[parsed.Minor] {
+		return "", fmt.Errorf("version %s does not exist (latest 1.%d release is %s)",
+			version, parsed.Minor, MinecraftVersion{Major: 1, Minor: parsed.Minor, Patch: releasedPatches[parsed.Minor]})
+	}
+
+	if minimum, ok := minimumVersions[serverType]; ok && parsed.Less(minimum) {
+		return "", fmt.Errorf("server type %s requires Minecraft %s or newer", serverType, minimum)
+	}
+
+	return "", nil
+}
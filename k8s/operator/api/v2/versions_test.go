@@ -0,0 +1,94 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMinecraftVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected MinecraftVersion
+	}{
+		{"1.8", MinecraftVersion{Major: 1, Minor: 8}},
+		{"1.20.1", MinecraftVersion{Major: 1, Minor: 20, Patch: 1}},
+		{"1.21.11", MinecraftVersion{Major: 1, Minor: 21, Patch: 11}},
+		{"26.1", MinecraftVersion{Major: 26, Minor: 1}},
+		{"26.1.2", MinecraftVersion{Major: 26, Minor: 1, Patch: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			parsed, err := ParseMinecraftVersion(tt.version)
+			if err != nil {
+				t.Fatalf("ParseMinecraftVersion: %v", err)
+			}
+			if parsed != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, parsed)
+			}
+			if parsed.String() != tt.version {
+				t.Errorf("expected %s to format as itself, got %s", tt.version, parsed)
+			}
+		})
+	}
+
+	for _, version := range []string{"", "1", "26", "0.30", "1.20.1.1", "1.x", "26.-1", "1.20-pre1", "LATEST"} {
+		if parsed, err := ParseMinecraftVersion(version); err == nil {
+			t.Errorf("expected %q to be invalid, got %+v", version, parsed)
+		}
+	}
+}
+
+func TestMinecraftVersionLess(t *testing.T) {
+	ordered := []string{"1.8.8", "1.20", "1.20.1", "1.21.11", "26.1", "26.1.1", "26.2", "27.1"}
+	for i := 1; i < len(ordered); i++ {
+		older, _ := ParseMinecraftVersion(ordered[i-1])
+		newer, _ := ParseMinecraftVersion(ordered[i])
+		if !older.Less(newer) || newer.Less(older) {
+			t.Errorf("expected %s to be older than %s", older, newer)
+		}
+	}
+
+	if IsDowngrade("26.1", "1.21.11") {
+		t.Error("expected 1.21.11 to 26.1 to be an upgrade")
+	}
+	if !IsDowngrade("1.21.11", "26.1") {
+		t.Error("expected 26.1 to 1.21.11 to be a downgrade")
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		serverType string
+		version    string
+		warning    bool
+		err        string
+	}{
+		{"PAPER", "1.20.4", false, ""},
+		{"PAPER", VersionLatest, false, ""},
+		{"VANILLA", VersionSnapshot, false, ""},
+		{"PAPER", VersionSnapshot, false, "only available for VANILLA"},
+		{"PAPER", "1.20.7", false, "does not exist"},
+		{"FABRIC", "1.12.2", false, "requires Minecraft 1.14 or newer"},
+		{"PAPER", "1.22", true, ""},
+		{"PAPER", "26.1", true, ""},
+		{"NEOFORGE", "26.1.1", true, ""},
+		{"PAPER", "0.30", false, "invalid Minecraft version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.serverType+" "+tt.version, func(t *testing.T) {
+			warning, err := CheckVersion(tt.serverType, tt.version)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckVersion: %v", err)
+			}
+			if (warning != "") != tt.warning {
+				t.Errorf("expected warning %v, got %q", tt.warning, warning)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Command exporter serves in-game metrics of a Minecraft server read over RCON
+// The operator injects it as a sidecar when spec.monitoring.enabled is set
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"minecraft-platform-operator/pkg/gamemetrics"
+)
+
+func main() {
+	var listenAddr string
+	var rconAddr string
+	var serverType string
+	var tenantID string
+	var serverID string
+	var timeout time.Duration
+
+	flag.StringVar(&listenAddr, "listen-address", ":9225", "The address the metrics endpoint binds to.")
+	flag.StringVar(&rconAddr, "rcon-address", "localhost:25575", "RCON address of the Minecraft server")
+	flag.StringVar(&serverType, "server-type", os.Getenv("TYPE"), "Server type, selects the commands used to read TPS and chunks")
+	flag.StringVar(&tenantID, "tenant-id", "", "Tenant label added to every metric")
+	flag.StringVar(&serverID, "server-id", "", "Server label added to every metric")
+	flag.DurationVar(&timeout, "rcon-timeout", 5*time.Second, "RCON connection timeout")
+	flag.Parse()
+
+	password := os.Getenv("RCON_PASSWORD")
+	if password == "" {
+		log.Fatal("RCON_PASSWORD environment variable is required")
+	}
+
+	// Same tenant/server labels as the operator's per-server metrics, so the two can be joined
+	registry := prometheus.NewRegistry()
+	labels := prometheus.Labels{"tenant": tenantID, "server": serverID}
+	prometheus.WrapRegistererWith(labels, registry).MustRegister(
+		gamemetrics.NewCollector(rconAddr, password, serverType, timeout),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	log.Printf("Serving %s server metrics on %s", serverType, listenAddr)
+	server := &http.Server{
+		Addr:              listenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if err := server.ListenAndServe(); err != nil {
+		log.Fatalf("Metrics server failed: %v", err)
+	}
+}
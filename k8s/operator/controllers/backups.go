@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"path"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/metrics"
+)
+
+// backupRecordedAnnotation marks a finished backup Job whose metrics have been recorded
+const backupRecordedAnnotation = "minecraft.platform.com/backup-recorded"
+
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=patch
+
+// reconcileBackupJobs records the result of finished backup Jobs for the server
+// Jobs from both the operator and the api-server backup service share the same labels
+// Successful backups advance Status.LastBackup; the caller writes the status
+func (r *MinecraftServerReconciler) reconcileBackupJobs(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs,
+		client.InNamespace(server.Namespace),
+		client.MatchingLabels{"app": "minecraft-backup", "server-id": server.Spec.ServerID},
+	); err != nil {
+		return fmt.Errorf("failed to list backup jobs: %w", err)
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		succeeded, finishedAt, finished := jobResult(job)
+		if !finished {
+			continue
+		}
+
+		// Not gated by the annotation, so a failed status write is retried on the next reconcile
+		if succeeded && (server.Status.LastBackup == nil || server.Status.LastBackup.Before(&finishedAt)) {
+			lastBackup := finishedAt
+			server.Status.LastBackup = &lastBackup
+		}
+
+		if _, recorded := job.Annotations[backupRecordedAnnotation]; recorded {
+			continue
+		}
+
+		duration := finishedAt.Sub(job.CreationTimestamp.Time)
+		if job.Status.StartTime != nil {
+			duration = finishedAt.Sub(job.Status.StartTime.Time)
+		}
+		metrics.ObserveBackup(server.Spec.TenantID, server.Spec.ServerID, duration, succeeded, finishedAt.Time)
+
+		patch := client.MergeFrom(job.DeepCopy())
+		if job.Annotations == nil {
+			job.Annotations = map[string]string{}
+		}
+		job.Annotations[backupRecordedAnnotation] = "true"
+		if err := r.Patch(ctx, job, patch); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to mark backup job as recorded", "job", job.Name)
+		}
+	}
+
+	return nil
+}
+
+// jobResult reports whether a Job finished, whether it succeeded and when it finished
+func jobResult(job *batchv1.Job) (succeeded bool, finishedAt metav1.Time, finished bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			finishedAt = condition.LastTransitionTime
+			if job.Status.CompletionTime != nil {
+				finishedAt = *job.Status.CompletionTime
+			}
+			return true, finishedAt, true
+		case batchv1.JobFailed:
+			return false, condition.LastTransitionTime, true
+		}
+	}
+	return false, metav1.Time{}, false
+}
+
+// snapshotJobName names the backup taken before a change, so every reconcile finds the same Job
+// The change is hashed to keep the name within the 63 characters allowed for Job names
+func snapshotJobName(server *minecraftv2.MinecraftServer, change string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(change))
+	return fmt.Sprintf("snapshot-%s-%08x", server.Spec.ServerID, hash.Sum32())
+}
+
+// ensureSnapshot starts the backup jobName unless it exists and reports whether it has succeeded
+// The job name doubles as backup ID, so the archive can be found again for a restore.
+// A failed backup is returned as an error, so the change it protects isn't applied
+func (r *MinecraftServerReconciler) ensureSnapshot(ctx context.Context, server *minecraftv2.MinecraftServer, jobName string) (bool, error) {
+	var job batchv1.Job
+	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: server.Namespace}, &job)
+	if errors.IsNotFound(err) {
+		log.FromContext(ctx).Info("Taking snapshot before change", "job", jobName)
+		return false, r.createBackupJob(ctx, server, jobName, jobName)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get snapshot job: %w", err)
+	}
+
+	succeeded, _, finished := jobResult(&job)
+	if finished && !succeeded {
+		return false, fmt.Errorf("snapshot %s failed", jobName)
+	}
+	return succeeded, nil
+}
+
+// buildRestoreJob creates a Job that replaces the data volume claimName with a backup archive
+// The Job mirrors the one the api-server backup service runs for restores; the server must be stopped
+func buildRestoreJob(server *minecraftv2.MinecraftServer, jobName, claimName, archive string) *batchv1.Job {
+	ttl := int32(3600) // Cleanup after 1 hour
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: server.Namespace,
+			Labels: map[string]string{
+				"app":       "minecraft-restore",
+				"server-id": server.Spec.ServerID,
+				"tenant":    server.Spec.TenantID,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "restore",
+							Image:   "alpine:latest",
+							Command: restoreCommand(archive),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "minecraft-data",
+									MountPath: "/data",
+								},
+								{
+									Name:      "backup-storage",
+									MountPath: "/backups",
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "minecraft-data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: claimName,
+								},
+							},
+						},
+						backupStorageVolume(true),
+					},
+				},
+			},
+		},
+	}
+}
+
+// backupScript archives /data to the archive passed as $1
+const backupScript = `tar -czf "$1" -C /data . && ls -lh "$1"`
+
+// backupCommand runs backupScript for archive on the backup storage
+func backupCommand(archive string) []string {
+	return []string{"/bin/sh", "-c", backupScript, "backup", path.Join("/backups", path.Base(archive))}
+}
+
+// restoreScript empties /data and extracts the archive passed as $1 into it
+// The archive is an argument rather than part of the script, so a backup ID can't inject shell commands
+const restoreScript = `find /data -mindepth 1 -delete && tar -xzf "$1" -C /data && ls -la /data`
+
+// restoreCommand runs restoreScript for archive on the backup storage
+func restoreCommand(archive string) []string {
+	return []string{"/bin/sh", "-c", restoreScript, "restore", path.Join("/backups", path.Base(archive))}
+}
+
+// backupStorageVolume mounts the shared backup PVC as the backup-storage volume
+func backupStorageVolume(readOnly bool) corev1.Volume {
+	return corev1.Volume{
+		Name: "backup-storage",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: backupStorageClaim,
+				ReadOnly:  readOnly,
+			},
+		},
+	}
+}
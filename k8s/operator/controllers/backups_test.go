@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+func TestBackupFilename(t *testing.T) {
+	server := &minecraftv2.MinecraftServer{Spec: minecraftv2.MinecraftServerSpec{ServerID: "0b3c5d6e-1111-2222-3333-444455556666"}}
+	if archive := backupFilename(server, "nightly"); archive != "0b3c5d6e-1111-2222-3333-444455556666-nightly.tar.gz" {
+		t.Errorf("unexpected archive %q", archive)
+	}
+
+	// Snapshot job names are backup IDs too, so the upgrade's archive is found again for a restore
+	snapshot := snapshotJobName(server, "upgrade/1.20.4/1.21.1")
+	if archive := backupFilename(server, snapshot); archive != server.Spec.ServerID+"-"+snapshot+".tar.gz" {
+		t.Errorf("unexpected snapshot archive %q", archive)
+	}
+}
+
+func TestBackupAndRestoreCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive string
+		path    string
+	}{
+		{"plain", "server-nightly.tar.gz", "/backups/server-nightly.tar.gz"},
+		{"directories are dropped", "../../etc/server-nightly.tar.gz", "/backups/server-nightly.tar.gz"},
+		{"shell syntax stays an argument", "server-$(reboot);x.tar.gz", "/backups/server-$(reboot);x.tar.gz"},
+		{"quotes stay an argument", `server-"; reboot; ".tar.gz`, `/backups/server-"; reboot; ".tar.gz`},
+		{"slashes only keep the last element", "server-$(rm -rf /data).tar.gz", "/backups/data).tar.gz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The script is fixed; the archive only ever reaches the shell as $1
+			if command := restoreCommand(tt.archive); !reflect.DeepEqual(command, []string{"/bin/sh", "-c", restoreScript, "restore", tt.path}) {
+				t.Errorf("unexpected restore command %q", command)
+			}
+			if command := backupCommand(tt.archive); !reflect.DeepEqual(command, []string{"/bin/sh", "-c", backupScript, "backup", tt.path}) {
+				t.Errorf("unexpected backup command %q", command)
+			}
+		})
+	}
+}
+
+func TestRestoreJobsUseArguments(t *testing.T) {
+	server := &minecraftv2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "tenant-1"},
+		Spec:       minecraftv2.MinecraftServerSpec{ServerID: "server-1", TenantID: "tenant-1"},
+	}
+	archive := backupFilename(server, "nightly")
+
+	backup := buildBackupJob(server, "backup-server-1-nightly", "nightly").Spec.Template.Spec.Containers[0]
+	if !reflect.DeepEqual(backup.Command, backupCommand(archive)) {
+		t.Errorf("backup Job runs %q", backup.Command)
+	}
+	restore := buildRestoreJob(server, "restore-server-1", "minecraft-data-survival-0", archive).Spec.Template.Spec.Containers[0]
+	if !reflect.DeepEqual(restore.Command, restoreCommand(archive)) {
+		t.Errorf("restore Job runs %q", restore.Command)
+	}
+}
+
+func TestValidBackupID(t *testing.T) {
+	for id, valid := range map[string]bool{
+		"nightly":                       true,
+		"snapshot-server-1-0a1b2c3d":    true,
+		"0b3c5d6e-1111-2222-3333-44445": true,
+		"":                              false,
+		"-nightly":                      false,
+		"Nightly":                       false,
+		"../nightly":                    false,
+		"nightly;rm -rf /data":          false,
+		"$(reboot)":                     false,
+		"a-very-long-backup-id-that-is-more-than-sixty-three-characters-long": false,
+	} {
+		if minecraftv2.ValidBackupID(id) != valid {
+			t.Errorf("ValidBackupID(%q): expected %v", id, valid)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+const (
+	// bedrockContainerPort is Geyser's default Bedrock listener (UDP)
+	bedrockContainerPort = 19132
+
+	// bedrockPortName names the Bedrock container and Service ports
+	bedrockPortName = "bedrock"
+
+	// geyserURL and floodgateURL are the latest Spigot builds from the GeyserMC download API
+	geyserURL    = "https://download.geysermc.org/v2/projects/geyser/versions/latest/builds/latest/downloads/spigot"
+	floodgateURL = "https://download.geysermc.org/v2/projects/floodgate/versions/latest/builds/latest/downloads/spigot"
+)
+
+// bedrockEnabled reports whether the server accepts Bedrock Edition clients
+// The webhook rejects Bedrock for server types without plugins; the type check also covers servers created without it
+func bedrockEnabled(server *minecraftv2.MinecraftServer) bool {
+	return server.Spec.Bedrock != nil && server.Spec.Bedrock.Enabled && minecraftv2.SupportsPlugins(server.Spec.ServerType)
+}
+
+// bedrockPluginURLs returns Geyser, plus Floodgate unless it is switched off
+// Geyser switches its auth type to floodgate by itself when Floodgate is installed
+func bedrockPluginURLs(server *minecraftv2.MinecraftServer) []string {
+	if !bedrockEnabled(server) {
+		return nil
+	}
+	urls := []string{geyserURL}
+	if server.Spec.Bedrock.Floodgate == nil || *server.Spec.Bedrock.Floodgate {
+		urls = append(urls, floodgateURL)
+	}
+	return urls
+}
+
+// bedrockServicePort is the Service port Bedrock clients connect to
+func bedrockServicePort(server *minecraftv2.MinecraftServer) int32 {
+	if server.Spec.Bedrock.Port > 0 {
+		return server.Spec.Bedrock.Port
+	}
+	return bedrockContainerPort
+}
+
+// buildBedrockContainerPort is Geyser's UDP port on the server container
+func buildBedrockContainerPort() corev1.ContainerPort {
+	return corev1.ContainerPort{
+		Name:          bedrockPortName,
+		ContainerPort: bedrockContainerPort,
+		Protocol:      corev1.ProtocolUDP,
+	}
+}
+
+// buildBedrockServicePort is the UDP port on the game Service, keeping an allocated node port
+func buildBedrockServicePort(server *minecraftv2.MinecraftServer, nodePort int32) corev1.ServicePort {
+	return corev1.ServicePort{
+		Name:       bedrockPortName,
+		Protocol:   corev1.ProtocolUDP,
+		Port:       bedrockServicePort(server),
+		TargetPort: intstr.FromInt(bedrockContainerPort),
+		NodePort:   nodePort,
+	}
+}
+
+// bedrockEndpoint returns the host:port Bedrock clients connect to, or "" until the load balancer has an address
+// The router only speaks the Java protocol, so a routing hostname doesn't make the Bedrock port reachable
+func bedrockEndpoint(server *minecraftv2.MinecraftServer, service *corev1.Service, externalIP string) string {
+	if !bedrockEnabled(server) || externalIP == "" {
+		return ""
+	}
+	for _, port := range service.Spec.Ports {
+		if port.Name == bedrockPortName {
+			return fmt.Sprintf("%s:%d", externalIP, port.Port)
+		}
+	}
+	return ""
+}
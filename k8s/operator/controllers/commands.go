@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/events"
+)
+
+// backupStorageClaim is the shared PVC backup archives are written to (see k8s/manifests/dev/backup-storage.yaml)
+const backupStorageClaim = "minecraft-backups"
+
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=delete
+// +kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
+
+// HandleCommand executes a server command received over NATS
+// The operator is the single executor for start/stop/restart/rcon/backup so the api-server stays stateless
+func (r *MinecraftServerReconciler) HandleCommand(ctx context.Context, cmd *events.ServerCommand) (string, error) {
+	logger := log.FromContext(ctx).WithValues("serverID", cmd.ServerID, "tenantID", cmd.TenantID, "action", cmd.Action)
+
+	server, err := r.findServerByID(ctx, cmd.ServerID)
+	if err != nil {
+		return "", err
+	}
+
+	// SECURITY: the tenant in the subject must own the server
+	if server.Spec.TenantID != cmd.TenantID {
+		logger.Info("Rejected command for server owned by another tenant")
+		return "", events.ErrCommandForbidden
+	}
+
+	logger.Info("Executing server command")
+
+	switch cmd.Action {
+	case events.CommandStart:
+		return r.startServer(ctx, server)
+	case events.CommandStop:
+		return r.stopServer(ctx, server)
+	case events.CommandRestart:
+		return r.restartServer(ctx, server)
+	case events.CommandRCON:
+		return r.runConsoleCommand(ctx, server, cmd.Command)
+	case events.CommandBackup:
+		return r.startBackupJob(ctx, server)
+	default:
+		return "", fmt.Errorf("unknown action: %s", cmd.Action)
+	}
+}
+
+// findServerByID looks up the MinecraftServer with the given spec.serverId across all namespaces
+func (r *MinecraftServerReconciler) findServerByID(ctx context.Context, serverID string) (*minecraftv2.MinecraftServer, error) {
+	var servers minecraftv2.MinecraftServerList
+	if err := r.List(ctx, &servers); err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	for i := range servers.Items {
+		if servers.Items[i].Spec.ServerID == serverID {
+			return &servers.Items[i], nil
+		}
+	}
+
+	return nil, events.ErrServerNotFound
+}
+
+// startServer wakes a sleeping Auto server, or powers an Off server back on
+func (r *MinecraftServerReconciler) startServer(ctx context.Context, server *minecraftv2.MinecraftServer) (string, error) {
+	switch server.Spec.EffectivePowerState() {
+	case minecraftv2.PowerOn:
+		return "Server is already started", nil
+	case minecraftv2.PowerAuto:
+		woken, err := r.wakeServer(ctx, server, minecraftv2.WakeReasonCommand)
+		if err != nil {
+			return "", err
+		}
+		if !woken {
+			return "Server is already started", nil
+		}
+		return "Server woken", nil
+	}
+
+	return r.setPowerState(ctx, server, startPowerState(server))
+}
+
+// stopServer switches the server Off; unlike sleeping, players can't wake it
+func (r *MinecraftServerReconciler) stopServer(ctx context.Context, server *minecraftv2.MinecraftServer) (string, error) {
+	if server.Spec.EffectivePowerState() == minecraftv2.PowerOff {
+		return "Server is already stopped", nil
+	}
+	return r.setPowerState(ctx, server, minecraftv2.PowerOff)
+}
+
+// setPowerState patches spec.powerState, keeping the deprecated spec.stopped in step for older clients
+func (r *MinecraftServerReconciler) setPowerState(ctx context.Context, server *minecraftv2.MinecraftServer, state minecraftv2.PowerState) (string, error) {
+	patch := client.MergeFrom(server.DeepCopy())
+	server.Spec.PowerState = state
+	server.Spec.Stopped = state == minecraftv2.PowerOff
+	if err := r.Patch(ctx, server, patch); err != nil {
+		return "", fmt.Errorf("failed to update server: %w", err)
+	}
+
+	if state == minecraftv2.PowerOff {
+		return "Server stopped", nil
+	}
+	return "Server started", nil
+}
+
+// restartServer deletes the server pod so the StatefulSet recreates it
+func (r *MinecraftServerReconciler) restartServer(ctx context.Context, server *minecraftv2.MinecraftServer) (string, error) {
+	if server.Status.Phase != minecraftv2.PhaseRunning && server.Status.Phase != minecraftv2.PhaseStarting && server.Status.Phase != minecraftv2.PhaseError {
+		return "", fmt.Errorf("server cannot be restarted while %s", strings.ToLower(string(server.Status.Phase)))
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-0", server.Name),
+			Namespace: server.Namespace,
+		},
+	}
+	if err := r.Delete(ctx, pod); err != nil {
+		if errors.IsNotFound(err) {
+			return "", fmt.Errorf("server pod not found")
+		}
+		return "", fmt.Errorf("failed to delete server pod: %w", err)
+	}
+
+	return "Server restarting", nil
+}
+
+// runConsoleCommand runs a console command through rcon-cli and returns its output
+func (r *MinecraftServerReconciler) runConsoleCommand(ctx context.Context, server *minecraftv2.MinecraftServer, command string) (string, error) {
+	if server.Status.Phase != minecraftv2.PhaseRunning {
+		return "", fmt.Errorf("server is not running")
+	}
+
+	stdout, stderr, err := r.execRconCli(ctx, server, strings.Fields(command)...)
+	if err != nil {
+		if stderr != "" {
+			return stdout, fmt.Errorf("rcon-cli failed: %s", strings.TrimSpace(stderr))
+		}
+		return stdout, fmt.Errorf("rcon-cli failed: %w", err)
+	}
+
+	return strings.TrimSpace(stdout), nil
+}
+
+// startBackupJob flushes the world to disk and starts a Job that archives the data volume
+// The Job mirrors the one created by the api-server backup service so archives land in the same place
+func (r *MinecraftServerReconciler) startBackupJob(ctx context.Context, server *minecraftv2.MinecraftServer) (string, error) {
+	backupID := uuid.New().String()
+	jobName := fmt.Sprintf("backup-%s-%s", server.Spec.ServerID, backupID[:8])
+
+	if err := r.createBackupJob(ctx, server, jobName, backupID); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Backup %s started (job %s, archive %s)", backupID, jobName, backupFilename(server, backupID)), nil
+}
+
+// createBackupJob flushes the world to disk and creates the backup Job jobName
+func (r *MinecraftServerReconciler) createBackupJob(ctx context.Context, server *minecraftv2.MinecraftServer, jobName, backupID string) error {
+	logger := log.FromContext(ctx)
+
+	// Best-effort flush so the archive contains a consistent world
+	if server.Status.Phase == minecraftv2.PhaseRunning {
+		if _, stderr, err := r.execRconCli(ctx, server, "save-all", "flush"); err != nil {
+			logger.Info("Could not flush world before backup", "error", err, "stderr", stderr)
+		}
+	}
+
+	job := buildBackupJob(server, jobName, backupID)
+	if err := controllerutil.SetControllerReference(server, job, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, job); err != nil {
+		return fmt.Errorf("failed to create backup job: %w", err)
+	}
+	return nil
+}
+
+// backupFilename is the archive a backup is written to on the backup storage
+func backupFilename(server *minecraftv2.MinecraftServer, backupID string) string {
+	return fmt.Sprintf("%s-%s.tar.gz", server.Spec.ServerID, backupID)
+}
+
+// buildBackupJob creates a Job that archives the data volume to the backup storage
+func buildBackupJob(server *minecraftv2.MinecraftServer, jobName, backupID string) *batchv1.Job {
+	archive := backupFilename(server, backupID)
+	ttl := int32(3600) // Cleanup after 1 hour
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: server.Namespace,
+			Labels: map[string]string{
+				"app":       "minecraft-backup",
+				"backup-id": backupID,
+				"server-id": server.Spec.ServerID,
+				"tenant":    server.Spec.TenantID,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "backup",
+							Image:   "alpine:latest",
+							Command: backupCommand(archive),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "minecraft-data",
+									MountPath: "/data",
+									ReadOnly:  true,
+								},
+								{
+									Name:      "backup-storage",
+									MountPath: "/backups",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "minecraft-data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									// StatefulSet PVC naming: <volumeClaimTemplate-name>-<pod-name>
+									ClaimName: fmt.Sprintf("minecraft-data-%s-0", server.Name),
+								},
+							},
+						},
+						backupStorageVolume(false),
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/events"
+)
+
+func commandServer() *minecraftv2.MinecraftServer {
+	return &minecraftv2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "tenant-a"},
+		Spec: minecraftv2.MinecraftServerSpec{
+			ServerID:   "server-1",
+			TenantID:   "tenant-a",
+			PowerState: minecraftv2.PowerOn,
+		},
+	}
+}
+
+func TestHandleCommandChecksOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     events.ServerCommand
+		wantErr error
+		output  string
+	}{
+		{"owner", events.ServerCommand{TenantID: "tenant-a", ServerID: "server-1", Action: events.CommandStop}, nil, "Server stopped"},
+		{"other tenant", events.ServerCommand{TenantID: "tenant-b", ServerID: "server-1", Action: events.CommandStop}, events.ErrCommandForbidden, ""},
+		{"unknown server", events.ServerCommand{TenantID: "tenant-a", ServerID: "server-2", Action: events.CommandStop}, events.ErrServerNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(commandServer()).Build()
+			r := &MinecraftServerReconciler{Client: c, Scheme: testScheme}
+
+			output, err := r.HandleCommand(context.Background(), &tt.cmd)
+			if !errors.Is(err, tt.wantErr) || output != tt.output {
+				t.Fatalf("HandleCommand = %q, %v; want %q, %v", output, err, tt.output, tt.wantErr)
+			}
+
+			var server minecraftv2.MinecraftServer
+			if err := c.Get(context.Background(), types.NamespacedName{Name: "survival", Namespace: "tenant-a"}, &server); err != nil {
+				t.Fatal(err)
+			}
+			stopped := server.Spec.PowerState == minecraftv2.PowerOff
+			if stopped != (tt.wantErr == nil) {
+				t.Errorf("unexpected power state %s", server.Spec.PowerState)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// backupOverdueAfter is how old the last backup may get before BackupHealthy turns False
+// Backups default to a daily schedule, so this allows one missed run plus slack
+const backupOverdueAfter = 26 * time.Hour
+
+// setCondition sets a condition stamped with the server's current generation
+func setCondition(server *minecraftv2.MinecraftServer, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&server.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: server.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// boolStatus converts a bool into a condition status
+func boolStatus(value bool) metav1.ConditionStatus {
+	if value {
+		return metav1.ConditionTrue
+	}
+	return metav1.ConditionFalse
+}
+
+// setServerConditions derives every condition from the observed StatefulSet and the computed phase
+// errorReason is the reason code reported for PhaseError (empty otherwise)
+func setServerConditions(server *minecraftv2.MinecraftServer, statefulSet *appsv1.StatefulSet, phase minecraftv2.ServerPhase, errorReason, message string) {
+	desired := int32(0)
+	if statefulSet.Spec.Replicas != nil {
+		desired = *statefulSet.Spec.Replicas
+	}
+	ready := statefulSet.Status.ReadyReplicas
+	rollingOut := statefulSet.Status.UpdateRevision != "" &&
+		statefulSet.Status.CurrentRevision != statefulSet.Status.UpdateRevision
+
+	// Ready: all desired replicas ready and the server is manageable
+	readyReason := string(phase)
+	if phase == minecraftv2.PhaseError && errorReason != "" {
+		readyReason = errorReason
+	}
+	setCondition(server, minecraftv2.ConditionReady, boolStatus(phase == minecraftv2.PhaseRunning), readyReason, message)
+
+	// Available: at least one replica serving players, even if degraded
+	if ready > 0 {
+		setCondition(server, minecraftv2.ConditionAvailable, metav1.ConditionTrue, "ReplicasReady",
+			fmt.Sprintf("%d/%d replicas ready", ready, desired))
+	} else {
+		setCondition(server, minecraftv2.ConditionAvailable, metav1.ConditionFalse, "NoReplicasReady",
+			fmt.Sprintf("0/%d replicas ready", desired))
+	}
+
+	// Progressing: moving between states or rolling out a new pod template
+	switch {
+	case phase == minecraftv2.PhaseStarting || phase == minecraftv2.PhasePending:
+		setCondition(server, minecraftv2.ConditionProgressing, metav1.ConditionTrue, "Starting", message)
+	case phase == minecraftv2.PhaseStopping:
+		setCondition(server, minecraftv2.ConditionProgressing, metav1.ConditionTrue, "Stopping", message)
+	case rollingOut:
+		setCondition(server, minecraftv2.ConditionProgressing, metav1.ConditionTrue, "RollingUpdate",
+			fmt.Sprintf("Updating to revision %s", statefulSet.Status.UpdateRevision))
+	default:
+		setCondition(server, minecraftv2.ConditionProgressing, metav1.ConditionFalse, "Stable", "No changes in progress")
+	}
+
+	// Degraded: the server is failing
+	if phase == minecraftv2.PhaseError {
+		setCondition(server, minecraftv2.ConditionDegraded, metav1.ConditionTrue, readyReason, message)
+	} else {
+		setCondition(server, minecraftv2.ConditionDegraded, metav1.ConditionFalse, "AsExpected", "Server is healthy")
+	}
+
+	// ConfigApplied: child resources reconciled for this generation and pods on the latest template
+	if rollingOut {
+		setCondition(server, minecraftv2.ConditionConfigApplied, metav1.ConditionFalse, "RolloutPending",
+			"Configuration applied, waiting for the pod to restart")
+	} else {
+		setCondition(server, minecraftv2.ConditionConfigApplied, metav1.ConditionTrue, "Applied",
+			fmt.Sprintf("Generation %d applied", server.Generation))
+	}
+
+	setBackupCondition(server)
+	setPluginsCondition(server)
+}
+
+// setReconcileFailedConditions records a failure to reconcile child resources
+func setReconcileFailedConditions(server *minecraftv2.MinecraftServer, reason, message string) {
+	setCondition(server, minecraftv2.ConditionConfigApplied, metav1.ConditionFalse, reason, message)
+	setCondition(server, minecraftv2.ConditionDegraded, metav1.ConditionTrue, reason, message)
+	setCondition(server, minecraftv2.ConditionReady, metav1.ConditionFalse, reason, message)
+}
+
+// setBackupCondition reports whether a recent backup exists; the condition is removed when backups are disabled
+func setBackupCondition(server *minecraftv2.MinecraftServer) {
+	if server.Spec.Backup == nil || !server.Spec.Backup.Enabled {
+		meta.RemoveStatusCondition(&server.Status.Conditions, minecraftv2.ConditionBackupHealthy)
+		return
+	}
+
+	switch {
+	case server.Status.LastBackup == nil:
+		setCondition(server, minecraftv2.ConditionBackupHealthy, metav1.ConditionUnknown, "NoBackupYet",
+			"No successful backup has been recorded")
+	case time.Since(server.Status.LastBackup.Time) > backupOverdueAfter:
+		setCondition(server, minecraftv2.ConditionBackupHealthy, metav1.ConditionFalse, "BackupOverdue",
+			fmt.Sprintf("Last successful backup was at %s", server.Status.LastBackup.UTC().Format(time.RFC3339)))
+	default:
+		setCondition(server, minecraftv2.ConditionBackupHealthy, metav1.ConditionTrue, "BackupRecent",
+			fmt.Sprintf("Last successful backup was at %s", server.Status.LastBackup.UTC().Format(time.RFC3339)))
+	}
+}
+
+// setPluginsCondition compares enabled plugins in the spec with the installed plugins in status
+func setPluginsCondition(server *minecraftv2.MinecraftServer) {
+	installed := map[string]bool{}
+	for _, plugin := range server.Status.InstalledPlugins {
+		if plugin.Enabled {
+			installed[plugin.Name] = true
+		}
+	}
+
+	var missing []string
+	for _, plugin := range server.Spec.Plugins {
+		if pointer.BoolDeref(plugin.Enabled, true) && !installed[plugin.Name] {
+			missing = append(missing, plugin.Name)
+		}
+	}
+
+	switch {
+	case len(server.Spec.Plugins) == 0:
+		setCondition(server, minecraftv2.ConditionPluginsReady, metav1.ConditionTrue, "NoPlugins", "No plugins requested")
+	case len(missing) > 0:
+		setCondition(server, minecraftv2.ConditionPluginsReady, metav1.ConditionFalse, "PluginsPending",
+			fmt.Sprintf("Plugins not installed: %s", strings.Join(missing, ", ")))
+	default:
+		setCondition(server, minecraftv2.ConditionPluginsReady, metav1.ConditionTrue, "AllInstalled", "All enabled plugins are installed")
+	}
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/events"
+)
+
+// pvcPendingGracePeriod is how long a PVC may stay Pending before it is reported as an error
+// (WaitForFirstConsumer storage classes keep claims Pending until the pod is scheduled)
+const pvcPendingGracePeriod = 2 * time.Minute
+
+// diagnoseServerError inspects the StatefulSet conditions, the server pod and its PVC
+// Returns a reason code and message, or empty strings if nothing is failing
+func (r *MinecraftServerReconciler) diagnoseServerError(ctx context.Context, server *minecraftv2.MinecraftServer, statefulSet *appsv1.StatefulSet) (string, string) {
+	logger := log.FromContext(ctx)
+
+	// Quota rejections surface as StatefulSet conditions since the pod is never created
+	for _, cond := range statefulSet.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue && isQuotaMessage(cond.Message) {
+			return events.ReasonQuotaExceeded, cond.Message
+		}
+	}
+
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      fmt.Sprintf("%s-0", server.Name),
+		Namespace: server.Namespace,
+	}, pod); err != nil {
+		if !errors.IsNotFound(err) {
+			logger.V(1).Info("Could not get pod for diagnosis", "error", err)
+		}
+		return "", ""
+	}
+
+	// Container waiting reasons (init containers first, they block the main container)
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if cs.State.Waiting == nil {
+			continue
+		}
+		switch cs.State.Waiting.Reason {
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+			return events.ReasonImagePullBackOff, fmt.Sprintf("Container %s cannot pull image: %s", cs.Name, cs.State.Waiting.Message)
+		case "CrashLoopBackOff":
+			return events.ReasonCrashLoopBackOff, fmt.Sprintf("Container %s is crash looping (%d restarts)", cs.Name, cs.RestartCount)
+		}
+	}
+
+	if pod.Status.Phase != corev1.PodPending {
+		return "", ""
+	}
+
+	// Unschedulable pods waiting on storage
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse &&
+			strings.Contains(strings.ToLower(cond.Message), "persistentvolumeclaim") {
+			return events.ReasonPVCPending, cond.Message
+		}
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      fmt.Sprintf("minecraft-data-%s-0", server.Name),
+		Namespace: server.Namespace,
+	}, pvc); err == nil {
+		if pvc.Status.Phase == corev1.ClaimPending && time.Since(pvc.CreationTimestamp.Time) > pvcPendingGracePeriod {
+			return events.ReasonPVCPending, fmt.Sprintf("PersistentVolumeClaim %s has been pending for %s", pvc.Name, time.Since(pvc.CreationTimestamp.Time).Round(time.Second))
+		}
+	}
+
+	return "", ""
+}
+
+// reconcileErrorReason maps an error returned while reconciling child resources to a reason code
+func reconcileErrorReason(err error) string {
+	if errors.IsForbidden(err) && isQuotaMessage(err.Error()) {
+		return events.ReasonQuotaExceeded
+	}
+	return events.ReasonReconcileFailed
+}
+
+// isQuotaMessage returns true if an API message reports a ResourceQuota rejection
+func isQuotaMessage(message string) bool {
+	return strings.Contains(message, "exceeded quota")
+}
+
+// publishServerError publishes an error event for the server if event publishing is enabled
+func (r *MinecraftServerReconciler) publishServerError(ctx context.Context, server *minecraftv2.MinecraftServer, reason, message string) {
+	if r.EventPublisher == nil {
+		return
+	}
+
+	if err := r.EventPublisher.PublishServerError(
+		server.Spec.ServerID,
+		server.Spec.TenantID,
+		server.Namespace,
+		server.Name,
+		reason,
+		message,
+	); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to publish server error event", "reason", reason)
+	}
+}
@@ -0,0 +1,248 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+const (
+	// forwardingPatchKey is the server ConfigMap item holding the mc-image-helper patch set that enables proxy forwarding
+	forwardingPatchKey = "proxy-forwarding.json"
+
+	// forwardingPatchDir is where the patch set is mounted in the server container
+	forwardingPatchDir = "/patches"
+
+	// forwardingSeedDir is where the seed files are mounted, below the patch set
+	forwardingSeedDir = forwardingPatchDir + "/seed"
+
+	// forwardingSeedScript copies each seed file ($1) to its target ($2) unless the target exists
+	// The files are handed to the itzg image's default user, which the server drops to
+	forwardingSeedScript = `while [ $# -gt 0 ]; do if [ ! -e "$2" ]; then mkdir -p "$(dirname "$2")" && cp "$1" "$2" && chown 1000:1000 "$(dirname "$2")" "$2" || exit 1; fi; shift 2; done`
+
+	// velocitySecretEnv is substituted into paper-global.yml by the patch set; itzg only replaces CFG_ variables
+	velocitySecretEnv = "CFG_VELOCITY_SECRET"
+)
+
+// networkFor returns the network the server is a member of, or nil
+// A server selected by several networks joins the oldest one
+func (r *MinecraftServerReconciler) networkFor(ctx context.Context, server *minecraftv2.MinecraftServer) (*minecraftv2.MinecraftNetwork, error) {
+	if !minecraftv2.SupportsPlugins(server.Spec.ServerType) {
+		return nil, nil
+	}
+
+	var networks minecraftv2.MinecraftNetworkList
+	if err := r.List(ctx, &networks, client.InNamespace(server.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list networks: %w", err)
+	}
+
+	var candidates []*minecraftv2.MinecraftNetwork
+	for i := range networks.Items {
+		network := &networks.Items[i]
+		if network.DeletionTimestamp.IsZero() && isNetworkMember(network, server) {
+			candidates = append(candidates, network)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+	return candidates[0], nil
+}
+
+// patchSet is the mc-image-helper PATCH_DEFINITIONS file format
+type patchSet struct {
+	Patches []filePatch `json:"patches"`
+}
+
+type filePatch struct {
+	File string                  `json:"file"`
+	Ops  []map[string]patchSetOp `json:"ops"`
+}
+
+type patchSetOp struct {
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// setOp is a $set patch operation
+func setOp(path string, value interface{}) map[string]patchSetOp {
+	return map[string]patchSetOp{"$set": {Path: path, Value: value}}
+}
+
+// forwardingPatchSet enables player info forwarding from the network's proxy
+// The patched files only exist once the server has started, so they are seeded before the first start
+func forwardingPatchSet(network *minecraftv2.MinecraftNetwork) patchSet {
+	var set patchSet
+	if network.Spec.UsesModernForwarding() {
+		set.Patches = []filePatch{{
+			File: "/data/config/paper-global.yml",
+			Ops: []map[string]patchSetOp{
+				setOp("$.proxies.velocity.enabled", true),
+				setOp("$.proxies.velocity.online-mode", true),
+				setOp("$.proxies.velocity.secret", fmt.Sprintf("${%s}", velocitySecretEnv)),
+			},
+		}}
+	} else {
+		set.Patches = []filePatch{
+			{
+				File: "/data/spigot.yml",
+				Ops:  []map[string]patchSetOp{setOp("$.settings.bungeecord", true)},
+			},
+			{
+				File: "/data/config/paper-global.yml",
+				Ops:  []map[string]patchSetOp{setOp("$.proxies.bungee-cord.online-mode", true)},
+			},
+		}
+	}
+
+	return set
+}
+
+// buildForwardingPatch renders the forwarding patch set
+func buildForwardingPatch(network *minecraftv2.MinecraftNetwork) (string, error) {
+	content, err := json.MarshalIndent(forwardingPatchSet(network), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render forwarding patch: %w", err)
+	}
+	return string(content), nil
+}
+
+// forwardingSeedKey is the server ConfigMap item seeding file before the first start
+func forwardingSeedKey(file string) string {
+	return "proxy-forwarding-seed-" + path.Base(file)
+}
+
+// buildForwardingSeeds renders a seed for every file the patch set edits, keyed by ConfigMap item
+// A seed holds just the patched settings; the server adds its defaults on the first start,
+// and the patch set runs before that start, so the secret placeholder never reaches the server
+func buildForwardingSeeds(network *minecraftv2.MinecraftNetwork) (map[string]string, error) {
+	seeds := map[string]string{}
+	for _, patch := range forwardingPatchSet(network).Patches {
+		content := map[string]interface{}{}
+		for _, op := range patch.Ops {
+			for _, set := range op {
+				setPath(content, strings.Split(strings.TrimPrefix(set.Path, "$."), "."), set.Value)
+			}
+		}
+		data, err := yaml.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render forwarding seed: %w", err)
+		}
+		seeds[forwardingSeedKey(patch.File)] = string(data)
+	}
+	return seeds, nil
+}
+
+// setPath sets value at the nested keys of content, creating the maps in between
+func setPath(content map[string]interface{}, keys []string, value interface{}) {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := content[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			content[key] = next
+		}
+		content = next
+	}
+	content[keys[len(keys)-1]] = value
+}
+
+// forwardingEnv puts a member behind its proxy: the proxy authenticates players, so the server runs in offline mode
+func forwardingEnv(network *minecraftv2.MinecraftNetwork) []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "PATCH_DEFINITIONS", Value: fmt.Sprintf("%s/%s", forwardingPatchDir, forwardingPatchKey)},
+	}
+	if network.Spec.UsesModernForwarding() {
+		env = append(env, corev1.EnvVar{
+			Name: velocitySecretEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: forwardingSecretName(network)},
+					Key:                  forwardingSecretKey,
+				},
+			},
+		})
+	}
+	return env
+}
+
+// applyForwarding wires a member's pod to its network's forwarding patch and secret
+func applyForwarding(podSpec *corev1.PodSpec, server *minecraftv2.MinecraftServer, network *minecraftv2.MinecraftNetwork) {
+	container := &podSpec.Containers[0]
+	for i := range container.Env {
+		if container.Env[i].Name == "ONLINE_MODE" {
+			container.Env[i].Value = "false"
+		}
+	}
+	container.Env = append(container.Env, forwardingEnv(network)...)
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "proxy-forwarding",
+		MountPath: forwardingPatchDir,
+		ReadOnly:  true,
+	})
+
+	// Seed the patched files, so forwarding is on from the first start
+	items := []corev1.KeyToPath{{Key: forwardingPatchKey, Path: forwardingPatchKey}}
+	args := []string{"/bin/sh", "-c", forwardingSeedScript, "seed"}
+	for _, patch := range forwardingPatchSet(network).Patches {
+		key := forwardingSeedKey(patch.File)
+		items = append(items, corev1.KeyToPath{Key: key, Path: path.Join("seed", key)})
+		args = append(args, path.Join(forwardingSeedDir, key), patch.File)
+	}
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:    "seed-forwarding",
+		Image:   "alpine:latest",
+		Command: args,
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "minecraft-data", MountPath: "/data"},
+			{Name: "proxy-forwarding", MountPath: forwardingPatchDir, ReadOnly: true},
+		},
+	})
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "proxy-forwarding",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: fmt.Sprintf("%s-config", server.Name)},
+				Items:                items,
+			},
+		},
+	})
+}
+
+// serversForNetwork maps a network event to every server in its namespace,
+// so servers also leave a network whose selector stopped matching them
+func (r *MinecraftServerReconciler) serversForNetwork(ctx context.Context, obj client.Object) []reconcile.Request {
+	var servers minecraftv2.MinecraftServerList
+	if err := r.List(ctx, &servers, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list servers for network", "network", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(servers.Items))
+	for _, server := range servers.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: server.Name, Namespace: server.Namespace},
+		})
+	}
+	return requests
+}
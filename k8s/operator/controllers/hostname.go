@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// hostnameSource is where a server's routing hostname comes from
+type hostnameSource int
+
+const (
+	// hostnameNone means the server isn't routed by hostname
+	hostnameNone hostnameSource = iota
+
+	// hostnameShared is the operator's --default-router-hostname, used by every server alike
+	hostnameShared
+
+	// hostnameSpec is spec.network.hostname
+	hostnameSpec
+
+	// hostnameDerived is <displayName>.<tenant>.<--router-domain>
+	hostnameDerived
+)
+
+// nonLabelChars matches runs of characters that can't appear in a DNS label
+var nonLabelChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify turns a display name or tenant ID into a DNS label, or "" if nothing usable is left
+func slugify(value string) string {
+	slug := nonLabelChars.ReplaceAllString(strings.ToLower(value), "-")
+	slug = strings.Trim(slug, "-")
+	if len(slug) > validation.DNS1123LabelMaxLength {
+		slug = strings.TrimRight(slug[:validation.DNS1123LabelMaxLength], "-")
+	}
+	return slug
+}
+
+// tenantHashLength is the number of hex digits of the tenant ID hash in a tenant label
+const tenantHashLength = 8
+
+// tenantLabel turns a tenant ID into the DNS label of its subdomain
+// IDs that aren't already a DNS label get a short hash of the raw ID appended, so IDs that slugify
+// alike ("Acme_Co", "acme-co") still get different subdomains
+func tenantLabel(tenantID string) string {
+	if len(validation.IsDNS1123Label(tenantID)) == 0 {
+		return tenantID
+	}
+	sum := sha256.Sum256([]byte(tenantID))
+	hash := hex.EncodeToString(sum[:])[:tenantHashLength]
+
+	slug := slugify(tenantID)
+	if maxLength := validation.DNS1123LabelMaxLength - tenantHashLength - 1; len(slug) > maxLength {
+		slug = strings.TrimRight(slug[:maxLength], "-")
+	}
+	if slug == "" {
+		return hash
+	}
+	return slug + "-" + hash
+}
+
+// candidateHostname returns the routing hostname the server asks for and where it comes from
+func (r *MinecraftServerReconciler) candidateHostname(server *minecraftv2.MinecraftServer) (string, hostnameSource) {
+	if server.Spec.Network != nil && server.Spec.Network.Hostname != "" {
+		return server.Spec.Network.Hostname, hostnameSpec
+	}
+
+	if r.Network.Domain != "" {
+		// Display names without any ASCII letters or digits fall back to the object name
+		name := slugify(server.Spec.DisplayName)
+		if name == "" {
+			name = server.Name
+		}
+		return fmt.Sprintf("%s.%s.%s", name, tenantLabel(server.Spec.TenantID), r.Network.Domain), hostnameDerived
+	}
+
+	if r.Network.Hostname != "" {
+		return r.Network.Hostname, hostnameShared
+	}
+
+	return "", hostnameNone
+}
+
+// reconcileHostname resolves the server's routing hostname, records it in status and reports conflicts
+// Returns the hostname to route, or "" when it is invalid or already used by an older server
+func (r *MinecraftServerReconciler) reconcileHostname(ctx context.Context, server *minecraftv2.MinecraftServer) (string, error) {
+	hostname, source := r.candidateHostname(server)
+
+	switch source {
+	case hostnameNone, hostnameShared:
+		meta.RemoveStatusCondition(&server.Status.Conditions, minecraftv2.ConditionHostnameAssigned)
+		server.Status.Hostname = hostname
+		return hostname, nil
+	}
+
+	reason, message, err := r.checkHostname(ctx, server, hostname, source)
+	if err != nil {
+		return "", err
+	}
+	if reason != "" {
+		log.FromContext(ctx).Info("Hostname not assigned", "hostname", hostname, "reason", reason)
+		setCondition(server, minecraftv2.ConditionHostnameAssigned, metav1.ConditionFalse, reason, message)
+		server.Status.Hostname = ""
+		return "", nil
+	}
+
+	setCondition(server, minecraftv2.ConditionHostnameAssigned, metav1.ConditionTrue, "Assigned",
+		fmt.Sprintf("Players connect with %s", hostname))
+	server.Status.Hostname = hostname
+	return hostname, nil
+}
+
+// checkHostname returns a condition reason and message when hostname can't be assigned to server
+func (r *MinecraftServerReconciler) checkHostname(ctx context.Context, server *minecraftv2.MinecraftServer, hostname string, source hostnameSource) (string, string, error) {
+	if msgs := validation.IsDNS1123Subdomain(hostname); len(msgs) > 0 {
+		return "HostnameInvalid", fmt.Sprintf("Hostname %s is invalid: %s", hostname, strings.Join(msgs, "; ")), nil
+	}
+
+	// Explicit hostnames under the router domain must stay inside the tenant's own subdomain
+	if source == hostnameSpec && r.Network.Domain != "" {
+		domain := "." + r.Network.Domain
+		tenantDomain := "." + tenantLabel(server.Spec.TenantID) + domain
+		if (hostname == r.Network.Domain || strings.HasSuffix(hostname, domain)) && !strings.HasSuffix(hostname, tenantDomain) {
+			return "HostnameOutsideTenant", fmt.Sprintf("Hostname %s must end with %s", hostname, tenantDomain), nil
+		}
+	}
+
+	var servers minecraftv2.MinecraftServerList
+	if err := r.List(ctx, &servers); err != nil {
+		return "", "", fmt.Errorf("failed to list servers for hostname check: %w", err)
+	}
+
+	for i := range servers.Items {
+		other := &servers.Items[i]
+		if other.UID == server.UID || !other.DeletionTimestamp.IsZero() || !createdBefore(other, server) {
+			continue
+		}
+		if otherHostname, otherSource := r.candidateHostname(other); otherSource >= hostnameSpec && otherHostname == hostname {
+			// Don't reveal another tenant's server names
+			owner := "another server"
+			if other.Spec.TenantID == server.Spec.TenantID {
+				owner = fmt.Sprintf("server %q", other.Spec.DisplayName)
+			}
+			return "HostnameConflict", fmt.Sprintf("Hostname %s is already used by %s", hostname, owner), nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// createdBefore reports whether a was created before b, ordering by namespace and name on equal timestamps
+func createdBefore(a, b *minecraftv2.MinecraftServer) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+func TestTenantLabel(t *testing.T) {
+	tests := []struct {
+		tenantID string
+		prefix   string
+	}{
+		{"acme-co", "acme-co"},
+		{"3f2b9c1e-7d4a-4b8e-9c2f-1a2b3c4d5e6f", "3f2b9c1e-7d4a-4b8e-9c2f-1a2b3c4d5e6f"},
+		{"Acme_Co", "acme-co-"},
+		{"ACME-CO", "acme-co-"},
+		{"__", ""},
+		{strings.Repeat("Tenant", 20), "tenanttenant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tenantID, func(t *testing.T) {
+			label := tenantLabel(tt.tenantID)
+			if msgs := validation.IsDNS1123Label(label); len(msgs) > 0 {
+				t.Fatalf("%q is not a DNS label: %v", label, msgs)
+			}
+			if !strings.HasPrefix(label, tt.prefix) {
+				t.Errorf("expected %q to start with %q", label, tt.prefix)
+			}
+		})
+	}
+}
+
+func TestTenantLabelsDoNotCollide(t *testing.T) {
+	seen := map[string]string{}
+	for _, tenantID := range []string{"acme-co", "Acme_Co", "acme_co", "ACME CO", "acme--co", "Acme.Co"} {
+		label := tenantLabel(tenantID)
+		if other, ok := seen[label]; ok {
+			t.Errorf("%q and %q both map to %q", tenantID, other, label)
+		}
+		seen[label] = tenantID
+	}
+}
+
+// hostnameServer is a "Survival" server of tenantID created at the given second
+func hostnameServer(name, tenantID string, created int64) *minecraftv2.MinecraftServer {
+	return &minecraftv2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.Unix(created, 0),
+		},
+		Spec: minecraftv2.MinecraftServerSpec{ServerID: name, TenantID: tenantID, DisplayName: "Survival"},
+	}
+}
+
+func TestReconcileHostnameSeparatesSimilarTenants(t *testing.T) {
+	first := hostnameServer("first", "acme-co", 1)
+	second := hostnameServer("second", "Acme_Co", 2)
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(first, second).Build()
+	r := &MinecraftServerReconciler{Client: c, Scheme: testScheme, Network: NetworkDefaults{Domain: "play.example.com"}}
+
+	firstHostname, err := r.reconcileHostname(context.Background(), first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondHostname, err := r.reconcileHostname(context.Background(), second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstHostname != "survival.acme-co.play.example.com" {
+		t.Errorf("unexpected hostname %q", firstHostname)
+	}
+	if secondHostname == "" || secondHostname == firstHostname {
+		t.Errorf("expected a hostname of its own for Acme_Co, got %q", secondHostname)
+	}
+
+	// Acme_Co can't claim a hostname in acme-co's subdomain either
+	second.Spec.Network = &minecraftv2.NetworkConfig{Hostname: "lobby.acme-co.play.example.com"}
+	if hostname, err := r.reconcileHostname(context.Background(), second); err != nil || hostname != "" {
+		t.Fatalf("expected no hostname, got %q, %v", hostname, err)
+	}
+	if condition := meta.FindStatusCondition(second.Status.Conditions, minecraftv2.ConditionHostnameAssigned); condition == nil || condition.Reason != "HostnameOutsideTenant" {
+		t.Errorf("expected HostnameOutsideTenant, got %+v", condition)
+	}
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/events"
+)
+
+// serverImage is spec.image, or the default image when unset
+func serverImage(server *minecraftv2.MinecraftServer) string {
+	if server.Spec.Image == "" {
+		return minecraftv2.DefaultImage
+	}
+	return server.Spec.Image
+}
+
+// podImage is the image the pod runs: spec.image pinned to the recorded digest
+func podImage(server *minecraftv2.MinecraftServer) string {
+	image := serverImage(server)
+	if pinned := server.Status.Image; pinned != nil && pinned.Reference == image {
+		return pinned.PinnedImage()
+	}
+	return image
+}
+
+// reconcileImage pins spec.image to a digest and records it in Status.Image
+// A new or changed image is resolved right away. A newer digest of the same tag is recorded as LatestDigest
+// and only rolled out on request or, for Automatic updates, inside imageUpdate.window or the maintenance window.
+// The restart itself waits for the maintenance window like any other pod change. The caller writes the status.
+// An image that can't be resolved (a private registry, an air-gapped cluster) runs by tag with ImagePinned False.
+func (r *MinecraftServerReconciler) reconcileImage(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	logger := log.FromContext(ctx)
+	image := serverImage(server)
+	pinned := server.Status.Image
+
+	// Images pinned in the spec are used as they are
+	if _, digest, ok := strings.Cut(image, "@"); ok {
+		if pinned == nil || pinned.Reference != image {
+			now := metav1.Now()
+			server.Status.Image = &minecraftv2.ImageStatus{Reference: image, Digest: digest, PinnedAt: &now}
+		}
+		setCondition(server, minecraftv2.ConditionImagePinned, metav1.ConditionTrue, "PinnedInSpec",
+			fmt.Sprintf("Running %s", image))
+		return nil
+	}
+	if r.ImageResolver == nil {
+		server.Status.Image = nil
+		meta.RemoveStatusCondition(&server.Status.Conditions, minecraftv2.ConditionImagePinned)
+		return nil
+	}
+
+	digest, err := r.ImageResolver.Resolve(ctx, image)
+	if pinned == nil || pinned.Reference != image || pinned.Digest == "" {
+		if err != nil {
+			// Failing here would stop every server whose registry the resolver can't reach; run the tag instead
+			logger.Info("Could not pin image, running it by tag", "image", image, "error", err.Error())
+			server.Status.Image = &minecraftv2.ImageStatus{Reference: image}
+			message := fmt.Sprintf("Running %s by tag: %v", image, err)
+			setCondition(server, minecraftv2.ConditionImagePinned, metav1.ConditionFalse, events.ReasonImageResolveFailed, message)
+			r.warn(server, events.ReasonImageResolveFailed, message)
+			return nil
+		}
+		pinImage(server, image, digest)
+		logger.Info("Pinned image", "image", image, "digest", digest)
+		return nil
+	}
+	setCondition(server, minecraftv2.ConditionImagePinned, metav1.ConditionTrue, "Pinned",
+		fmt.Sprintf("Running %s", pinned.PinnedImage()))
+	if err != nil {
+		// A registry outage shouldn't affect a server that already has its digest
+		logger.Info("Could not check for image updates, keeping the pinned digest", "image", image, "error", err.Error())
+		return nil
+	}
+
+	_, requested := server.Annotations[minecraftv2.UpdateImageAnnotation]
+	if requested {
+		if err := r.removeAnnotation(ctx, server, minecraftv2.UpdateImageAnnotation); err != nil {
+			return err
+		}
+	}
+	if digest == pinned.Digest {
+		pinned.LatestDigest = ""
+		return nil
+	}
+	pinned.LatestDigest = digest
+
+	// A new digest restarts the server, which would disturb an upgrade's readiness checks
+	if upgrade := server.Status.Upgrade; upgrade != nil && upgrade.InProgress() {
+		return nil
+	}
+	if !requested {
+		update := server.Spec.ImageUpdate
+		if update == nil || update.Policy != minecraftv2.ImageUpdateAutomatic {
+			return nil
+		}
+		window := update.Window
+		if window == nil {
+			window = r.maintenanceWindow(server)
+		}
+		if window == nil {
+			return nil
+		}
+		open, err := window.Open(r.now())
+		if err != nil {
+			logger.Info("Invalid image update window, not updating", "error", err.Error())
+			return nil
+		}
+		if !open {
+			return nil
+		}
+	}
+
+	logger.Info("Rolling out new image digest", "image", image, "from", pinned.Digest, "to", digest)
+	pinImage(server, image, digest)
+	return nil
+}
+
+// pinImage records digest as the digest the server runs
+func pinImage(server *minecraftv2.MinecraftServer, image, digest string) {
+	now := metav1.Now()
+	server.Status.Image = &minecraftv2.ImageStatus{Reference: image, Digest: digest, PinnedAt: &now}
+	setCondition(server, minecraftv2.ConditionImagePinned, metav1.ConditionTrue, "Pinned",
+		fmt.Sprintf("Running %s", server.Status.Image.PinnedImage()))
+}
+
+// warn records a Warning event on the server; a no-op without an event recorder
+func (r *MinecraftServerReconciler) warn(server *minecraftv2.MinecraftServer, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(server, corev1.EventTypeWarning, reason, message)
+	}
+}
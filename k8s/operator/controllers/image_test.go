@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// fakeImageResolver returns a fixed digest, or err when set
+type fakeImageResolver struct {
+	digest string
+	err    error
+}
+
+func (f *fakeImageResolver) Resolve(_ context.Context, _ string) (string, error) {
+	return f.digest, f.err
+}
+
+func imageServer() *minecraftv2.MinecraftServer {
+	return &minecraftv2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "default"},
+		Spec:       minecraftv2.MinecraftServerSpec{Image: "registry.internal/minecraft:java21"},
+	}
+}
+
+func TestReconcileImageRunsTagWhenFirstPinFails(t *testing.T) {
+	recorder := record.NewFakeRecorder(1)
+	resolver := &fakeImageResolver{err: errors.New("401 Unauthorized")}
+	r := &MinecraftServerReconciler{ImageResolver: resolver, Recorder: recorder}
+	server := imageServer()
+
+	if err := r.reconcileImage(context.Background(), server); err != nil {
+		t.Fatalf("reconcileImage: %v", err)
+	}
+	if image := podImage(server); image != server.Spec.Image {
+		t.Errorf("expected the pod to run the tag, got %s", image)
+	}
+	if !meta.IsStatusConditionFalse(server.Status.Conditions, minecraftv2.ConditionImagePinned) {
+		t.Errorf("expected ImagePinned False, got %+v", server.Status.Conditions)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.HasPrefix(event, "Warning ImageResolveFailed") {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Error("expected a Warning event")
+	}
+
+	// The next reconcile pins once the registry answers
+	resolver.err = nil
+	resolver.digest = "sha256:aaa"
+	if err := r.reconcileImage(context.Background(), server); err != nil {
+		t.Fatalf("reconcileImage: %v", err)
+	}
+	if image := podImage(server); image != server.Spec.Image+"@sha256:aaa" {
+		t.Errorf("expected the pinned digest, got %s", image)
+	}
+	if !meta.IsStatusConditionTrue(server.Status.Conditions, minecraftv2.ConditionImagePinned) {
+		t.Errorf("expected ImagePinned True, got %+v", server.Status.Conditions)
+	}
+}
+
+func TestReconcileImageUsesReconcilerClock(t *testing.T) {
+	// Mondays 04:00-06:00 UTC
+	window := &minecraftv2.MaintenanceWindow{Schedule: "0 4 * * 1", Duration: "2h"}
+	clock := clocktesting.NewFakePassiveClock(time.Date(2024, time.March, 4, 5, 0, 0, 0, time.UTC))
+	r := &MinecraftServerReconciler{ImageResolver: &fakeImageResolver{digest: "sha256:bbb"}, Clock: clock}
+
+	server := imageServer()
+	server.Spec.ImageUpdate = &minecraftv2.ImageUpdateConfig{Policy: minecraftv2.ImageUpdateAutomatic, Window: window}
+	server.Status.Image = &minecraftv2.ImageStatus{Reference: server.Spec.Image, Digest: "sha256:aaa"}
+
+	if err := r.reconcileImage(context.Background(), server); err != nil {
+		t.Fatalf("reconcileImage: %v", err)
+	}
+	if server.Status.Image.Digest != "sha256:bbb" {
+		t.Errorf("expected the new digest inside the window, got %s", server.Status.Image.Digest)
+	}
+
+	// Outside the window the new digest only shows up as LatestDigest
+	clock.SetTime(time.Date(2024, time.March, 5, 5, 0, 0, 0, time.UTC))
+	server.Status.Image = &minecraftv2.ImageStatus{Reference: server.Spec.Image, Digest: "sha256:aaa"}
+	if err := r.reconcileImage(context.Background(), server); err != nil {
+		t.Fatalf("reconcileImage: %v", err)
+	}
+	if server.Status.Image.Digest != "sha256:aaa" || server.Status.Image.LatestDigest != "sha256:bbb" {
+		t.Errorf("expected the digest held outside the window, got %+v", server.Status.Image)
+	}
+}
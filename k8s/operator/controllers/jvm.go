@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// jvmEnv maps spec.jvm onto the itzg/minecraft-server JVM variables
+// USE_AIKAR_FLAGS makes the image add Aikar's G1 flags (adjusted for heaps over 12G), JVM_XX_OPTS is placed
+// before and JVM_OPTS after them. Options the webhook would reject are dropped, which covers servers created without it.
+func jvmEnv(server *minecraftv2.MinecraftServer) []corev1.EnvVar {
+	profile := minecraftv2.ResolveJVMProfile(server.Spec.JVM)
+
+	var xxOptions, extraArgs []string
+	if profile == minecraftv2.JVMProfileZGC {
+		xxOptions = append(xxOptions, "-XX:+UseZGC")
+	}
+	if jvm := server.Spec.JVM; jvm != nil {
+		for _, option := range jvm.XXOptions {
+			if minecraftv2.CheckXXOption(option) == nil {
+				xxOptions = append(xxOptions, "-XX:"+option)
+			}
+		}
+		for _, arg := range jvm.ExtraArgs {
+			if minecraftv2.CheckJVMArg(arg) == nil {
+				extraArgs = append(extraArgs, arg)
+			}
+		}
+	}
+
+	var env []corev1.EnvVar
+	if profile == minecraftv2.JVMProfileAikar {
+		env = append(env, corev1.EnvVar{Name: "USE_AIKAR_FLAGS", Value: "true"})
+	}
+	if len(xxOptions) > 0 {
+		env = append(env, corev1.EnvVar{Name: "JVM_XX_OPTS", Value: strings.Join(xxOptions, " ")})
+	}
+	if len(extraArgs) > 0 {
+		env = append(env, corev1.EnvVar{Name: "JVM_OPTS", Value: strings.Join(extraArgs, " ")})
+	}
+	return env
+}
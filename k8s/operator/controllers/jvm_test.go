@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+func TestJVMEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		jvm      *minecraftv2.JVMConfig
+		expected []corev1.EnvVar
+	}{
+		// Servers from before spec.jvm keep their pod template, however many players they allow
+		{"unset", nil, nil},
+		{"no profile", &minecraftv2.JVMConfig{XXOptions: []string{"+AlwaysPreTouch"}}, []corev1.EnvVar{
+			{Name: "JVM_XX_OPTS", Value: "-XX:+AlwaysPreTouch"},
+		}},
+		{"aikar", &minecraftv2.JVMConfig{Profile: minecraftv2.JVMProfileAikar}, []corev1.EnvVar{
+			{Name: "USE_AIKAR_FLAGS", Value: "true"},
+		}},
+		{"zgc with options", &minecraftv2.JVMConfig{
+			Profile:   minecraftv2.JVMProfileZGC,
+			XXOptions: []string{"+AlwaysPreTouch", "UseG1GC=true"},
+			ExtraArgs: []string{"-Dfoo=bar", "-Xmx8G"},
+		}, []corev1.EnvVar{
+			{Name: "JVM_XX_OPTS", Value: "-XX:+UseZGC -XX:+AlwaysPreTouch"},
+			{Name: "JVM_OPTS", Value: "-Dfoo=bar"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &minecraftv2.MinecraftServer{Spec: minecraftv2.MinecraftServerSpec{JVM: tt.jvm}}
+			server.Spec.Config.MaxPlayers = 50
+			if env := jvmEnv(server); !reflect.DeepEqual(env, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, env)
+			}
+		})
+	}
+}
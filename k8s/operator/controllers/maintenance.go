@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// podTemplateHashAnnotation is the hash of the pod template the operator last applied to the StatefulSet
+const podTemplateHashAnnotation = "minecraft.platform.com/pod-template-hash"
+
+// LoadMaintenanceWindows reads the tenants' default maintenance windows from a YAML map of tenant ID to schedule/duration/timezone
+func LoadMaintenanceWindows(path string) (map[string]minecraftv2.MaintenanceWindow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var windows map[string]minecraftv2.MaintenanceWindow
+	if err := yaml.UnmarshalStrict(data, &windows); err != nil {
+		return nil, fmt.Errorf("failed to parse maintenance windows: %w", err)
+	}
+	for tenant, window := range windows {
+		if err := window.Validate(); err != nil {
+			return nil, fmt.Errorf("maintenance window of tenant %s: %w", tenant, err)
+		}
+	}
+	return windows, nil
+}
+
+// maintenanceWindow is the server's window, falling back to its tenant's default; nil when changes apply right away
+func (r *MinecraftServerReconciler) maintenanceWindow(server *minecraftv2.MinecraftServer) *minecraftv2.MaintenanceWindow {
+	if server.Spec.MaintenanceWindow != nil {
+		return server.Spec.MaintenanceWindow
+	}
+	if window, ok := r.MaintenanceWindows[server.Spec.TenantID]; ok {
+		return &window
+	}
+	return nil
+}
+
+// holdDisruptiveChanges reports whether changes that restart the server have to wait for its maintenance window
+// Stopped servers have nothing to disrupt, and the apply-now annotation overrides the window
+func (r *MinecraftServerReconciler) holdDisruptiveChanges(ctx context.Context, server *minecraftv2.MinecraftServer) bool {
+	window := r.maintenanceWindow(server)
+	if window == nil || desiredReplicas(server) == 0 {
+		return false
+	}
+	if _, ok := server.Annotations[minecraftv2.ApplyNowAnnotation]; ok {
+		return false
+	}
+	open, err := window.Open(r.now())
+	if err != nil {
+		// A tenant window is validated at startup and a server window by the webhook; don't hold changes forever
+		log.FromContext(ctx).Info("Invalid maintenance window, applying changes", "error", err.Error())
+		return false
+	}
+	return !open
+}
+
+// holdPodChanges reports whether a new pod template has to wait for the maintenance window
+// Upgrades under way were started in the window, so their pod changes go ahead
+func (r *MinecraftServerReconciler) holdPodChanges(ctx context.Context, server *minecraftv2.MinecraftServer) bool {
+	if upgrade := server.Status.Upgrade; upgrade != nil && upgrade.InProgress() {
+		return false
+	}
+	return r.holdDisruptiveChanges(ctx, server)
+}
+
+// setPendingChange records a held back change, keeping when a change of the same type was first queued
+func (r *MinecraftServerReconciler) setPendingChange(server *minecraftv2.MinecraftServer, changeType minecraftv2.PendingChangeType, description string) {
+	for i := range server.Status.PendingChanges {
+		if server.Status.PendingChanges[i].Type == changeType {
+			server.Status.PendingChanges[i].Description = description
+			return
+		}
+	}
+	now := metav1.NewTime(r.now())
+	server.Status.PendingChanges = append(server.Status.PendingChanges,
+		minecraftv2.PendingChange{Type: changeType, Description: description, QueuedAt: &now})
+}
+
+// clearPendingChange removes the held back change of changeType
+func clearPendingChange(server *minecraftv2.MinecraftServer, changeType minecraftv2.PendingChangeType) {
+	var kept []minecraftv2.PendingChange
+	for _, change := range server.Status.PendingChanges {
+		if change.Type != changeType {
+			kept = append(kept, change)
+		}
+	}
+	server.Status.PendingChanges = kept
+}
+
+// recordNextWindow shows when the pending changes will be applied
+func (r *MinecraftServerReconciler) recordNextWindow(server *minecraftv2.MinecraftServer) {
+	window := r.maintenanceWindow(server)
+	if len(server.Status.PendingChanges) == 0 || window == nil {
+		server.Status.NextMaintenanceWindow = nil
+		return
+	}
+	next, err := window.NextOpen(r.now())
+	if err != nil {
+		server.Status.NextMaintenanceWindow = nil
+		return
+	}
+	if current := server.Status.NextMaintenanceWindow; current == nil || !current.Time.Equal(next) {
+		opens := metav1.NewTime(next)
+		server.Status.NextMaintenanceWindow = &opens
+	}
+}
+
+// windowRequeue shortens requeueAfter so held changes are applied as soon as the maintenance window opens
+func (r *MinecraftServerReconciler) windowRequeue(server *minecraftv2.MinecraftServer, requeueAfter time.Duration) time.Duration {
+	opens := server.Status.NextMaintenanceWindow
+	if opens == nil || len(server.Status.PendingChanges) == 0 {
+		return requeueAfter
+	}
+	// A second late, so the window is open by then
+	if untilOpen := opens.Sub(r.now()) + time.Second; untilOpen < requeueAfter {
+		return max(untilOpen, time.Second)
+	}
+	return requeueAfter
+}
+
+// podTemplateHash identifies a pod template as built by the operator, before the API server fills in defaults
+func podTemplateHash(template *corev1.PodTemplateSpec) string {
+	data, _ := json.Marshal(template)
+	hash := fnv.New64a()
+	_, _ = hash.Write(data)
+	return fmt.Sprintf("%x", hash.Sum64())
+}
+
+// describePodChanges names what differs between the running pod template and the desired one
+// Environment variables are listed by name only, since they include the RCON password
+func describePodChanges(current, desired *corev1.PodTemplateSpec) string {
+	var changes []string
+	have, want := serverContainer(&current.Spec), serverContainer(&desired.Spec)
+	if have == nil || want == nil {
+		return "pod template"
+	}
+
+	if have.Image != want.Image {
+		changes = append(changes, "image")
+	}
+
+	values := map[string]corev1.EnvVar{}
+	for _, env := range have.Env {
+		values[env.Name] = env
+	}
+	var names []string
+	for _, env := range want.Env {
+		if old, ok := values[env.Name]; !ok || !equality.Semantic.DeepEqual(old, env) {
+			names = append(names, env.Name)
+		}
+		delete(values, env.Name)
+	}
+	for name := range values {
+		names = append(names, name)
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		changes = append(changes, "env "+strings.Join(names, ", "))
+	}
+
+	if !equality.Semantic.DeepEqual(have.Resources, want.Resources) {
+		changes = append(changes, "resources")
+	}
+	if len(changes) == 0 {
+		return "pod template"
+	}
+	return strings.Join(changes, "; ")
+}
+
+// serverContainer returns the Minecraft server container of a pod spec
+func serverContainer(spec *corev1.PodSpec) *corev1.Container {
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == "minecraft-server" {
+			return &spec.Containers[i]
+		}
+	}
+	return nil
+}
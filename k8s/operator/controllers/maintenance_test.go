@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// podTemplate builds a template with a server container and an exporter sidecar
+func podTemplate(change func(container *corev1.Container)) *corev1.PodTemplateSpec {
+	server := corev1.Container{
+		Name:  "minecraft-server",
+		Image: "itzg/minecraft-server@sha256:aaa",
+		Env: []corev1.EnvVar{
+			{Name: "EULA", Value: "TRUE"},
+			{Name: "MEMORY", Value: "2G"},
+			{Name: "RCON_PASSWORD", Value: "old-secret"},
+		},
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("3Gi")},
+		},
+	}
+	if change != nil {
+		change(&server)
+	}
+	return &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		server,
+		{Name: "exporter", Image: "exporter:1"},
+	}}}
+}
+
+func TestDescribePodChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		desired  *corev1.PodTemplateSpec
+		expected string
+	}{
+		{"image", podTemplate(func(c *corev1.Container) { c.Image = "itzg/minecraft-server@sha256:bbb" }), "image"},
+		{"changed variable", podTemplate(func(c *corev1.Container) { c.Env[1].Value = "3G" }), "env MEMORY"},
+		{"secret is named, not shown", podTemplate(func(c *corev1.Container) { c.Env[2].Value = "new-secret" }), "env RCON_PASSWORD"},
+		{"added and removed variables", podTemplate(func(c *corev1.Container) {
+			c.Env = []corev1.EnvVar{c.Env[0], {Name: "TYPE", Value: "PAPER"}, c.Env[2]}
+		}), "env MEMORY, TYPE"},
+		{"variable from a secret", podTemplate(func(c *corev1.Container) {
+			c.Env[2] = corev1.EnvVar{Name: "RCON_PASSWORD", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "rcon"}, Key: "password"},
+			}}
+		}), "env RCON_PASSWORD"},
+		{"resources", podTemplate(func(c *corev1.Container) {
+			c.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("4Gi")
+		}), "resources"},
+		{"equal quantities", podTemplate(func(c *corev1.Container) {
+			c.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("3072Mi")
+		}), "pod template"},
+		{"several", podTemplate(func(c *corev1.Container) {
+			c.Image = "itzg/minecraft-server@sha256:bbb"
+			c.Env[1].Value = "3G"
+			c.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("4Gi")
+		}), "image; env MEMORY; resources"},
+		{"other containers only", func() *corev1.PodTemplateSpec {
+			template := podTemplate(nil)
+			template.Spec.Containers[1].Image = "exporter:2"
+			return template
+		}(), "pod template"},
+		{"no server container", &corev1.PodTemplateSpec{}, "pod template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if description := describePodChanges(podTemplate(nil), tt.desired); description != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, description)
+			}
+		})
+	}
+}
+
+func maintenanceServer(window *minecraftv2.MaintenanceWindow) *minecraftv2.MinecraftServer {
+	return &minecraftv2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "default"},
+		Spec: minecraftv2.MinecraftServerSpec{
+			TenantID:          "tenant-a",
+			PowerState:        minecraftv2.PowerOn,
+			MaintenanceWindow: window,
+		},
+	}
+}
+
+func TestHoldDisruptiveChanges(t *testing.T) {
+	berlin := &minecraftv2.MaintenanceWindow{Schedule: "0 3 * * *", Duration: "1h", Timezone: "Europe/Berlin"}
+	tests := []struct {
+		name string
+		now  string
+		hold bool
+	}{
+		{"summer, before the window", "2026-06-01T00:59:59Z", true},
+		{"summer, window opens", "2026-06-01T01:00:00Z", false},
+		{"summer, window closes", "2026-06-01T02:00:00Z", true},
+		{"winter, before the window", "2026-01-15T01:59:59Z", true},
+		{"winter, window opens", "2026-01-15T02:00:00Z", false},
+		{"winter, window closes", "2026-01-15T03:00:00Z", true},
+		{"clocks go forward", "2026-03-29T01:30:00Z", false},
+		{"clocks go back", "2026-10-25T02:30:00Z", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, _ := time.Parse(time.RFC3339, tt.now)
+			r := &MinecraftServerReconciler{Clock: clocktesting.NewFakePassiveClock(now)}
+			if hold := r.holdDisruptiveChanges(context.Background(), maintenanceServer(berlin)); hold != tt.hold {
+				t.Errorf("holdDisruptiveChanges at %s = %v, want %v", tt.now, hold, tt.hold)
+			}
+		})
+	}
+}
+
+func TestHeldChangesWaitForTenantWindow(t *testing.T) {
+	// Midnight in Berlin on the night the clocks go forward; the window opens at 03:00 CEST
+	now, _ := time.Parse(time.RFC3339, "2026-03-28T23:00:00Z")
+	clock := clocktesting.NewFakePassiveClock(now)
+	r := &MinecraftServerReconciler{
+		Clock: clock,
+		MaintenanceWindows: map[string]minecraftv2.MaintenanceWindow{
+			"tenant-a": {Schedule: "0 3 * * *", Duration: "1h", Timezone: "Europe/Berlin"},
+		},
+	}
+	server := maintenanceServer(nil)
+
+	if !r.holdDisruptiveChanges(context.Background(), server) {
+		t.Fatal("expected changes to be held outside the window")
+	}
+	r.setPendingChange(server, minecraftv2.PendingRestart, "env MEMORY")
+	r.recordNextWindow(server)
+
+	if queued := server.Status.PendingChanges[0].QueuedAt; queued == nil || !queued.Time.Equal(now) {
+		t.Errorf("expected the change to be queued at %s, got %v", now, queued)
+	}
+	opens, _ := time.Parse(time.RFC3339, "2026-03-29T01:00:00Z")
+	if next := server.Status.NextMaintenanceWindow; next == nil || !next.Time.Equal(opens) {
+		t.Fatalf("expected the next window at %s, got %v", opens, next)
+	}
+	if requeue := r.windowRequeue(server, 5*time.Minute); requeue != 5*time.Minute {
+		t.Errorf("expected the regular requeue while the window is hours away, got %s", requeue)
+	}
+
+	clock.SetTime(opens.Add(-time.Minute))
+	if requeue := r.windowRequeue(server, 5*time.Minute); requeue != time.Minute+time.Second {
+		t.Errorf("expected a requeue just after the window opens, got %s", requeue)
+	}
+
+	clock.SetTime(opens.Add(time.Second))
+	if r.holdDisruptiveChanges(context.Background(), server) {
+		t.Error("expected changes to be applied once the window opens")
+	}
+}
@@ -0,0 +1,535 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+const (
+	// proxyPort is the port the proxy listens on inside the pod (itzg/mc-proxy default)
+	proxyPort = 25577
+
+	// forwardingSecretKey is the Secret key and file name of the Velocity forwarding secret
+	forwardingSecretKey = "forwarding.secret"
+
+	// proxyConfigHashAnnotation rolls the proxy pods when the generated configuration changes
+	proxyConfigHashAnnotation = "minecraft.platform.com/config-hash"
+)
+
+// MinecraftNetworkReconciler reconciles a MinecraftNetwork object
+type MinecraftNetworkReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Network holds the exposure defaults for networks that don't set spec.network
+	Network NetworkDefaults
+}
+
+// +kubebuilder:rbac:groups=minecraft.platform.com,resources=minecraftnetworks,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=minecraft.platform.com,resources=minecraftnetworks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=minecraft.platform.com,resources=minecraftnetworks/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile deploys the proxy of a MinecraftNetwork and keeps its server list in sync with the members
+func (r *MinecraftNetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("minecraftnetwork", req.NamespacedName)
+
+	var network minecraftv2.MinecraftNetwork
+	if err := r.Get(ctx, req.NamespacedName, &network); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Child resources are garbage collected through their owner references
+	if network.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	members, skipped, err := r.listMembers(ctx, &network)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if network.Spec.UsesModernForwarding() {
+		if err := r.reconcileForwardingSecret(ctx, &network); err != nil {
+			logger.Error(err, "Failed to reconcile forwarding secret")
+			return ctrl.Result{}, err
+		}
+	}
+
+	configHash, err := r.reconcileProxyConfig(ctx, &network, members)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile proxy config")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileProxyDeployment(ctx, &network, configHash); err != nil {
+		logger.Error(err, "Failed to reconcile proxy Deployment")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileProxyService(ctx, &network); err != nil {
+		logger.Error(err, "Failed to reconcile proxy Service")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateNetworkStatus(ctx, &network, members, skipped); err != nil {
+		logger.Error(err, "Failed to update network status")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, err
+	}
+
+	return ctrl.Result{RequeueAfter: 120 * time.Second}, nil
+}
+
+// isNetworkMember reports whether the network's selector picks the server
+// Only servers in the network's namespace and tenant can be picked; the server type is checked separately
+func isNetworkMember(network *minecraftv2.MinecraftNetwork, server *minecraftv2.MinecraftServer) bool {
+	if server.Namespace != network.Namespace || server.Spec.TenantID != network.Spec.TenantID || !server.DeletionTimestamp.IsZero() {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&network.Spec.Selector)
+	if err != nil || selector.Empty() {
+		// An empty selector would pull every server of the namespace into the network
+		return false
+	}
+	return selector.Matches(labels.Set(server.Labels))
+}
+
+// memberAddress is the in-cluster game address of a member
+func memberAddress(server *minecraftv2.MinecraftServer) string {
+	return fmt.Sprintf("%s.%s.svc:25565", server.Name, server.Namespace)
+}
+
+// listMembers returns the network's members in name order, and the selected servers that can't join
+// Member names are the display name slugs, or the resource name when the slug is empty or taken
+func (r *MinecraftNetworkReconciler) listMembers(ctx context.Context, network *minecraftv2.MinecraftNetwork) ([]minecraftv2.NetworkMember, []string, error) {
+	var servers minecraftv2.MinecraftServerList
+	if err := r.List(ctx, &servers, client.InNamespace(network.Namespace)); err != nil {
+		return nil, nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	// Oldest servers keep their names when display names collide
+	sort.Slice(servers.Items, func(i, j int) bool {
+		return createdBefore(&servers.Items[i], &servers.Items[j])
+	})
+
+	var members []minecraftv2.NetworkMember
+	var skipped []string
+	taken := map[string]bool{}
+	for i := range servers.Items {
+		server := &servers.Items[i]
+		if !isNetworkMember(network, server) {
+			continue
+		}
+		// Forwarding is configured through Paper's and Spigot's config files
+		if !minecraftv2.SupportsPlugins(server.Spec.ServerType) {
+			skipped = append(skipped, server.Name)
+			continue
+		}
+
+		name := slugify(server.Spec.DisplayName)
+		if name == "" || taken[name] {
+			name = server.Name
+		}
+		taken[name] = true
+
+		members = append(members, minecraftv2.NetworkMember{
+			Name:       name,
+			ServerName: server.Name,
+			ServerID:   server.Spec.ServerID,
+			Address:    memberAddress(server),
+			Phase:      server.Status.Phase,
+		})
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+	return members, skipped, nil
+}
+
+// forwardingSecretName is the Secret holding the network's Velocity forwarding secret
+func forwardingSecretName(network *minecraftv2.MinecraftNetwork) string {
+	return fmt.Sprintf("%s-forwarding", network.Name)
+}
+
+// reconcileForwardingSecret creates the forwarding secret once; an existing value is never rotated
+func (r *MinecraftNetworkReconciler) reconcileForwardingSecret(ctx context.Context, network *minecraftv2.MinecraftNetwork) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      forwardingSecretName(network),
+			Namespace: network.Namespace,
+		},
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if err := controllerutil.SetControllerReference(network, secret, r.Scheme); err != nil {
+			return err
+		}
+		if len(secret.Data[forwardingSecretKey]) > 0 {
+			return nil
+		}
+
+		value := make([]byte, 32)
+		if _, err := rand.Read(value); err != nil {
+			return fmt.Errorf("failed to generate forwarding secret: %w", err)
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[forwardingSecretKey] = []byte(base64.RawURLEncoding.EncodeToString(value))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create/update forwarding Secret: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Forwarding Secret reconciled", "operation", op)
+	return nil
+}
+
+// proxyName is the name of the proxy Deployment and ConfigMap
+func proxyName(network *minecraftv2.MinecraftNetwork) string {
+	return fmt.Sprintf("%s-proxy", network.Name)
+}
+
+// proxyLabels select the proxy pods
+func proxyLabels(network *minecraftv2.MinecraftNetwork) map[string]string {
+	return map[string]string{
+		"app":     proxyName(network),
+		"tenant":  network.Spec.TenantID,
+		"network": network.Name,
+	}
+}
+
+// reconcileProxyConfig writes the proxy configuration and returns its hash
+func (r *MinecraftNetworkReconciler) reconcileProxyConfig(ctx context.Context, network *minecraftv2.MinecraftNetwork, members []minecraftv2.NetworkMember) (string, error) {
+	fileName, content, err := buildProxyConfig(network, members)
+	if err != nil {
+		return "", err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      proxyName(network),
+			Namespace: network.Namespace,
+		},
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if err := controllerutil.SetControllerReference(network, configMap, r.Scheme); err != nil {
+			return err
+		}
+		configMap.Data = map[string]string{fileName: content}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create/update proxy ConfigMap: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Proxy ConfigMap reconciled", "operation", op)
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// reconcileProxyDeployment runs the proxy; a new config hash rolls the pods so they pick up the server list
+func (r *MinecraftNetworkReconciler) reconcileProxyDeployment(ctx context.Context, network *minecraftv2.MinecraftNetwork, configHash string) error {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      proxyName(network),
+			Namespace: network.Namespace,
+		},
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
+		if err := controllerutil.SetControllerReference(network, deployment, r.Scheme); err != nil {
+			return err
+		}
+
+		replicas := int32(1)
+		if network.Spec.Replicas != nil {
+			replicas = *network.Spec.Replicas
+		}
+
+		deployment.Labels = proxyLabels(network)
+		deployment.Spec = appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": proxyName(network)},
+			},
+			// Keep the old proxy serving players until the new one is ready
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+					MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      proxyLabels(network),
+					Annotations: map[string]string{proxyConfigHashAnnotation: configHash},
+				},
+				Spec: buildProxyPodSpec(network),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create/update proxy Deployment: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Proxy Deployment reconciled", "operation", op)
+	return nil
+}
+
+// buildProxyPodSpec runs itzg/mc-proxy, which copies /config into the proxy directory on start
+func buildProxyPodSpec(network *minecraftv2.MinecraftNetwork) corev1.PodSpec {
+	proxyType := network.Spec.ProxyType
+	if proxyType == "" {
+		proxyType = minecraftv2.ProxyVelocity
+	}
+	resources := network.Spec.Resources
+	if len(resources.Requests) == 0 && len(resources.Limits) == 0 {
+		resources = defaultProxyResources()
+	}
+
+	sources := []corev1.VolumeProjection{
+		{ConfigMap: &corev1.ConfigMapProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: proxyName(network)},
+		}},
+	}
+	if network.Spec.UsesModernForwarding() {
+		sources = append(sources, corev1.VolumeProjection{Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: forwardingSecretName(network)},
+		}})
+	}
+
+	probe := func(initialDelay int32) *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(proxyPort)},
+			},
+			InitialDelaySeconds: initialDelay,
+			PeriodSeconds:       10,
+			TimeoutSeconds:      5,
+			FailureThreshold:    6,
+		}
+	}
+
+	return corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:  "proxy",
+				Image: network.Spec.Image,
+				Env: []corev1.EnvVar{
+					{Name: "TYPE", Value: string(proxyType)},
+					{Name: "MEMORY", Value: network.Spec.Memory},
+				},
+				Ports: []corev1.ContainerPort{
+					{Name: "minecraft", ContainerPort: proxyPort, Protocol: corev1.ProtocolTCP},
+				},
+				Resources: resources,
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "proxy-config", MountPath: "/config", ReadOnly: true},
+				},
+				ReadinessProbe: probe(10),
+				LivenessProbe:  probe(60),
+			},
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: "proxy-config",
+				VolumeSource: corev1.VolumeSource{
+					Projected: &corev1.ProjectedVolumeSource{Sources: sources},
+				},
+			},
+		},
+	}
+}
+
+// reconcileProxyService exposes the proxy on port 25565 like a game Service
+func (r *MinecraftNetworkReconciler) reconcileProxyService(ctx context.Context, network *minecraftv2.MinecraftNetwork) error {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      network.Name,
+			Namespace: network.Namespace,
+		},
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		if err := controllerutil.SetControllerReference(network, service, r.Scheme); err != nil {
+			return err
+		}
+
+		resolved := r.Network.resolve(network.Spec.Network)
+		if network.Spec.Network != nil {
+			resolved.hostname = network.Spec.Network.Hostname
+		}
+		applyServiceAnnotations(service, resolved, false)
+
+		// Keep an allocated node port so the Service isn't rewritten on every reconcile
+		nodePort := resolved.nodePort
+		if nodePort == 0 && service.Spec.Type == resolved.serviceType && resolved.serviceType != corev1.ServiceTypeClusterIP {
+			for _, port := range service.Spec.Ports {
+				if port.Name == "minecraft" {
+					nodePort = port.NodePort
+				}
+			}
+		}
+
+		service.Spec = corev1.ServiceSpec{
+			Selector: map[string]string{"app": proxyName(network)},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "minecraft",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       25565,
+					TargetPort: intstr.FromInt(proxyPort),
+					NodePort:   nodePort,
+				},
+			},
+			Type:              resolved.serviceType,
+			LoadBalancerClass: resolved.loadBalancerClass,
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create/update proxy Service: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Proxy Service reconciled", "operation", op)
+	return nil
+}
+
+// updateNetworkStatus records the members and the proxy readiness
+func (r *MinecraftNetworkReconciler) updateNetworkStatus(ctx context.Context, network *minecraftv2.MinecraftNetwork, members []minecraftv2.NetworkMember, skipped []string) error {
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: proxyName(network), Namespace: network.Namespace}, deployment); err != nil {
+		return fmt.Errorf("failed to get proxy Deployment: %w", err)
+	}
+
+	network.Status.ExternalIP = ""
+	service := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Name: network.Name, Namespace: network.Namespace}, service); err == nil {
+		if ingress := service.Status.LoadBalancer.Ingress; len(ingress) > 0 {
+			network.Status.ExternalIP = ingress[0].IP
+			if network.Status.ExternalIP == "" {
+				network.Status.ExternalIP = ingress[0].Hostname
+			}
+		}
+	}
+
+	network.Status.Members = members
+	network.Status.MemberCount = int32(len(members))
+	network.Status.ReadyReplicas = deployment.Status.ReadyReplicas
+	network.Status.ObservedGeneration = network.Generation
+	network.Status.Hostname = ""
+	if network.Spec.Network != nil {
+		network.Status.Hostname = network.Spec.Network.Hostname
+	}
+	network.Status.ForwardingSecretName = ""
+	if network.Spec.UsesModernForwarding() {
+		network.Status.ForwardingSecretName = forwardingSecretName(network)
+	}
+
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	setNetworkCondition(network, minecraftv2.ConditionProxyReady, boolStatus(deployment.Status.ReadyReplicas >= desired && desired > 0),
+		"ReplicasReady", fmt.Sprintf("%d/%d proxy replicas ready", deployment.Status.ReadyReplicas, desired))
+
+	var notRunning []string
+	for _, member := range members {
+		if member.Phase != minecraftv2.PhaseRunning {
+			notRunning = append(notRunning, member.Name)
+		}
+	}
+	switch {
+	case len(skipped) > 0:
+		setNetworkCondition(network, minecraftv2.ConditionMembersReady, metav1.ConditionFalse, "UnsupportedServerType",
+			fmt.Sprintf("Only Paper-family servers can join a proxy network; skipped %s", strings.Join(skipped, ", ")))
+	case len(members) == 0:
+		setNetworkCondition(network, minecraftv2.ConditionMembersReady, metav1.ConditionFalse, "NoMembers",
+			"No servers match the selector")
+	case len(notRunning) > 0:
+		setNetworkCondition(network, minecraftv2.ConditionMembersReady, metav1.ConditionFalse, "MembersNotRunning",
+			fmt.Sprintf("Not running: %s", strings.Join(notRunning, ", ")))
+	default:
+		setNetworkCondition(network, minecraftv2.ConditionMembersReady, metav1.ConditionTrue, "AllRunning",
+			fmt.Sprintf("%d members running", len(members)))
+	}
+
+	if err := r.Status().Update(ctx, network); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	return nil
+}
+
+// setNetworkCondition sets a condition on the network for its current generation
+func setNetworkCondition(network *minecraftv2.MinecraftNetwork, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&network.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: network.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// networksForServer maps a server event to every network in its namespace,
+// so networks also notice servers that stopped matching their selector
+func (r *MinecraftNetworkReconciler) networksForServer(ctx context.Context, obj client.Object) []reconcile.Request {
+	var networks minecraftv2.MinecraftNetworkList
+	if err := r.List(ctx, &networks, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list networks for server", "server", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(networks.Items))
+	for _, network := range networks.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: network.Name, Namespace: network.Namespace},
+		})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *MinecraftNetworkReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&minecraftv2.MinecraftNetwork{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Watches(&minecraftv2.MinecraftServer{}, handler.EnqueueRequestsFromMapFunc(r.networksForServer)).
+		Complete(r)
+}
@@ -3,33 +3,45 @@ package controllers
 import (
 	"bytes"
 	"context"
+	goerrors "errors"
 	"fmt"
 	"os"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
-	minecraftv1 "minecraft-platform-operator/api/v1"
+	minecraftv2 "minecraft-platform-operator/api/v2"
 	"minecraft-platform-operator/pkg/events"
+	"minecraft-platform-operator/pkg/metrics"
+	"minecraft-platform-operator/pkg/modpack"
 	"minecraft-platform-operator/pkg/rcon"
+	"minecraft-platform-operator/pkg/registry"
+	"minecraft-platform-operator/pkg/usage"
 )
 
 // getRconPassword returns the RCON password for a server
 // Uses per-server password if set, otherwise falls back to global env var
-func getRconPassword(server *minecraftv1.MinecraftServer) string {
+func getRconPassword(server *minecraftv2.MinecraftServer) string {
 	// Prefer per-server password if set
 	if server != nil && server.Spec.RCONPassword != "" {
 		return server.Spec.RCONPassword
@@ -49,6 +61,42 @@ type MinecraftServerReconciler struct {
 	EventPublisher *events.EventPublisher
 	Clientset      *kubernetes.Clientset
 	RestConfig     *rest.Config
+
+	// UsageSource reads pod resource usage; nil disables usage collection
+	UsageSource   usage.Source
+	UsageInterval time.Duration
+
+	// Network holds the exposure defaults for servers that don't set spec.network
+	Network NetworkDefaults
+
+	// ExporterImage is the image of the in-game metrics sidecar (the operator image, which ships /exporter)
+	ExporterImage string
+
+	// SizeTiers are the tiers spec.size selects from; the built-in tiers when nil
+	SizeTiers map[string]minecraftv2.SizeTier
+
+	// ModpackResolver resolves spec.modpack versions; servers with a modpack fail to reconcile when nil
+	ModpackResolver modpack.Resolver
+
+	// ImageResolver pins spec.image tags to digests; images run by tag when nil
+	ImageResolver registry.Resolver
+
+	// MaintenanceWindows are the tenants' default maintenance windows, by tenant ID
+	MaintenanceWindows map[string]minecraftv2.MaintenanceWindow
+
+	// Recorder records Kubernetes events on servers; events are skipped when nil
+	Recorder record.EventRecorder
+
+	// Clock measures idle time and decides whether windows are open; the real clock when nil
+	Clock clock.PassiveClock
+}
+
+// now is the reconciler's current time
+func (r *MinecraftServerReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
 }
 
 // +kubebuilder:rbac:groups=minecraft.platform.com,resources=minecraftservers,verbs=get;list;watch;create;update;patch;delete
@@ -59,13 +107,14 @@ type MinecraftServerReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile handles the reconciliation loop for MinecraftServer resources
 func (r *MinecraftServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx).WithValues("minecraftserver", req.NamespacedName)
 
 	// Fetch the MinecraftServer instance
-	var minecraftServer minecraftv1.MinecraftServer
+	var minecraftServer minecraftv2.MinecraftServer
 	if err := r.Get(ctx, req.NamespacedName, &minecraftServer); err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("MinecraftServer resource not found. Ignoring since object must be deleted")
@@ -82,6 +131,9 @@ func (r *MinecraftServerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return r.handleDeletion(ctx, &minecraftServer)
 	}
 
+	// Servers admitted without the defaulting webhook (--enable-webhooks=false) are stored as written
+	minecraftServer.Default()
+
 	// Add finalizer if not present
 	if !controllerutil.ContainsFinalizer(&minecraftServer, "minecraft.platform.com/finalizer") {
 		controllerutil.AddFinalizer(&minecraftServer, "minecraft.platform.com/finalizer")
@@ -90,65 +142,154 @@ func (r *MinecraftServerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		}
 	}
 
+	// Write the referenced template into the spec before anything reads it
+	if err := r.applyTemplate(ctx, &minecraftServer); err != nil {
+		logger.Error(err, "Failed to apply template")
+		var notFound *errTemplateNotFound
+		if goerrors.As(err, &notFound) {
+			return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, events.ReasonTemplateNotFound, err.Error())
+		}
+		return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, reconcileErrorReason(err), err.Error())
+	}
+
+	// Members of a MinecraftNetwork run behind its proxy
+	network, err := r.networkFor(ctx, &minecraftServer)
+	if err != nil {
+		logger.Error(err, "Failed to look up network")
+		return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, reconcileErrorReason(err), err.Error())
+	}
+
 	// Reconcile the ConfigMap
-	if err := r.reconcileConfigMap(ctx, &minecraftServer); err != nil {
+	stepStart := time.Now()
+	err = r.reconcileConfigMap(ctx, &minecraftServer, network)
+	metrics.ObserveStep(metrics.StepConfigMap, stepStart)
+	if err != nil {
 		logger.Error(err, "Failed to reconcile ConfigMap")
-		return r.updateStatus(ctx, &minecraftServer, "Error", err.Error())
+		return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, reconcileErrorReason(err), err.Error())
 	}
 
 	// Reconcile the Service
-	if err := r.reconcileService(ctx, &minecraftServer); err != nil {
+	stepStart = time.Now()
+	err = r.reconcileService(ctx, &minecraftServer, network)
+	metrics.ObserveStep(metrics.StepService, stepStart)
+	if err != nil {
 		logger.Error(err, "Failed to reconcile Service")
-		return r.updateStatus(ctx, &minecraftServer, "Error", err.Error())
+		return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, reconcileErrorReason(err), err.Error())
+	}
+
+	// Reconcile the metrics Service and ServiceMonitor
+	stepStart = time.Now()
+	err = r.reconcileMonitoring(ctx, &minecraftServer)
+	metrics.ObserveStep(metrics.StepMonitoring, stepStart)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile monitoring")
+		return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, reconcileErrorReason(err), err.Error())
+	}
+
+	// Apply wake-ups from the API server or mc-router before scaling
+	if err := r.reconcilePowerState(ctx, &minecraftServer); err != nil {
+		logger.Error(err, "Failed to reconcile power state")
+		return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, reconcileErrorReason(err), err.Error())
+	}
+
+	// Pick the modpack version to install; upgrades wait for their backup
+	if err := r.reconcileModpack(ctx, &minecraftServer); err != nil {
+		logger.Error(err, "Failed to reconcile modpack")
+		return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, events.ReasonModpackFailed, err.Error())
+	}
+
+	// Move version changes through snapshot, dry run, rollout and rollback
+	if err := r.reconcileUpgrade(ctx, &minecraftServer); err != nil {
+		logger.Error(err, "Failed to reconcile upgrade")
+		return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, reconcileErrorReason(err), err.Error())
+	}
+
+	// Pin the image to a digest, rolling out newer digests per spec.imageUpdate
+	if err := r.reconcileImage(ctx, &minecraftServer); err != nil {
+		logger.Error(err, "Failed to reconcile image")
+		return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, events.ReasonImageResolveFailed, err.Error())
 	}
 
 	// Reconcile the StatefulSet
-	if err := r.reconcileStatefulSet(ctx, &minecraftServer); err != nil {
+	stepStart = time.Now()
+	err = r.reconcileStatefulSet(ctx, &minecraftServer, network)
+	metrics.ObserveStep(metrics.StepStatefulSet, stepStart)
+	if err != nil {
 		logger.Error(err, "Failed to reconcile StatefulSet")
-		return r.updateStatus(ctx, &minecraftServer, "Error", err.Error())
+		return r.updateStatus(ctx, &minecraftServer, minecraftv2.PhaseError, reconcileErrorReason(err), err.Error())
+	}
+
+	// The override covers everything queued so far
+	if _, ok := minecraftServer.Annotations[minecraftv2.ApplyNowAnnotation]; ok {
+		if err := r.removeAnnotation(ctx, &minecraftServer, minecraftv2.ApplyNowAnnotation); err != nil {
+			logger.Error(err, "Failed to remove apply-now annotation")
+		}
+	}
+	r.recordNextWindow(&minecraftServer)
+
+	// Record finished backup Jobs before the status is written
+	if err := r.reconcileBackupJobs(ctx, &minecraftServer); err != nil {
+		logger.Error(err, "Failed to reconcile backup jobs")
+		// Continue anyway, not fatal
 	}
 
 	// Update status based on StatefulSet readiness
-	if err := r.updateServerStatus(ctx, &minecraftServer); err != nil {
+	stepStart = time.Now()
+	err = r.updateServerStatus(ctx, &minecraftServer)
+	metrics.ObserveStep(metrics.StepStatus, stepStart)
+	if err != nil {
 		logger.Error(err, "Failed to update server status")
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, err
 	}
 
-	// Check if server should be auto-stopped due to inactivity
-	autoStopped, err := r.checkAutoStop(ctx, &minecraftServer)
+	// Check if an Auto server should go to sleep due to inactivity
+	slept, err := r.checkSleep(ctx, &minecraftServer)
 	if err != nil {
-		logger.Error(err, "Failed to check auto-stop")
+		logger.Error(err, "Failed to check sleep")
 		// Continue anyway, not fatal
 	}
-	if autoStopped {
-		logger.Info("Server was auto-stopped, requeuing immediately")
+	if slept {
+		logger.Info("Server went to sleep, requeuing immediately")
 		return ctrl.Result{Requeue: true}, nil
 	}
 
 	logger.Info("Successfully reconciled MinecraftServer")
 
-	// Determine requeue interval based on auto-stop settings
-	// If auto-stop is enabled and server is running with no players,
-	// we need to check more frequently
+	// Determine requeue interval based on the power state
+	// If an Auto server is running with no players, we need to check more frequently
 	requeueAfter := 120 * time.Second
-	if minecraftServer.Spec.AutoStop != nil &&
-		minecraftServer.Spec.AutoStop.Enabled &&
-		minecraftServer.Status.Phase == "Running" &&
+	if minecraftServer.Spec.EffectivePowerState() == minecraftv2.PowerAuto &&
+		minecraftServer.Status.Phase == minecraftv2.PhaseRunning &&
 		minecraftServer.Status.PlayerCount == 0 {
 		// Check every 30 seconds when idle to catch auto-stop trigger
 		requeueAfter = 30 * time.Second
 	}
+	// Upgrades are watched for readiness and timeouts
+	if upgrade := minecraftServer.Status.Upgrade; upgrade != nil && upgrade.InProgress() {
+		requeueAfter = 15 * time.Second
+	}
+	// Held changes are applied as soon as the maintenance window opens
+	requeueAfter = r.windowRequeue(&minecraftServer, requeueAfter)
 
 	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // handleDeletion handles the deletion of MinecraftServer resources
-func (r *MinecraftServerReconciler) handleDeletion(ctx context.Context, server *minecraftv1.MinecraftServer) (ctrl.Result, error) {
+func (r *MinecraftServerReconciler) handleDeletion(ctx context.Context, server *minecraftv2.MinecraftServer) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
 	// Perform cleanup tasks here
 	logger.Info("Cleaning up MinecraftServer resources", "server", server.Name)
 
+	metrics.DeleteServer(server.Spec.TenantID, server.Spec.ServerID)
+
+	// Drop the server's status snapshot so consumers don't see a ghost server
+	if r.EventPublisher != nil {
+		if err := r.EventPublisher.DeleteServerStatus(server.Spec.TenantID, server.Spec.ServerID); err != nil {
+			logger.Error(err, "Failed to delete status snapshot")
+		}
+	}
+
 	// Remove finalizer to allow deletion
 	controllerutil.RemoveFinalizer(server, "minecraft.platform.com/finalizer")
 	if err := r.Update(ctx, server); err != nil {
@@ -159,7 +300,7 @@ func (r *MinecraftServerReconciler) handleDeletion(ctx context.Context, server *
 }
 
 // reconcileConfigMap ensures the ConfigMap exists and is up to date
-func (r *MinecraftServerReconciler) reconcileConfigMap(ctx context.Context, server *minecraftv1.MinecraftServer) error {
+func (r *MinecraftServerReconciler) reconcileConfigMap(ctx context.Context, server *minecraftv2.MinecraftServer, network *minecraftv2.MinecraftNetwork) error {
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-config", server.Name),
@@ -180,6 +321,22 @@ func (r *MinecraftServerReconciler) reconcileConfigMap(ctx context.Context, serv
 			"eula.txt":          "eula=true",
 		}
 
+		// Forwarding settings for the network's proxy, applied by the image on start
+		if network != nil {
+			patch, err := buildForwardingPatch(network)
+			if err != nil {
+				return err
+			}
+			configMap.Data[forwardingPatchKey] = patch
+			seeds, err := buildForwardingSeeds(network)
+			if err != nil {
+				return err
+			}
+			for key, seed := range seeds {
+				configMap.Data[key] = seed
+			}
+		}
+
 		return nil
 	})
 
@@ -191,9 +348,22 @@ func (r *MinecraftServerReconciler) reconcileConfigMap(ctx context.Context, serv
 	return nil
 }
 
-// reconcileService ensures the external Service exists (game port only, LoadBalancer)
-func (r *MinecraftServerReconciler) reconcileService(ctx context.Context, server *minecraftv1.MinecraftServer) error {
-	// External service - game port only (LoadBalancer)
+// reconcileService ensures the external Service exists (game port only, exposed per spec.network)
+func (r *MinecraftServerReconciler) reconcileService(ctx context.Context, server *minecraftv2.MinecraftServer, member *minecraftv2.MinecraftNetwork) error {
+	// Resolve the routing hostname first; a conflicting hostname is reported in status and left unrouted
+	// Network members run in offline mode, so only their proxy may reach them: no hostname, no external Service
+	var hostname string
+	if member != nil {
+		meta.RemoveStatusCondition(&server.Status.Conditions, minecraftv2.ConditionHostnameAssigned)
+		server.Status.Hostname = ""
+	} else {
+		var err error
+		if hostname, err = r.reconcileHostname(ctx, server); err != nil {
+			return err
+		}
+	}
+
+	// External service - game port only
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      server.Name,
@@ -207,20 +377,24 @@ func (r *MinecraftServerReconciler) reconcileService(ctx context.Context, server
 			return err
 		}
 
-		// Add mc-router annotations for auto-discovery and wake-on-connect
-		if service.Annotations == nil {
-			service.Annotations = make(map[string]string)
+		// mc-router annotations for hostname routing and wake-on-connect, plus spec.network annotations
+		network := r.resolveNetwork(server)
+		if member != nil {
+			network = resolvedNetwork{serviceType: corev1.ServiceTypeClusterIP}
 		}
-		// Use server name as the hostname for mc-router routing
-		// For local development with Windows Docker Desktop, the Minecraft client sends
-		// "kubernetes.docker.internal" when connecting through kubectl port-forward
-		// TODO: Make this configurable via MinecraftServer spec for production deployments
-		service.Annotations["mc-router.itzg.me/externalServerName"] = "kubernetes.docker.internal"
-		// Enable auto-scale-up when player connects to stopped server
-		if server.Spec.AutoStart != nil && server.Spec.AutoStart.Enabled {
-			service.Annotations["mc-router.itzg.me/autoScaleUp"] = "true"
-		} else {
-			delete(service.Annotations, "mc-router.itzg.me/autoScaleUp")
+		network.hostname = hostname
+		applyServiceAnnotations(service, network, member == nil && server.Spec.EffectivePowerState() == minecraftv2.PowerAuto)
+
+		// Keep allocated node ports so the Service isn't rewritten on every reconcile
+		allocated := map[string]int32{}
+		if service.Spec.Type == network.serviceType && network.serviceType != corev1.ServiceTypeClusterIP {
+			for _, port := range service.Spec.Ports {
+				allocated[port.Name] = port.NodePort
+			}
+		}
+		nodePort := network.nodePort
+		if nodePort == 0 {
+			nodePort = allocated["minecraft"]
 		}
 
 		// Configure external service - game port ONLY (SECURITY: RCON not exposed externally)
@@ -234,9 +408,16 @@ func (r *MinecraftServerReconciler) reconcileService(ctx context.Context, server
 					Protocol:   corev1.ProtocolTCP,
 					Port:       25565,
 					TargetPort: intstr.FromInt(25565),
+					NodePort:   nodePort,
 				},
 			},
-			Type: corev1.ServiceTypeLoadBalancer,
+			Type:              network.serviceType,
+			LoadBalancerClass: network.loadBalancerClass,
+		}
+
+		// Bedrock clients use UDP on the same Service
+		if bedrockEnabled(server) {
+			service.Spec.Ports = append(service.Spec.Ports, buildBedrockServicePort(server, allocated[bedrockPortName]))
 		}
 
 		return nil
@@ -290,7 +471,7 @@ func (r *MinecraftServerReconciler) reconcileService(ctx context.Context, server
 }
 
 // reconcileStatefulSet ensures the StatefulSet exists and is configured correctly
-func (r *MinecraftServerReconciler) reconcileStatefulSet(ctx context.Context, server *minecraftv1.MinecraftServer) error {
+func (r *MinecraftServerReconciler) reconcileStatefulSet(ctx context.Context, server *minecraftv2.MinecraftServer, network *minecraftv2.MinecraftNetwork) error {
 	statefulSet := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      server.Name,
@@ -298,6 +479,15 @@ func (r *MinecraftServerReconciler) reconcileStatefulSet(ctx context.Context, se
 		},
 	}
 
+	resources, err := r.resolveResources(server)
+	if err != nil {
+		return err
+	}
+
+	// Pod changes restart the server, so they wait for the maintenance window; upgrades under way were started in it
+	hold := r.holdPodChanges(ctx, server)
+	var pending string
+
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, statefulSet, func() error {
 		// Set owner reference
 		if err := controllerutil.SetControllerReference(server, statefulSet, r.Scheme); err != nil {
@@ -311,19 +501,48 @@ func (r *MinecraftServerReconciler) reconcileStatefulSet(ctx context.Context, se
 			"server-id": server.Spec.ServerID,
 		}
 
-		// Configure StatefulSet - set replicas based on Stopped field
-		// When autoStart is enabled, preserve replicas if mc-router scaled it up
-		replicas := int32(1)
-		if server.Spec.Stopped {
-			// Check if autoStart is enabled and the StatefulSet was externally scaled up (by mc-router)
-			if server.Spec.AutoStart != nil && server.Spec.AutoStart.Enabled &&
-				statefulSet.Spec.Replicas != nil && *statefulSet.Spec.Replicas > 0 {
-				// Preserve the current replica count - mc-router scaled it up for wake-on-connect
-				replicas = *statefulSet.Spec.Replicas
-			} else {
-				replicas = int32(0)
+		// Configure StatefulSet - set replicas based on the power state
+		// mc-router scale-ups were already turned into wake-ups by reconcilePowerState
+		replicas := desiredReplicas(server)
+		podMeta := metav1.ObjectMeta{
+			Labels: map[string]string{
+				"app":       server.Name,
+				"tenant":    server.Spec.TenantID,
+				"server-id": server.Spec.ServerID,
+			},
+		}
+		if monitoringEnabled(server) {
+			// Picked up by the annotation-based minecraft-servers scrape job when prometheus-operator isn't used
+			podMeta.Labels["minecraft.platform.com/server"] = "true"
+			podMeta.Annotations = map[string]string{
+				"prometheus.io/scrape": "true",
+				"prometheus.io/port":   fmt.Sprintf("%d", exporterPort(server)),
+				"prometheus.io/path":   "/metrics",
 			}
 		}
+
+		// Volume claim templates are immutable, so a new size or storage only applies to new servers
+		volumeClaimTemplates := statefulSet.Spec.VolumeClaimTemplates
+		if statefulSet.CreationTimestamp.IsZero() {
+			volumeClaimTemplates = r.buildVolumeClaimTemplates(server, resources)
+		}
+
+		// A held back template keeps the running one; StatefulSets from before the hash was recorded take the new one
+		template := corev1.PodTemplateSpec{
+			ObjectMeta: podMeta,
+			Spec:       r.buildPodSpec(server, network, resources),
+		}
+		hash := podTemplateHash(&template)
+		pending = ""
+		if applied, ok := statefulSet.Annotations[podTemplateHashAnnotation]; ok && applied != hash && hold {
+			pending = describePodChanges(&statefulSet.Spec.Template, &template)
+			template, hash = statefulSet.Spec.Template, applied
+		}
+		if statefulSet.Annotations == nil {
+			statefulSet.Annotations = map[string]string{}
+		}
+		statefulSet.Annotations[podTemplateHashAnnotation] = hash
+
 		statefulSet.Spec = appsv1.StatefulSetSpec{
 			Replicas:    &replicas,
 			ServiceName: server.Name,
@@ -332,17 +551,8 @@ func (r *MinecraftServerReconciler) reconcileStatefulSet(ctx context.Context, se
 					"app": server.Name,
 				},
 			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"app":       server.Name,
-						"tenant":    server.Spec.TenantID,
-						"server-id": server.Spec.ServerID,
-					},
-				},
-				Spec: r.buildPodSpec(server),
-			},
-			VolumeClaimTemplates: r.buildVolumeClaimTemplates(server),
+			Template:             template,
+			VolumeClaimTemplates: volumeClaimTemplates,
 		}
 
 		return nil
@@ -352,12 +562,24 @@ func (r *MinecraftServerReconciler) reconcileStatefulSet(ctx context.Context, se
 		return fmt.Errorf("failed to create/update StatefulSet: %w", err)
 	}
 
+	if pending != "" {
+		r.setPendingChange(server, minecraftv2.PendingRestart, pending)
+		log.FromContext(ctx).Info("Holding restart until the maintenance window", "changes", pending)
+	} else {
+		clearPendingChange(server, minecraftv2.PendingRestart)
+	}
+
 	log.FromContext(ctx).Info("StatefulSet reconciled", "operation", op)
 	return nil
 }
 
 // buildPodSpec creates the pod specification for the Minecraft server
-func (r *MinecraftServerReconciler) buildPodSpec(server *minecraftv1.MinecraftServer) corev1.PodSpec {
+// network is the MinecraftNetwork the server is a member of, if any; resources are the resolved spec.resources
+func (r *MinecraftServerReconciler) buildPodSpec(server *minecraftv2.MinecraftServer, network *minecraftv2.MinecraftNetwork, resources minecraftv2.MinecraftServerResources) corev1.PodSpec {
+	// Unset booleans fall back to their defaults
+	config := server.Spec.Config.DeepCopy()
+	config.ApplyDefaults()
+
 	// The itzg/minecraft-server image uses environment variables for configuration
 	// instead of mounting config files (which would be read-only)
 
@@ -366,41 +588,41 @@ func (r *MinecraftServerReconciler) buildPodSpec(server *minecraftv1.MinecraftSe
 		// Basic server settings
 		{Name: "EULA", Value: "TRUE"},
 		{Name: "TYPE", Value: server.Spec.ServerType},
-		{Name: "VERSION", Value: server.Spec.Version},
-		{Name: "MEMORY", Value: server.Spec.Resources.Memory},
+		{Name: "VERSION", Value: runningVersion(server)},
+		{Name: "MEMORY", Value: resources.Memory},
 
 		// Player settings
-		{Name: "MAX_PLAYERS", Value: fmt.Sprintf("%d", server.Spec.Config.MaxPlayers)},
-		{Name: "DIFFICULTY", Value: server.Spec.Config.Difficulty},
-		{Name: "MODE", Value: server.Spec.Config.Gamemode},
-		{Name: "FORCE_GAMEMODE", Value: fmt.Sprintf("%t", server.Spec.Config.ForceGamemode)},
-		{Name: "HARDCORE", Value: fmt.Sprintf("%t", server.Spec.Config.HardcoreMode)},
+		{Name: "MAX_PLAYERS", Value: fmt.Sprintf("%d", config.MaxPlayers)},
+		{Name: "DIFFICULTY", Value: config.Difficulty},
+		{Name: "MODE", Value: config.Gamemode},
+		{Name: "FORCE_GAMEMODE", Value: fmt.Sprintf("%t", *config.ForceGamemode)},
+		{Name: "HARDCORE", Value: fmt.Sprintf("%t", *config.HardcoreMode)},
 
 		// World settings
-		{Name: "LEVEL", Value: server.Spec.Config.LevelName},
-		{Name: "LEVEL_TYPE", Value: server.Spec.Config.LevelType},
-		{Name: "SPAWN_PROTECTION", Value: fmt.Sprintf("%d", server.Spec.Config.SpawnProtection)},
-		{Name: "VIEW_DISTANCE", Value: fmt.Sprintf("%d", server.Spec.Config.ViewDistance)},
-		{Name: "SIMULATION_DISTANCE", Value: fmt.Sprintf("%d", server.Spec.Config.SimulationDistance)},
-		{Name: "GENERATE_STRUCTURES", Value: fmt.Sprintf("%t", server.Spec.Config.GenerateStructures)},
-		{Name: "ALLOW_NETHER", Value: fmt.Sprintf("%t", server.Spec.Config.AllowNether)},
+		{Name: "LEVEL", Value: config.LevelName},
+		{Name: "LEVEL_TYPE", Value: config.LevelType},
+		{Name: "SPAWN_PROTECTION", Value: fmt.Sprintf("%d", config.SpawnProtection)},
+		{Name: "VIEW_DISTANCE", Value: fmt.Sprintf("%d", config.ViewDistance)},
+		{Name: "SIMULATION_DISTANCE", Value: fmt.Sprintf("%d", config.SimulationDistance)},
+		{Name: "GENERATE_STRUCTURES", Value: fmt.Sprintf("%t", *config.GenerateStructures)},
+		{Name: "ALLOW_NETHER", Value: fmt.Sprintf("%t", *config.AllowNether)},
 
 		// Server display
-		{Name: "MOTD", Value: server.Spec.Config.MOTD},
+		{Name: "MOTD", Value: config.MOTD},
 
 		// Gameplay settings
-		{Name: "PVP", Value: fmt.Sprintf("%t", server.Spec.Config.PVP)},
-		{Name: "ALLOW_FLIGHT", Value: fmt.Sprintf("%t", server.Spec.Config.AllowFlight)},
-		{Name: "ENABLE_COMMAND_BLOCK", Value: fmt.Sprintf("%t", server.Spec.Config.EnableCommandBlock)},
+		{Name: "PVP", Value: fmt.Sprintf("%t", *config.PVP)},
+		{Name: "ALLOW_FLIGHT", Value: fmt.Sprintf("%t", *config.AllowFlight)},
+		{Name: "ENABLE_COMMAND_BLOCK", Value: fmt.Sprintf("%t", *config.EnableCommandBlock)},
 
 		// Mob spawning
-		{Name: "SPAWN_ANIMALS", Value: fmt.Sprintf("%t", server.Spec.Config.SpawnAnimals)},
-		{Name: "SPAWN_MONSTERS", Value: fmt.Sprintf("%t", server.Spec.Config.SpawnMonsters)},
-		{Name: "SPAWN_NPCS", Value: fmt.Sprintf("%t", server.Spec.Config.SpawnNPCs)},
+		{Name: "SPAWN_ANIMALS", Value: fmt.Sprintf("%t", *config.SpawnAnimals)},
+		{Name: "SPAWN_MONSTERS", Value: fmt.Sprintf("%t", *config.SpawnMonsters)},
+		{Name: "SPAWN_NPCS", Value: fmt.Sprintf("%t", *config.SpawnNPCs)},
 
 		// Security settings
-		{Name: "ONLINE_MODE", Value: fmt.Sprintf("%t", server.Spec.Config.OnlineMode)},
-		{Name: "ENFORCE_WHITELIST", Value: fmt.Sprintf("%t", server.Spec.Config.WhiteList)},
+		{Name: "ONLINE_MODE", Value: fmt.Sprintf("%t", *config.OnlineMode)},
+		{Name: "ENFORCE_WHITELIST", Value: fmt.Sprintf("%t", *config.WhiteList)},
 
 		// RCON configuration for remote console access
 		{Name: "ENABLE_RCON", Value: "true"},
@@ -409,18 +631,27 @@ func (r *MinecraftServerReconciler) buildPodSpec(server *minecraftv1.MinecraftSe
 	}
 
 	// Add seed if specified (empty seed = random generation)
-	if server.Spec.Config.LevelSeed != "" {
+	if config.LevelSeed != "" {
 		envVars = append(envVars, corev1.EnvVar{
 			Name:  "SEED",
-			Value: server.Spec.Config.LevelSeed,
+			Value: config.LevelSeed,
 		})
 	}
 
-	return corev1.PodSpec{
+	// Garbage collector tuning and extra JVM options
+	envVars = append(envVars, jvmEnv(server)...)
+
+	// Modpack of the installed version, which lags spec.modpack while an upgrade waits for its backup
+	envVars = modpackEnv(server, envVars)
+
+	// Plugin jars, including Geyser/Floodgate for Bedrock crossplay
+	envVars = append(envVars, pluginEnv(server)...)
+
+	podSpec := corev1.PodSpec{
 		Containers: []corev1.Container{
 			{
 				Name:  "minecraft-server",
-				Image: server.Spec.Image,
+				Image: podImage(server),
 				Ports: []corev1.ContainerPort{
 					{
 						ContainerPort: 25565,
@@ -434,12 +665,12 @@ func (r *MinecraftServerReconciler) buildPodSpec(server *minecraftv1.MinecraftSe
 				Env: envVars,
 				Resources: corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
-						corev1.ResourceCPU:    server.Spec.Resources.CPURequest,
-						corev1.ResourceMemory: server.Spec.Resources.MemoryRequest,
+						corev1.ResourceCPU:    resources.CPURequest,
+						corev1.ResourceMemory: resources.MemoryRequest,
 					},
 					Limits: corev1.ResourceList{
-						corev1.ResourceCPU:    server.Spec.Resources.CPULimit,
-						corev1.ResourceMemory: server.Spec.Resources.MemoryLimit,
+						corev1.ResourceCPU:    resources.CPULimit,
+						corev1.ResourceMemory: resources.MemoryLimit,
 					},
 				},
 				VolumeMounts: []corev1.VolumeMount{
@@ -474,10 +705,24 @@ func (r *MinecraftServerReconciler) buildPodSpec(server *minecraftv1.MinecraftSe
 		},
 		RestartPolicy: corev1.RestartPolicyAlways,
 	}
+
+	if bedrockEnabled(server) {
+		podSpec.Containers[0].Ports = append(podSpec.Containers[0].Ports, buildBedrockContainerPort())
+	}
+
+	if network != nil {
+		applyForwarding(&podSpec, server, network)
+	}
+
+	if monitoringEnabled(server) {
+		podSpec.Containers = append(podSpec.Containers, r.buildExporterContainer(server))
+	}
+
+	return podSpec
 }
 
 // buildVolumeClaimTemplates creates the volume claim templates for persistent storage
-func (r *MinecraftServerReconciler) buildVolumeClaimTemplates(server *minecraftv1.MinecraftServer) []corev1.PersistentVolumeClaim {
+func (r *MinecraftServerReconciler) buildVolumeClaimTemplates(server *minecraftv2.MinecraftServer, resources minecraftv2.MinecraftServerResources) []corev1.PersistentVolumeClaim {
 	return []corev1.PersistentVolumeClaim{
 		{
 			ObjectMeta: metav1.ObjectMeta{
@@ -489,7 +734,7 @@ func (r *MinecraftServerReconciler) buildVolumeClaimTemplates(server *minecraftv
 				},
 				Resources: corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
-						corev1.ResourceStorage: server.Spec.Resources.Storage,
+						corev1.ResourceStorage: resources.Storage,
 					},
 				},
 				StorageClassName: &server.Spec.StorageClass,
@@ -499,7 +744,11 @@ func (r *MinecraftServerReconciler) buildVolumeClaimTemplates(server *minecraftv
 }
 
 // buildServerProperties generates the server.properties configuration
-func (r *MinecraftServerReconciler) buildServerProperties(server *minecraftv1.MinecraftServer) string {
+func (r *MinecraftServerReconciler) buildServerProperties(server *minecraftv2.MinecraftServer) string {
+	// Unset booleans fall back to their defaults
+	config := server.Spec.Config.DeepCopy()
+	config.ApplyDefaults()
+
 	properties := fmt.Sprintf(`# Minecraft server properties - Generated by operator
 server-port=25565
 max-players=%d
@@ -527,39 +776,39 @@ op-permission-level=4
 player-idle-timeout=0
 max-world-size=29999984
 `,
-		server.Spec.Config.MaxPlayers,
-		server.Spec.Config.Gamemode,
-		server.Spec.Config.Difficulty,
-		server.Spec.Config.LevelName,
-		server.Spec.Config.LevelType,
-		server.Spec.Config.MOTD,
-		server.Spec.Config.WhiteList,
-		server.Spec.Config.OnlineMode,
-		server.Spec.Config.PVP,
-		server.Spec.Config.EnableCommandBlock,
-		server.Spec.Config.SpawnProtection,
-		server.Spec.Config.ViewDistance,
-		server.Spec.Config.SimulationDistance,
-		server.Spec.Config.AllowFlight,
-		server.Spec.Config.AllowNether,
-		server.Spec.Config.SpawnAnimals,
-		server.Spec.Config.SpawnMonsters,
-		server.Spec.Config.SpawnNPCs,
-		server.Spec.Config.GenerateStructures,
-		server.Spec.Config.HardcoreMode,
-		server.Spec.Config.ForceGamemode,
+		config.MaxPlayers,
+		config.Gamemode,
+		config.Difficulty,
+		config.LevelName,
+		config.LevelType,
+		config.MOTD,
+		*config.WhiteList,
+		*config.OnlineMode,
+		*config.PVP,
+		*config.EnableCommandBlock,
+		config.SpawnProtection,
+		config.ViewDistance,
+		config.SimulationDistance,
+		*config.AllowFlight,
+		*config.AllowNether,
+		*config.SpawnAnimals,
+		*config.SpawnMonsters,
+		*config.SpawnNPCs,
+		*config.GenerateStructures,
+		*config.HardcoreMode,
+		*config.ForceGamemode,
 	)
 
 	// Add seed if specified
-	if server.Spec.Config.LevelSeed != "" {
-		properties += fmt.Sprintf("level-seed=%s\n", server.Spec.Config.LevelSeed)
+	if config.LevelSeed != "" {
+		properties += fmt.Sprintf("level-seed=%s\n", config.LevelSeed)
 	}
 
 	return properties
 }
 
 // updateServerStatus updates the MinecraftServer status based on StatefulSet status
-func (r *MinecraftServerReconciler) updateServerStatus(ctx context.Context, server *minecraftv1.MinecraftServer) error {
+func (r *MinecraftServerReconciler) updateServerStatus(ctx context.Context, server *minecraftv2.MinecraftServer) error {
 	logger := log.FromContext(ctx)
 
 	// Get StatefulSet status
@@ -596,48 +845,53 @@ func (r *MinecraftServerReconciler) updateServerStatus(ctx context.Context, serv
 	}
 
 	// Determine server status
-	var phase string
+	var phase minecraftv2.ServerPhase
 	var message string
+	var errorReason string
 	previousPhase := server.Status.Phase
+	previousMessage := server.Status.Message
 
-	// Check if server is intentionally stopped
-	// BUT: if autoStart is enabled and the server is running (mc-router scaled it up), show Running status
-	autoStartRunning := server.Spec.Stopped &&
-		server.Spec.AutoStart != nil && server.Spec.AutoStart.Enabled &&
-		statefulSet.Spec.Replicas != nil && *statefulSet.Spec.Replicas > 0
-
-	if server.Spec.Stopped && !autoStartRunning {
+	// Check if server is intentionally stopped or sleeping
+	if desiredReplicas(server) == 0 {
 		if statefulSet.Status.Replicas > 0 {
-			phase = "Stopping"
+			phase = minecraftv2.PhaseStopping
 			message = "Server is stopping"
 		} else {
-			phase = "Stopped"
+			phase = minecraftv2.PhaseStopped
 			message = "Server is stopped"
 		}
+		if server.Status.Sleeping {
+			message = fmt.Sprintf("Server is sleeping after %s without players", idleTimeout(server))
+		}
 	} else if *statefulSet.Spec.Replicas > 0 && statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas {
-		phase = "Running"
+		phase = minecraftv2.PhaseRunning
 		message = "Server is running and ready"
+	} else if reason, detail := r.diagnoseServerError(ctx, server, statefulSet); reason != "" {
+		phase = minecraftv2.PhaseError
+		message = detail
+		errorReason = reason
 	} else if statefulSet.Status.Replicas > 0 || *statefulSet.Spec.Replicas > 0 {
-		phase = "Starting"
+		phase = minecraftv2.PhaseStarting
 		message = "Server is starting up"
 	} else {
-		phase = "Pending"
+		phase = minecraftv2.PhasePending
 		message = "Server is pending"
 	}
 
-	// Update status directly
-	server.Status.Phase = phase
-	server.Status.Message = message
-	server.Status.LastUpdated = metav1.Now()
-	server.Status.ExternalIP = externalIP
-	server.Status.Port = externalPort
-
 	// Query player count via RCON if server is running
-	if phase == "Running" {
-		playerInfo := r.queryPlayerCount(ctx, server)
-		if playerInfo != nil {
+	// A rejected RCON password leaves the server unmanageable, so it is reported as an error
+	if phase == minecraftv2.PhaseRunning {
+		playerInfo, err := r.queryPlayerCount(ctx, server)
+		if rcon.IsAuthError(err) {
+			phase = minecraftv2.PhaseError
+			message = "Server is running but RCON authentication failed"
+			errorReason = events.ReasonRCONAuthFailed
+			server.Status.PlayerCount = 0
+			server.Status.Players = nil
+		} else if playerInfo != nil {
 			server.Status.PlayerCount = playerInfo.Online
 			server.Status.MaxPlayers = playerInfo.Max
+			server.Status.Players = playerInfo.Players
 
 			// Track player activity for auto-stop
 			if playerInfo.Online > 0 {
@@ -648,6 +902,12 @@ func (r *MinecraftServerReconciler) updateServerStatus(ctx context.Context, serv
 	} else {
 		// Reset player count when not running
 		server.Status.PlayerCount = 0
+		server.Status.Players = nil
+	}
+
+	// Remember the newest version that opened the world, so downgrades can be refused
+	if phase == minecraftv2.PhaseRunning {
+		r.recordHighestVersion(ctx, server)
 	}
 
 	// Set max players from config if not set from RCON
@@ -655,16 +915,34 @@ func (r *MinecraftServerReconciler) updateServerStatus(ctx context.Context, serv
 		server.Status.MaxPlayers = server.Spec.Config.MaxPlayers
 	}
 
+	// Update status directly
+	server.Status.Phase = phase
+	server.Status.Message = message
+	server.Status.LastUpdated = metav1.Now()
+	server.Status.ExternalIP = externalIP
+	server.Status.Port = externalPort
+	server.Status.BedrockEndpoint = bedrockEndpoint(server, service, externalIP)
+	server.Status.ObservedGeneration = server.Generation
+	setServerConditions(server, statefulSet, phase, errorReason, message)
+
 	if err := r.Status().Update(ctx, server); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
+	r.mirrorStatus(ctx, server)
+	metrics.PlayersOnline.WithLabelValues(server.Spec.TenantID, server.Spec.ServerID).Set(float64(server.Status.PlayerCount))
+	metrics.SetPhase(server.Spec.TenantID, server.Spec.ServerID, string(phase))
+
+	// Error events are also republished when the failure reason changes while already in Error
+	if phase == minecraftv2.PhaseError && previousPhase == phase && previousMessage != message {
+		r.publishServerError(ctx, server, errorReason, message)
+	}
 
 	// Publish state change event to NATS if phase changed
 	if r.EventPublisher != nil && previousPhase != phase {
 		logger.Info("Publishing state change event", "serverID", server.Spec.ServerID, "phase", phase)
 
 		switch phase {
-		case "Running":
+		case minecraftv2.PhaseRunning:
 			if err := r.EventPublisher.PublishServerRunning(
 				server.Spec.ServerID,
 				server.Spec.TenantID,
@@ -676,7 +954,7 @@ func (r *MinecraftServerReconciler) updateServerStatus(ctx context.Context, serv
 			); err != nil {
 				logger.Error(err, "Failed to publish server running event")
 			}
-		case "Starting":
+		case minecraftv2.PhaseStarting:
 			if err := r.EventPublisher.PublishServerStarting(
 				server.Spec.ServerID,
 				server.Spec.TenantID,
@@ -685,7 +963,7 @@ func (r *MinecraftServerReconciler) updateServerStatus(ctx context.Context, serv
 			); err != nil {
 				logger.Error(err, "Failed to publish server starting event")
 			}
-		case "Stopped":
+		case minecraftv2.PhaseStopped:
 			if err := r.EventPublisher.PublishServerStopped(
 				server.Spec.ServerID,
 				server.Spec.TenantID,
@@ -693,6 +971,8 @@ func (r *MinecraftServerReconciler) updateServerStatus(ctx context.Context, serv
 			); err != nil {
 				logger.Error(err, "Failed to publish server stopped event")
 			}
+		case minecraftv2.PhaseError:
+			r.publishServerError(ctx, server, errorReason, message)
 		}
 	}
 
@@ -700,12 +980,13 @@ func (r *MinecraftServerReconciler) updateServerStatus(ctx context.Context, serv
 }
 
 // queryPlayerCount queries the Minecraft server via pod exec to run rcon-cli
-func (r *MinecraftServerReconciler) queryPlayerCount(ctx context.Context, server *minecraftv1.MinecraftServer) *rcon.PlayerInfo {
+// Returns an error only when rcon-cli reports that authentication failed
+func (r *MinecraftServerReconciler) queryPlayerCount(ctx context.Context, server *minecraftv2.MinecraftServer) (*rcon.PlayerInfo, error) {
 	logger := log.FromContext(ctx)
 
 	if r.Clientset == nil || r.RestConfig == nil {
 		logger.V(1).Info("Clientset or RestConfig not available for exec")
-		return nil
+		return nil, nil
 	}
 
 	podName := fmt.Sprintf("%s-0", server.Name)
@@ -717,71 +998,113 @@ func (r *MinecraftServerReconciler) queryPlayerCount(ctx context.Context, server
 		Namespace: server.Namespace,
 	}, pod); err != nil {
 		logger.V(1).Info("Could not get pod for RCON query", "error", err)
-		return nil
+		return nil, nil
 	}
 
 	if pod.Status.Phase != corev1.PodRunning {
 		logger.V(1).Info("Pod not running yet", "phase", pod.Status.Phase)
-		return nil
+		return nil, nil
 	}
 
 	// Execute rcon-cli list command inside the pod
+	stdout, stderr, err := r.execRconCli(ctx, server, "list")
+	if err != nil {
+		logger.V(1).Info("Failed to execute rcon-cli", "error", err, "stderr", stderr)
+		if rcon.IsAuthFailureOutput(stderr) || rcon.IsAuthFailureOutput(stdout) {
+			return nil, fmt.Errorf("rcon-cli: %w", rcon.ErrAuthFailed)
+		}
+		return nil, nil
+	}
+
+	response := stdout
+	logger.V(1).Info("RCON list response", "response", response)
+
+	playerInfo, err := rcon.ParsePlayerList(response)
+	if err != nil {
+		logger.V(1).Info("Failed to parse player list", "error", err, "response", response)
+		return nil, nil
+	}
+
+	logger.V(1).Info("Got player count", "online", playerInfo.Online, "max", playerInfo.Max)
+	return playerInfo, nil
+}
+
+// execRconCli runs rcon-cli with args inside the server pod and returns its stdout and stderr
+func (r *MinecraftServerReconciler) execRconCli(ctx context.Context, server *minecraftv2.MinecraftServer, args ...string) (string, string, error) {
+	if r.Clientset == nil || r.RestConfig == nil {
+		return "", "", fmt.Errorf("clientset or rest config not available for exec")
+	}
+
 	req := r.Clientset.CoreV1().RESTClient().Post().
 		Resource("pods").
-		Name(podName).
+		Name(fmt.Sprintf("%s-0", server.Name)).
 		Namespace(server.Namespace).
 		SubResource("exec").
 		Param("container", "minecraft-server").
-		Param("command", "rcon-cli").
-		Param("command", "list").
-		Param("stdout", "true").
+		Param("command", "rcon-cli")
+	for _, arg := range args {
+		req = req.Param("command", arg)
+	}
+	req = req.Param("stdout", "true").
 		Param("stderr", "true")
 
 	exec, err := remotecommand.NewSPDYExecutor(r.RestConfig, "POST", req.URL())
 	if err != nil {
-		logger.V(1).Info("Failed to create executor", "error", err)
-		return nil
+		return "", "", fmt.Errorf("failed to create executor: %w", err)
 	}
 
 	var stdout, stderr bytes.Buffer
+	start := time.Now()
 	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
 		Stdout: &stdout,
 		Stderr: &stderr,
 	})
-	if err != nil {
-		logger.V(1).Info("Failed to execute rcon-cli", "error", err, "stderr", stderr.String())
-		return nil
-	}
-
-	response := stdout.String()
-	logger.V(1).Info("RCON list response", "response", response)
+	metrics.ObserveRCON(server.Spec.TenantID, server.Spec.ServerID, start, err)
+	return stdout.String(), stderr.String(), err
+}
 
-	playerInfo, err := rcon.ParsePlayerList(response)
-	if err != nil {
-		logger.V(1).Info("Failed to parse player list", "error", err, "response", response)
-		return nil
+// removeAnnotation removes a one-shot request annotation from the server
+// The update returns the stored status, so the status computed so far in this reconcile is kept.
+func (r *MinecraftServerReconciler) removeAnnotation(ctx context.Context, server *minecraftv2.MinecraftServer, key string) error {
+	delete(server.Annotations, key)
+	observed := server.Status.DeepCopy()
+	if err := r.Update(ctx, server); err != nil {
+		return fmt.Errorf("failed to remove annotation %s: %w", key, err)
 	}
-
-	logger.V(1).Info("Got player count", "online", playerInfo.Online, "max", playerInfo.Max)
-	return playerInfo
+	server.Status = *observed
+	return nil
 }
 
 // updateStatus updates the MinecraftServer status
-func (r *MinecraftServerReconciler) updateStatus(ctx context.Context, server *minecraftv1.MinecraftServer, status, message string) (ctrl.Result, error) {
+// An error event carrying reason is published when the server enters the Error phase or its message changes
+func (r *MinecraftServerReconciler) updateStatus(ctx context.Context, server *minecraftv2.MinecraftServer, status minecraftv2.ServerPhase, reason, message string) (ctrl.Result, error) {
+	previousPhase := server.Status.Phase
+	previousMessage := server.Status.Message
+
 	server.Status.Phase = status
 	server.Status.Message = message
 	server.Status.LastUpdated = metav1.Now()
+	server.Status.ObservedGeneration = server.Generation
+	if status == minecraftv2.PhaseError {
+		setReconcileFailedConditions(server, reason, message)
+	}
 
 	if err := r.Status().Update(ctx, server); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
 	}
+	r.mirrorStatus(ctx, server)
+	metrics.SetPhase(server.Spec.TenantID, server.Spec.ServerID, string(status))
+
+	if status == minecraftv2.PhaseError && (previousPhase != status || previousMessage != message) {
+		r.publishServerError(ctx, server, reason, message)
+	}
 
 	// Determine requeue interval based on status
 	var requeueAfter time.Duration
 	switch status {
-	case "Starting":
+	case minecraftv2.PhaseStarting:
 		requeueAfter = 10 * time.Second
-	case "Error":
+	case minecraftv2.PhaseError:
 		requeueAfter = 30 * time.Second
 	default:
 		requeueAfter = 60 * time.Second
@@ -790,137 +1113,29 @@ func (r *MinecraftServerReconciler) updateStatus(ctx context.Context, server *mi
 	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-// checkAutoStop checks if the server should be automatically stopped due to inactivity
-// Returns true if the server was auto-stopped
-func (r *MinecraftServerReconciler) checkAutoStop(ctx context.Context, server *minecraftv1.MinecraftServer) (bool, error) {
-	logger := log.FromContext(ctx)
-
-	// Check if auto-stop is enabled
-	if server.Spec.AutoStop == nil || !server.Spec.AutoStop.Enabled {
-		return false, nil
-	}
-
-	// Don't auto-stop if server is already stopped or stopping
-	if server.Status.Phase == "Stopped" || server.Status.Phase == "Stopping" {
-		return false, nil
-	}
-
-	// Don't auto-stop if spec.stopped=true (server is meant to be stopped)
-	// unless autoStart woke it up and a player has connected since
-	if server.Spec.Stopped {
-		// If autoStart is NOT enabled, don't auto-stop - operator will scale down
-		if server.Spec.AutoStart == nil || !server.Spec.AutoStart.Enabled {
-			return false, nil
-		}
-		// If server was previously auto-stopped and no player activity since, don't re-trigger
-		// This prevents the infinite loop where auto-stop keeps triggering
-		if server.Status.AutoStoppedAt != nil {
-			if server.Status.LastPlayerActivity == nil ||
-				server.Status.LastPlayerActivity.Time.Before(server.Status.AutoStoppedAt.Time) {
-				return false, nil
-			}
-		}
-	}
-
-	// Don't auto-stop if server isn't running yet
-	if server.Status.Phase != "Running" {
-		return false, nil
-	}
-
-	// Don't auto-stop if there are players online
-	if server.Status.PlayerCount > 0 {
-		return false, nil
-	}
-
-	// Get idle timeout (default to 3 minutes if not set)
-	idleTimeoutMinutes := server.Spec.AutoStop.IdleTimeoutMinutes
-	if idleTimeoutMinutes == 0 {
-		idleTimeoutMinutes = 3
-	}
-
-	// Check if we've been idle long enough
-	var idleSince time.Time
-	if server.Status.LastPlayerActivity != nil {
-		idleSince = server.Status.LastPlayerActivity.Time
-	} else {
-		// If no player activity recorded, use server creation time
-		idleSince = server.CreationTimestamp.Time
-	}
-
-	idleDuration := time.Since(idleSince)
-	idleTimeout := time.Duration(idleTimeoutMinutes) * time.Minute
-
-	if idleDuration < idleTimeout {
-		logger.V(1).Info("Server is idle but timeout not reached yet",
-			"idleDuration", idleDuration.String(),
-			"idleTimeout", idleTimeout.String(),
-		)
-		return false, nil
-	}
-
-	// Auto-stop the server
-	logger.Info("Auto-stopping server due to inactivity",
-		"serverID", server.Spec.ServerID,
-		"idleDuration", idleDuration.String(),
-		"idleTimeout", idleTimeout.String(),
-	)
-
-	// Set the stopped flag
-	server.Spec.Stopped = true
-
-	if err := r.Update(ctx, server); err != nil {
-		return false, fmt.Errorf("failed to update server spec for auto-stop: %w", err)
-	}
-
-	// Re-fetch the server to get the updated resourceVersion before status update
-	if err := r.Get(ctx, types.NamespacedName{Name: server.Name, Namespace: server.Namespace}, server); err != nil {
-		logger.Error(err, "Failed to re-fetch server after spec update")
-		// Continue anyway - the spec update was successful
-	} else {
-		// Record when we auto-stopped (status update is separate from spec update)
-		now := metav1.Now()
-		server.Status.AutoStoppedAt = &now
-		if err := r.Status().Update(ctx, server); err != nil {
-			logger.Error(err, "Failed to update server status for auto-stop timestamp")
-			// Continue even if status update fails - the main spec.stopped=true is already set
-		}
-	}
-
-	// Directly scale down the StatefulSet to 0 to prevent the preserve logic
-	// from incorrectly keeping it running (when autoStart is enabled)
-	statefulSet := &appsv1.StatefulSet{}
-	if err := r.Get(ctx, types.NamespacedName{Name: server.Name, Namespace: server.Namespace}, statefulSet); err == nil {
-		zero := int32(0)
-		if statefulSet.Spec.Replicas == nil || *statefulSet.Spec.Replicas != 0 {
-			statefulSet.Spec.Replicas = &zero
-			if err := r.Update(ctx, statefulSet); err != nil {
-				logger.Error(err, "Failed to scale down StatefulSet during auto-stop")
-			} else {
-				logger.Info("Scaled down StatefulSet during auto-stop", "replicas", 0)
-			}
-		}
+// SetupWithManager sets up the controller with the Manager
+func (r *MinecraftServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The built-in router looks servers up by their assigned hostname
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &minecraftv2.MinecraftServer{}, hostnameIndexField, indexHostname); err != nil {
+		return err
 	}
 
-	// Publish auto-stop event
-	if r.EventPublisher != nil {
-		if err := r.EventPublisher.PublishServerStopped(
-			server.Spec.ServerID,
-			server.Spec.TenantID,
-			server.Namespace,
-		); err != nil {
-			logger.Error(err, "Failed to publish auto-stop event")
-		}
+	// Template changes are applied to the servers built from them
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &minecraftv2.MinecraftServer{}, templateIndexField, indexTemplateRef); err != nil {
+		return err
 	}
 
-	return true, nil
-}
-
-// SetupWithManager sets up the controller with the Manager
-func (r *MinecraftServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&minecraftv1.MinecraftServer{}).
+		// Usage collection only writes status.resourceUsage, which needs no reconcile
+		For(&minecraftv2.MinecraftServer{}, builder.WithPredicates(ignoreUsageUpdates)).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&batchv1.Job{}).
+		// Only selector and proxy type changes affect members, not the network's status
+		Watches(&minecraftv2.MinecraftNetwork{}, handler.EnqueueRequestsFromMapFunc(r.serversForNetwork),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&minecraftv2.MinecraftServerTemplate{}, handler.EnqueueRequestsFromMapFunc(r.serversForTemplate),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
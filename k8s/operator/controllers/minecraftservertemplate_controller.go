@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// MinecraftServerTemplateReconciler reports which servers were built from each template revision
+// The templates themselves are applied by the MinecraftServer controller
+type MinecraftServerTemplateReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=minecraft.platform.com,resources=minecraftservertemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=minecraft.platform.com,resources=minecraftservertemplates/status,verbs=get;update;patch
+
+// Reconcile updates the template's status from the servers referencing it
+func (r *MinecraftServerTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var template minecraftv2.MinecraftServerTemplate
+	if err := r.Get(ctx, req.NamespacedName, &template); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	var servers minecraftv2.MinecraftServerList
+	if err := r.List(ctx, &servers, client.MatchingFields{templateIndexField: template.Name}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	status := minecraftv2.MinecraftServerTemplateStatus{Revision: template.Generation}
+	for _, server := range servers.Items {
+		status.Servers = append(status.Servers, minecraftv2.TemplateServer{
+			Namespace: server.Namespace,
+			Name:      server.Name,
+			ServerID:  server.Spec.ServerID,
+			Revision:  server.Status.TemplateRevision,
+		})
+		if server.Status.TemplateRevision != template.Generation {
+			status.OutdatedServers++
+		}
+	}
+	status.ServerCount = int32(len(status.Servers))
+	sort.Slice(status.Servers, func(i, j int) bool {
+		a, b := status.Servers[i], status.Servers[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	if equality.Semantic.DeepEqual(template.Status, status) {
+		return ctrl.Result{}, nil
+	}
+	template.Status = status
+	if err := r.Status().Update(ctx, &template); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Template status updated", "template", template.Name,
+		"servers", status.ServerCount, "outdated", status.OutdatedServers)
+	return ctrl.Result{}, nil
+}
+
+// templateForServer maps a server event to the template it references
+func templateForServer(_ context.Context, obj client.Object) []reconcile.Request {
+	server, ok := obj.(*minecraftv2.MinecraftServer)
+	if !ok || server.Spec.TemplateRef == nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: server.Spec.TemplateRef.Name}}}
+}
+
+// SetupWithManager sets up the controller with the Manager
+// The templateRef index is registered by the MinecraftServer controller
+func (r *MinecraftServerTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&minecraftv2.MinecraftServerTemplate{}).
+		Watches(&minecraftv2.MinecraftServer{}, handler.EnqueueRequestsFromMapFunc(templateForServer)).
+		Complete(r)
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/modpack"
+)
+
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+
+// reconcileModpack resolves spec.modpack and records the version the pod installs in Status.Modpack
+// A new version, or a pack added to an existing world, is only rolled out in the maintenance window and after a successful backup;
+// until then the server keeps the installed version. The caller writes the status.
+func (r *MinecraftServerReconciler) reconcileModpack(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	logger := log.FromContext(ctx)
+
+	spec := server.Spec.Modpack
+	if spec == nil {
+		server.Status.Modpack = nil
+		clearPendingChange(server, minecraftv2.PendingModpack)
+		return nil
+	}
+	if r.ModpackResolver == nil {
+		return fmt.Errorf("modpacks are not enabled on this operator")
+	}
+
+	installed := server.Status.Modpack
+	samePack := installed != nil && installed.Source == spec.Source && installed.Project == spec.Project && installed.URL == spec.URL
+
+	pack, err := r.resolveModpack(ctx, server)
+	if err != nil {
+		if samePack && installed.VersionID != "" {
+			// A registry outage shouldn't take down a server that already has its pack
+			logger.Info("Could not resolve modpack, keeping the installed version", "version", installed.Version, "error", err.Error())
+			return nil
+		}
+		return fmt.Errorf("failed to resolve modpack: %w", err)
+	}
+	if samePack && installed.VersionID == pack.VersionID {
+		installed.PendingVersion = ""
+		clearPendingChange(server, minecraftv2.PendingModpack)
+		return nil
+	}
+
+	hasWorld, err := r.hasWorld(ctx, server)
+	if err != nil {
+		return err
+	}
+
+	// The pod installs what Status.Modpack names, so a held restart keeps the installed version recorded
+	if hasWorld && r.holdPodChanges(ctx, server) {
+		if installed == nil {
+			installed = &minecraftv2.ModpackStatus{Source: spec.Source, Project: spec.Project, URL: spec.URL}
+			server.Status.Modpack = installed
+		}
+		installed.PendingVersion = pack.Version
+		r.setPendingChange(server, minecraftv2.PendingModpack, fmt.Sprintf("modpack %s", pack.Version))
+		logger.Info("Holding modpack upgrade until the maintenance window", "version", pack.Version)
+		return nil
+	}
+	clearPendingChange(server, minecraftv2.PendingModpack)
+
+	// The mods change the world's content, so anything other than a pack on a new world is backed up first
+	var snapshot string
+	if hasWorld {
+		snapshot = snapshotJobName(server, fmt.Sprintf("modpack/%s/%s/%s/%s", spec.Source, spec.Project, spec.URL, pack.VersionID))
+		done, err := r.ensureSnapshot(ctx, server, snapshot)
+		if err != nil {
+			return fmt.Errorf("pre-upgrade backup for modpack %s failed: %w", pack.Version, err)
+		}
+		if !done {
+			if installed == nil {
+				installed = &minecraftv2.ModpackStatus{Source: spec.Source, Project: spec.Project, URL: spec.URL}
+				server.Status.Modpack = installed
+			}
+			installed.PendingVersion = pack.Version
+			logger.Info("Modpack upgrade waits for backup", "version", pack.Version, "job", snapshot)
+			return nil
+		}
+	}
+
+	now := metav1.Now()
+	server.Status.Modpack = &minecraftv2.ModpackStatus{
+		Source:        spec.Source,
+		Project:       spec.Project,
+		URL:           spec.URL,
+		Version:       pack.Version,
+		VersionID:     pack.VersionID,
+		GameVersion:   pack.GameVersion,
+		Loader:        pack.Loader,
+		LoaderVersion: pack.LoaderVersion,
+		UpgradeBackup: snapshot,
+		InstalledAt:   &now,
+	}
+	logger.Info("Installing modpack", "version", pack.Version, "loader", pack.Loader, "loaderVersion", pack.LoaderVersion)
+	return nil
+}
+
+// resolveModpack resolves spec.modpack for the server's Minecraft version and loader
+func (r *MinecraftServerReconciler) resolveModpack(ctx context.Context, server *minecraftv2.MinecraftServer) (*modpack.Pack, error) {
+	spec := server.Spec.Modpack
+	req := modpack.Request{
+		Source:      string(spec.Source),
+		Project:     spec.Project,
+		URL:         spec.URL,
+		Version:     spec.Version,
+		GameVersion: server.Spec.Version,
+		Loader:      strings.ToLower(server.Spec.ServerType),
+	}
+
+	if spec.Source == minecraftv2.ModpackCurseForge {
+		if spec.APIKeySecretRef == nil {
+			return nil, fmt.Errorf("CurseForge modpacks need apiKeySecretRef")
+		}
+		var secret corev1.Secret
+		if err := r.Get(ctx, types.NamespacedName{Name: spec.APIKeySecretRef.Name, Namespace: server.Namespace}, &secret); err != nil {
+			return nil, fmt.Errorf("failed to get CurseForge API key: %w", err)
+		}
+		req.APIKey = string(secret.Data[spec.APIKeySecretRef.Key])
+	}
+
+	return r.ModpackResolver.Resolve(ctx, req)
+}
+
+// hasWorld reports whether the server's data volume exists
+func (r *MinecraftServerReconciler) hasWorld(ctx context.Context, server *minecraftv2.MinecraftServer) (bool, error) {
+	var claim corev1.PersistentVolumeClaim
+	err := r.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("minecraft-data-%s-0", server.Name), Namespace: server.Namespace}, &claim)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get data volume: %w", err)
+	}
+	return true, nil
+}
+
+// modpackEnv maps the installed modpack onto the itzg/minecraft-server variables
+// The image installs the loader the pack pins and skips mods marked client-only
+// Modrinth and CurseForge packs replace TYPE; URL packs are extracted over the server's own type.
+func modpackEnv(server *minecraftv2.MinecraftServer, env []corev1.EnvVar) []corev1.EnvVar {
+	installed := server.Status.Modpack
+	if server.Spec.Modpack == nil || installed == nil || installed.VersionID == "" {
+		return env
+	}
+
+	switch installed.Source {
+	case minecraftv2.ModpackModrinth:
+		env = setEnv(env, "TYPE", "MODRINTH")
+		if installed.Project != "" {
+			env = append(env,
+				corev1.EnvVar{Name: "MODRINTH_MODPACK", Value: installed.Project},
+				corev1.EnvVar{Name: "MODRINTH_VERSION", Value: installed.VersionID},
+			)
+		} else {
+			env = append(env, corev1.EnvVar{Name: "MODRINTH_MODPACK", Value: installed.URL})
+		}
+	case minecraftv2.ModpackCurseForge:
+		env = setEnv(env, "TYPE", "AUTO_CURSEFORGE")
+		env = append(env,
+			corev1.EnvVar{Name: "CF_SLUG", Value: installed.Project},
+			corev1.EnvVar{Name: "CF_FILE_ID", Value: installed.VersionID},
+		)
+		if ref := server.Spec.Modpack.APIKeySecretRef; ref != nil {
+			env = append(env, corev1.EnvVar{Name: "CF_API_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: ref.DeepCopy()}})
+		}
+	case minecraftv2.ModpackURL:
+		env = append(env, corev1.EnvVar{Name: "GENERIC_PACK", Value: installed.URL})
+	}
+	return env
+}
+
+// setEnv replaces the value of an environment variable, appending it when missing
+func setEnv(env []corev1.EnvVar, name, value string) []corev1.EnvVar {
+	for i := range env {
+		if env[i].Name == name {
+			env[i].Value = value
+			return env
+		}
+	}
+	return append(env, corev1.EnvVar{Name: name, Value: value})
+}
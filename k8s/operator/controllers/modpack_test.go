@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/modpack"
+)
+
+// staticResolver resolves every request to the same pack
+type staticResolver struct{ pack modpack.Pack }
+
+func (s staticResolver) Resolve(context.Context, modpack.Request) (*modpack.Pack, error) {
+	pack := s.pack
+	return &pack, nil
+}
+
+func TestModpackUpgradeWaitsForWindow(t *testing.T) {
+	pt := newPowerTest(t, minecraftv2.PowerOn)
+	pt.r.ModpackResolver = staticResolver{modpack.Pack{Version: "2.0.0", VersionID: "v2"}}
+
+	// The server already has a world and runs version 1.0.0; its window only opens on New Year
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("minecraft-data-%s-0", pt.key.Name), Namespace: pt.key.Namespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources:   corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}},
+		},
+	}
+	if err := k8sClient.Create(pt.ctx, claim); err != nil {
+		t.Fatalf("failed to create data volume: %v", err)
+	}
+	server := pt.server
+	server.Spec.ServerType = "FABRIC"
+	server.Spec.Modpack = &minecraftv2.ModpackConfig{Source: minecraftv2.ModpackModrinth, Project: "pack"}
+	server.Spec.MaintenanceWindow = &minecraftv2.MaintenanceWindow{Schedule: "0 0 1 1 *", Duration: "5m"}
+	server.Status.Modpack = &minecraftv2.ModpackStatus{Source: minecraftv2.ModpackModrinth, Project: "pack", Version: "1.0.0", VersionID: "v1"}
+
+	if err := pt.r.reconcileModpack(pt.ctx, server); err != nil {
+		t.Fatalf("reconcileModpack: %v", err)
+	}
+	if installed := server.Status.Modpack; installed.VersionID != "v1" || installed.InstalledAt != nil || installed.PendingVersion != "2.0.0" {
+		t.Errorf("expected 1.0.0 installed and 2.0.0 pending, got %+v", installed)
+	}
+	if len(server.Status.PendingChanges) != 1 || server.Status.PendingChanges[0].Type != minecraftv2.PendingModpack {
+		t.Errorf("expected a pending modpack change, got %+v", server.Status.PendingChanges)
+	}
+
+	// Applying now starts the backup; the version is recorded once it succeeds
+	server.Annotations = map[string]string{minecraftv2.ApplyNowAnnotation: ""}
+	if err := pt.r.reconcileModpack(pt.ctx, server); err != nil {
+		t.Fatalf("reconcileModpack: %v", err)
+	}
+	if installed := server.Status.Modpack; installed.VersionID != "v1" || installed.PendingVersion != "2.0.0" {
+		t.Errorf("expected 2.0.0 to wait for its backup, got %+v", installed)
+	}
+	if len(server.Status.PendingChanges) != 0 {
+		t.Errorf("expected no pending changes, got %+v", server.Status.PendingChanges)
+	}
+}
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+const (
+	// exporterContainerName is the in-game metrics sidecar injected when spec.monitoring.enabled is set
+	exporterContainerName = "metrics-exporter"
+
+	// DefaultExporterPort is the exporter port when spec.monitoring.port is unset
+	DefaultExporterPort = int32(9225)
+
+	// defaultScrapeInterval is the ServiceMonitor interval when spec.monitoring.interval is unset
+	defaultScrapeInterval = "30s"
+
+	// metricsComponentLabel marks the metrics Service so the ServiceMonitor selects only it
+	metricsComponentLabel = "minecraft.platform.com/component"
+)
+
+// serviceMonitorGVK is the prometheus-operator ServiceMonitor; used unstructured so the CRD stays optional
+var serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+
+// monitoringEnabled reports whether the exporter sidecar should run
+func monitoringEnabled(server *minecraftv2.MinecraftServer) bool {
+	return server.Spec.Monitoring != nil && server.Spec.Monitoring.Enabled
+}
+
+// exporterPort returns the port the exporter serves metrics on
+func exporterPort(server *minecraftv2.MinecraftServer) int32 {
+	if server.Spec.Monitoring.Port != 0 {
+		return server.Spec.Monitoring.Port
+	}
+	return DefaultExporterPort
+}
+
+// buildExporterContainer creates the sidecar that reads TPS, tick time, chunks and entities over RCON
+func (r *MinecraftServerReconciler) buildExporterContainer(server *minecraftv2.MinecraftServer) corev1.Container {
+	port := exporterPort(server)
+
+	return corev1.Container{
+		Name:    exporterContainerName,
+		Image:   r.ExporterImage,
+		Command: []string{"/exporter"},
+		Args: []string{
+			fmt.Sprintf("--listen-address=:%d", port),
+			"--rcon-address=localhost:25575",
+			fmt.Sprintf("--server-type=%s", server.Spec.ServerType),
+			fmt.Sprintf("--tenant-id=%s", server.Spec.TenantID),
+			fmt.Sprintf("--server-id=%s", server.Spec.ServerID),
+		},
+		Env: []corev1.EnvVar{
+			{Name: "RCON_PASSWORD", Value: getRconPassword(server)},
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "metrics",
+				ContainerPort: port,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("16Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+		LivenessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/healthz",
+					Port: intstr.FromString("metrics"),
+				},
+			},
+			PeriodSeconds: 30,
+		},
+	}
+}
+
+// reconcileMonitoring ensures the metrics Service and ServiceMonitor match spec.monitoring
+func (r *MinecraftServerReconciler) reconcileMonitoring(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	logger := log.FromContext(ctx)
+
+	metricsService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-metrics", server.Name),
+			Namespace: server.Namespace,
+		},
+	}
+
+	if !monitoringEnabled(server) {
+		return r.deleteMonitoring(ctx, server, metricsService)
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, metricsService, func() error {
+		if err := controllerutil.SetControllerReference(server, metricsService, r.Scheme); err != nil {
+			return err
+		}
+
+		metricsService.Labels = map[string]string{
+			"app":                 server.Name,
+			"tenant":              server.Spec.TenantID,
+			"server-id":           server.Spec.ServerID,
+			metricsComponentLabel: "metrics",
+		}
+
+		// Internal only, like the RCON service
+		metricsService.Spec.Type = corev1.ServiceTypeClusterIP
+		metricsService.Spec.Selector = map[string]string{
+			"app": server.Name,
+		}
+		metricsService.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:       "metrics",
+				Protocol:   corev1.ProtocolTCP,
+				Port:       exporterPort(server),
+				TargetPort: intstr.FromString("metrics"),
+			},
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create/update metrics Service: %w", err)
+	}
+	logger.Info("Metrics Service reconciled", "operation", op)
+
+	if !pointer.BoolDeref(server.Spec.Monitoring.ServiceMonitor, true) {
+		return r.deleteServiceMonitor(ctx, server)
+	}
+
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(serviceMonitorGVK)
+	serviceMonitor.SetName(server.Name)
+	serviceMonitor.SetNamespace(server.Namespace)
+
+	smOp, err := controllerutil.CreateOrUpdate(ctx, r.Client, serviceMonitor, func() error {
+		if err := controllerutil.SetControllerReference(server, serviceMonitor, r.Scheme); err != nil {
+			return err
+		}
+
+		labels := map[string]string{
+			"app":       server.Name,
+			"tenant":    server.Spec.TenantID,
+			"server-id": server.Spec.ServerID,
+		}
+		for key, value := range server.Spec.Monitoring.ServiceMonitorLabels {
+			labels[key] = value
+		}
+		serviceMonitor.SetLabels(labels)
+
+		interval := server.Spec.Monitoring.Interval
+		if interval == "" {
+			interval = defaultScrapeInterval
+		}
+		serviceMonitor.Object["spec"] = map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{
+					"app":                 server.Name,
+					metricsComponentLabel: "metrics",
+				},
+			},
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"port":     "metrics",
+					"path":     "/metrics",
+					"interval": interval,
+				},
+			},
+		}
+
+		return nil
+	})
+	switch {
+	case meta.IsNoMatchError(err):
+		// prometheus-operator isn't installed; the Service can still be scraped directly
+		logger.V(1).Info("ServiceMonitor CRD not installed, skipping ServiceMonitor")
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to create/update ServiceMonitor: %w", err)
+	}
+	logger.Info("ServiceMonitor reconciled", "operation", smOp)
+
+	return nil
+}
+
+// deleteMonitoring removes the metrics Service and ServiceMonitor after monitoring is disabled
+func (r *MinecraftServerReconciler) deleteMonitoring(ctx context.Context, server *minecraftv2.MinecraftServer, metricsService *corev1.Service) error {
+	// Both are created together, so a missing Service means there is nothing to clean up
+	if err := r.Get(ctx, types.NamespacedName{Name: metricsService.Name, Namespace: metricsService.Namespace}, metricsService); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get metrics Service: %w", err)
+	}
+
+	if err := r.deleteServiceMonitor(ctx, server); err != nil {
+		return err
+	}
+
+	if err := r.Delete(ctx, metricsService); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete metrics Service: %w", err)
+	}
+	log.FromContext(ctx).Info("Metrics Service deleted")
+
+	return nil
+}
+
+// deleteServiceMonitor removes the server's ServiceMonitor if it exists
+func (r *MinecraftServerReconciler) deleteServiceMonitor(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(serviceMonitorGVK)
+	serviceMonitor.SetName(server.Name)
+	serviceMonitor.SetNamespace(server.Namespace)
+
+	err := r.Delete(ctx, serviceMonitor)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete ServiceMonitor: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+const (
+	// routerHostnameAnnotation is read by mc-router to route a hostname to the Service
+	routerHostnameAnnotation = "mc-router.itzg.me/externalServerName"
+
+	// routerAutoScaleUpAnnotation makes mc-router scale the StatefulSet up when a player connects
+	routerAutoScaleUpAnnotation = "mc-router.itzg.me/autoScaleUp"
+
+	// managedAnnotationsAnnotation lists the spec.network.annotations keys last applied to the Service,
+	// so keys removed from the spec are removed from the Service too
+	managedAnnotationsAnnotation = "minecraft.platform.com/managed-annotations"
+)
+
+// NetworkDefaults are the cluster-wide exposure settings used where spec.network leaves a field unset
+type NetworkDefaults struct {
+	// ServiceType of game Services; LoadBalancer when empty
+	ServiceType corev1.ServiceType
+
+	// LoadBalancerClass of LoadBalancer game Services; the cluster default when empty
+	LoadBalancerClass string
+
+	// Hostname set as the mc-router external server name for every server; no router annotation when empty
+	// Shared by all servers (e.g. kubernetes.docker.internal in dev), so it is not checked for conflicts
+	Hostname string
+
+	// Domain under which servers get <displayName>.<tenant>.<domain> hostnames; takes precedence over Hostname
+	Domain string
+}
+
+// resolvedNetwork is spec.network merged with the operator defaults
+type resolvedNetwork struct {
+	serviceType       corev1.ServiceType
+	nodePort          int32
+	loadBalancerClass *string
+	annotations       map[string]string
+	hostname          string
+}
+
+// resolveNetwork merges spec.network over the operator defaults
+// The hostname is resolved separately by reconcileHostname, which needs the other servers
+func (r *MinecraftServerReconciler) resolveNetwork(server *minecraftv2.MinecraftServer) resolvedNetwork {
+	return r.Network.resolve(server.Spec.Network)
+}
+
+// resolve merges a spec.network (of a server or a proxy network) over the defaults
+func (d NetworkDefaults) resolve(spec *minecraftv2.NetworkConfig) resolvedNetwork {
+	network := resolvedNetwork{
+		serviceType: d.ServiceType,
+	}
+	if network.serviceType == "" {
+		network.serviceType = corev1.ServiceTypeLoadBalancer
+	}
+	if d.LoadBalancerClass != "" {
+		class := d.LoadBalancerClass
+		network.loadBalancerClass = &class
+	}
+
+	if spec != nil {
+		if spec.ServiceType != "" {
+			network.serviceType = spec.ServiceType
+		}
+		if spec.LoadBalancerClass != nil {
+			network.loadBalancerClass = spec.LoadBalancerClass
+		}
+		network.nodePort = spec.NodePort
+		network.annotations = spec.Annotations
+	}
+
+	// Node ports and load balancer classes only exist on the service types that use them
+	if network.serviceType == corev1.ServiceTypeClusterIP {
+		network.nodePort = 0
+	}
+	if network.serviceType != corev1.ServiceTypeLoadBalancer {
+		network.loadBalancerClass = nil
+	}
+
+	return network
+}
+
+// applyServiceAnnotations sets the user and router annotations on the game Service
+// Annotations added by other controllers (cloud providers, mc-router) are left alone
+func applyServiceAnnotations(service *corev1.Service, network resolvedNetwork, autoScaleUp bool) {
+	if service.Annotations == nil {
+		service.Annotations = make(map[string]string)
+	}
+
+	// Drop user annotations that are no longer in the spec
+	for _, key := range strings.Split(service.Annotations[managedAnnotationsAnnotation], ",") {
+		if _, ok := network.annotations[key]; key != "" && !ok {
+			delete(service.Annotations, key)
+		}
+	}
+
+	keys := make([]string, 0, len(network.annotations))
+	for key, value := range network.annotations {
+		service.Annotations[key] = value
+		keys = append(keys, key)
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		service.Annotations[managedAnnotationsAnnotation] = strings.Join(keys, ",")
+	} else {
+		delete(service.Annotations, managedAnnotationsAnnotation)
+	}
+
+	// mc-router routes this hostname to the Service
+	if network.hostname != "" {
+		service.Annotations[routerHostnameAnnotation] = network.hostname
+	} else {
+		delete(service.Annotations, routerHostnameAnnotation)
+	}
+
+	// Enable auto-scale-up when a player connects to a sleeping server
+	if autoScaleUp {
+		service.Annotations[routerAutoScaleUpAnnotation] = "true"
+	} else {
+		delete(service.Annotations, routerAutoScaleUpAnnotation)
+	}
+}
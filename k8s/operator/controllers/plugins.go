@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// pluginURLs lists the plugin jars the image downloads into /data/plugins on start
+// Enabled spec.plugins with a URL come first, followed by plugins added by features such as Bedrock crossplay
+func pluginURLs(server *minecraftv2.MinecraftServer) []string {
+	var urls []string
+	for _, plugin := range server.Spec.Plugins {
+		if plugin.URL == "" || (plugin.Enabled != nil && !*plugin.Enabled) {
+			continue
+		}
+		urls = append(urls, plugin.URL)
+	}
+	return append(urls, bedrockPluginURLs(server)...)
+}
+
+// pluginEnv passes the plugin URLs to the itzg/minecraft-server PLUGINS variable
+// Only Paper-family server types load plugins; other types get no variable
+func pluginEnv(server *minecraftv2.MinecraftServer) []corev1.EnvVar {
+	urls := pluginURLs(server)
+	if len(urls) == 0 || !minecraftv2.SupportsPlugins(server.Spec.ServerType) {
+		return nil
+	}
+	return []corev1.EnvVar{{Name: "PLUGINS", Value: strings.Join(urls, "\n")}}
+}
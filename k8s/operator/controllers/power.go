@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/metrics"
+)
+
+const (
+	// wakeRequestedAnnotation is set to an RFC 3339 time by the API server's start endpoint to wake a sleeping server
+	wakeRequestedAnnotation = "minecraft.platform.com/wake-requested-at"
+
+	// defaultIdleTimeout applies when spec.autoStop doesn't set idleTimeoutMinutes
+	defaultIdleTimeout = 3 * time.Minute
+)
+
+// Power state machine
+//
+//	On   -> runs; never sleeps
+//	Off  -> scaled to 0; players can't wake it
+//	Auto -> awake:    runs until idle for the autoStop timeout, then sleeping
+//	        sleeping: scaled to 0 until woken by a player (router or mc-router) or a start command
+//
+// Only the controller writes status.sleeping. Leaving Auto clears it, so a server switched back to Auto starts awake.
+
+// desiredReplicas is the StatefulSet replica count for the server's power state
+func desiredReplicas(server *minecraftv2.MinecraftServer) int32 {
+	// Upgrades restore backups while the server is down
+	if upgrade := server.Status.Upgrade; upgrade != nil &&
+		(upgrade.Phase == minecraftv2.UpgradeRestoring || upgrade.Phase == minecraftv2.UpgradeRollingBack) {
+		return 0
+	}
+	switch server.Spec.EffectivePowerState() {
+	case minecraftv2.PowerOff:
+		return 0
+	case minecraftv2.PowerAuto:
+		if server.Status.Sleeping {
+			return 0
+		}
+	}
+	return 1
+}
+
+// idleTimeout is how long an Auto server may run without players before it sleeps
+func idleTimeout(server *minecraftv2.MinecraftServer) time.Duration {
+	if server.Spec.AutoStop != nil && server.Spec.AutoStop.IdleTimeoutMinutes > 0 {
+		return time.Duration(server.Spec.AutoStop.IdleTimeoutMinutes) * time.Minute
+	}
+	return defaultIdleTimeout
+}
+
+// startPowerState is the power state a start command moves an Off server to
+// Servers that used auto-stop or auto-start before being switched off go back to Auto
+func startPowerState(server *minecraftv2.MinecraftServer) minecraftv2.PowerState {
+	if (server.Spec.AutoStop != nil && server.Spec.AutoStop.Enabled) || (server.Spec.AutoStart != nil && server.Spec.AutoStart.Enabled) {
+		return minecraftv2.PowerAuto
+	}
+	return minecraftv2.PowerOn
+}
+
+// markAwake records a wake-up in status
+func markAwake(server *minecraftv2.MinecraftServer, reason string) {
+	now := metav1.Now()
+	server.Status.Sleeping = false
+	server.Status.WokenAt = &now
+	server.Status.WakeReason = reason
+}
+
+// autoStoppedBeforePowerState reports whether the server was put to sleep by an operator that predates spec.powerState
+// Those operators slept a server by setting spec.stopped, which now reads as Off, so nothing could wake it again.
+// Servers that set powerState were stopped on purpose: the start and stop commands keep spec.stopped in step.
+func autoStoppedBeforePowerState(server *minecraftv2.MinecraftServer) bool {
+	return server.Spec.PowerState == "" && server.Spec.Stopped &&
+		server.Spec.AutoStart != nil && server.Spec.AutoStart.Enabled &&
+		server.Status.AutoStoppedAt != nil
+}
+
+// reconcilePowerState applies wake-ups that arrived outside the operator before the StatefulSet is scaled
+// The status is saved together with the rest of the status by updateServerStatus
+func (r *MinecraftServerReconciler) reconcilePowerState(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	// Move servers auto-stopped by an older operator to Auto, asleep, so a player can still wake them
+	if autoStoppedBeforePowerState(server) {
+		server.Spec.PowerState = minecraftv2.PowerAuto
+		server.Spec.Stopped = false
+		status := server.Status.DeepCopy()
+		if err := r.Update(ctx, server); err != nil {
+			return fmt.Errorf("failed to migrate auto-stopped server: %w", err)
+		}
+		server.Status = *status
+		server.Status.Sleeping = true
+		log.FromContext(ctx).Info("Migrated auto-stopped server to the Auto power state", "serverID", server.Spec.ServerID)
+	}
+
+	if server.Spec.EffectivePowerState() != minecraftv2.PowerAuto {
+		server.Status.Sleeping = false
+		return nil
+	}
+	if !server.Status.Sleeping {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	// A start request from the API server made after the server went to sleep
+	if value, ok := server.Annotations[wakeRequestedAnnotation]; ok {
+		requested, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			logger.Info("Ignoring invalid wake request annotation", "value", value)
+		} else if server.Status.AutoStoppedAt == nil || requested.After(server.Status.AutoStoppedAt.Time) {
+			logger.Info("Waking server on API request", "serverID", server.Spec.ServerID)
+			markAwake(server, minecraftv2.WakeReasonCommand)
+			return nil
+		}
+	}
+
+	// mc-router's autoScaleUp scales the StatefulSet of a sleeping server up when a player connects
+	statefulSet := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: server.Name, Namespace: server.Namespace}, statefulSet); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get StatefulSet: %w", err)
+	}
+	if statefulSet.Spec.Replicas != nil && *statefulSet.Spec.Replicas > 0 {
+		logger.Info("Waking server scaled up by mc-router", "serverID", server.Spec.ServerID)
+		markAwake(server, minecraftv2.WakeReasonPlayerConnect)
+	}
+
+	return nil
+}
+
+// wakeServer wakes a sleeping Auto server; returns false when it wasn't sleeping
+func (r *MinecraftServerReconciler) wakeServer(ctx context.Context, server *minecraftv2.MinecraftServer, reason string) (bool, error) {
+	if server.Spec.EffectivePowerState() != minecraftv2.PowerAuto || !server.Status.Sleeping {
+		return false, nil
+	}
+
+	patch := client.MergeFrom(server.DeepCopy())
+	markAwake(server, reason)
+	if err := r.Status().Patch(ctx, server, patch); err != nil {
+		return false, fmt.Errorf("failed to wake server: %w", err)
+	}
+	r.mirrorStatus(ctx, server)
+
+	log.FromContext(ctx).Info("Server woken", "serverID", server.Spec.ServerID, "reason", reason)
+	return true, nil
+}
+
+// checkSleep puts an idle Auto server to sleep
+// Returns true if the server went to sleep
+func (r *MinecraftServerReconciler) checkSleep(ctx context.Context, server *minecraftv2.MinecraftServer) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	if server.Spec.EffectivePowerState() != minecraftv2.PowerAuto || server.Status.Sleeping {
+		return false, nil
+	}
+	if server.Status.Phase != minecraftv2.PhaseRunning || server.Status.PlayerCount > 0 {
+		return false, nil
+	}
+	// An upgrade needs the server up to prove the new version starts
+	if upgrade := server.Status.Upgrade; upgrade != nil && upgrade.InProgress() {
+		return false, nil
+	}
+
+	// Idle since the latest of creation, the last player and the last wake-up,
+	// so a server woken after a long sleep isn't put straight back to sleep
+	idleSince := server.CreationTimestamp.Time
+	for _, t := range []*metav1.Time{server.Status.LastPlayerActivity, server.Status.WokenAt} {
+		if t != nil && t.Time.After(idleSince) {
+			idleSince = t.Time
+		}
+	}
+
+	idleDuration := r.now().Sub(idleSince)
+	timeout := idleTimeout(server)
+	if idleDuration < timeout {
+		logger.V(1).Info("Server is idle but timeout not reached yet",
+			"idleDuration", idleDuration.String(),
+			"idleTimeout", timeout.String(),
+		)
+		return false, nil
+	}
+
+	logger.Info("Putting server to sleep due to inactivity",
+		"serverID", server.Spec.ServerID,
+		"idleDuration", idleDuration.String(),
+		"idleTimeout", timeout.String(),
+	)
+
+	now := metav1.Now()
+	server.Status.Sleeping = true
+	server.Status.AutoStoppedAt = &now
+	if err := r.Status().Update(ctx, server); err != nil {
+		return false, fmt.Errorf("failed to update server status for sleep: %w", err)
+	}
+	r.mirrorStatus(ctx, server)
+
+	// Scale down right away: a sleeping server with replicas left would look like an mc-router wake-up
+	statefulSet := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: server.Name, Namespace: server.Namespace}, statefulSet); err != nil {
+		return false, fmt.Errorf("failed to get StatefulSet for sleep: %w", err)
+	}
+	if statefulSet.Spec.Replicas == nil || *statefulSet.Spec.Replicas != 0 {
+		zero := int32(0)
+		statefulSet.Spec.Replicas = &zero
+		if err := r.Update(ctx, statefulSet); err != nil {
+			return false, fmt.Errorf("failed to scale down StatefulSet for sleep: %w", err)
+		}
+	}
+
+	metrics.AutoStops.WithLabelValues(server.Spec.TenantID, server.Spec.ServerID).Inc()
+	return true, nil
+}
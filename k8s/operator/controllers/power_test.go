@@ -0,0 +1,567 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/events"
+)
+
+// powerTest drives one server through the reconciler, standing in for the StatefulSet controller and mc-router
+type powerTest struct {
+	t      *testing.T
+	ctx    context.Context
+	clock  *clocktesting.FakePassiveClock
+	r      *MinecraftServerReconciler
+	key    types.NamespacedName
+	server *minecraftv2.MinecraftServer
+}
+
+// newPowerTest creates a server in its own namespace with the given power state
+func newPowerTest(t *testing.T, state minecraftv2.PowerState) *powerTest {
+	t.Helper()
+	requireEnvtest(t)
+
+	clock := clocktesting.NewFakePassiveClock(time.Now())
+	pt := &powerTest{
+		t:     t,
+		ctx:   context.Background(),
+		clock: clock,
+		r:     &MinecraftServerReconciler{Client: k8sClient, Scheme: testScheme, Clock: clock},
+		key:   types.NamespacedName{Name: "survival", Namespace: testNamespace(t)},
+	}
+
+	server := &minecraftv2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{Name: pt.key.Name, Namespace: pt.key.Namespace},
+		Spec: minecraftv2.MinecraftServerSpec{
+			ServerID:     uuid.New().String(),
+			DisplayName:  "Survival",
+			TenantID:     "tenant-1",
+			PowerState:   state,
+			ServerType:   "PAPER",
+			Version:      "1.20.4",
+			RCONPassword: "rcon-secret",
+		},
+	}
+	if state == minecraftv2.PowerAuto {
+		server.Spec.AutoStop = &minecraftv2.AutoStopConfig{Enabled: true, IdleTimeoutMinutes: 5}
+	}
+	if err := k8sClient.Create(pt.ctx, server); err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	pt.server = server
+	return pt
+}
+
+// reconcile runs Reconcile until it stops asking for an immediate requeue and reloads the server
+func (pt *powerTest) reconcile() {
+	pt.t.Helper()
+	for i := 0; i < 3; i++ {
+		result, err := pt.r.Reconcile(pt.ctx, ctrl.Request{NamespacedName: pt.key})
+		if err != nil {
+			pt.t.Fatalf("Reconcile: %v", err)
+		}
+		if !result.Requeue {
+			break
+		}
+	}
+	pt.reload()
+}
+
+// reload reads the server back from the API server
+func (pt *powerTest) reload() {
+	pt.t.Helper()
+	var server minecraftv2.MinecraftServer
+	if err := k8sClient.Get(pt.ctx, pt.key, &server); err != nil {
+		pt.t.Fatalf("failed to get server: %v", err)
+	}
+	pt.server = &server
+}
+
+// statefulSet returns the server's StatefulSet
+func (pt *powerTest) statefulSet() *appsv1.StatefulSet {
+	pt.t.Helper()
+	var statefulSet appsv1.StatefulSet
+	if err := k8sClient.Get(pt.ctx, pt.key, &statefulSet); err != nil {
+		pt.t.Fatalf("failed to get StatefulSet: %v", err)
+	}
+	return &statefulSet
+}
+
+// settle plays the StatefulSet controller: the pods the StatefulSet asks for exist and are ready
+func (pt *powerTest) settle() {
+	pt.t.Helper()
+	statefulSet := pt.statefulSet()
+	replicas := *statefulSet.Spec.Replicas
+	statefulSet.Status.ObservedGeneration = statefulSet.Generation
+	statefulSet.Status.Replicas = replicas
+	statefulSet.Status.ReadyReplicas = replicas
+	statefulSet.Status.AvailableReplicas = replicas
+	statefulSet.Status.CurrentReplicas = replicas
+	statefulSet.Status.UpdatedReplicas = replicas
+	if err := k8sClient.Status().Update(pt.ctx, statefulSet); err != nil {
+		pt.t.Fatalf("failed to update StatefulSet status: %v", err)
+	}
+}
+
+// expect checks the StatefulSet replicas and the server phase
+func (pt *powerTest) expect(replicas int32, phase minecraftv2.ServerPhase) {
+	pt.t.Helper()
+	statefulSet := pt.statefulSet()
+	if statefulSet.Spec.Replicas == nil || *statefulSet.Spec.Replicas != replicas {
+		pt.t.Errorf("expected %d replicas, got %v", replicas, statefulSet.Spec.Replicas)
+	}
+	if pt.server.Status.Phase != phase {
+		pt.t.Errorf("expected phase %s, got %s (%s)", phase, pt.server.Status.Phase, pt.server.Status.Message)
+	}
+}
+
+// run brings the server up and waits for it to be Running
+func (pt *powerTest) run() {
+	pt.t.Helper()
+	pt.reconcile()
+	pt.expect(1, minecraftv2.PhaseStarting)
+	pt.settle()
+	pt.reconcile()
+	pt.expect(1, minecraftv2.PhaseRunning)
+}
+
+// sleep lets a running Auto server idle past its timeout until it is asleep and scaled down
+func (pt *powerTest) sleep() {
+	pt.t.Helper()
+	pt.clock.SetTime(time.Now().Add(6 * time.Minute))
+	pt.reconcile()
+	if !pt.server.Status.Sleeping || pt.server.Status.AutoStoppedAt == nil {
+		pt.t.Fatalf("expected server to sleep, status %+v", pt.server.Status)
+	}
+	pt.expect(0, minecraftv2.PhaseStopping)
+	pt.settle()
+	pt.reconcile()
+	pt.expect(0, minecraftv2.PhaseStopped)
+	pt.clock.SetTime(time.Now())
+}
+
+// expectAwake checks that a sleeping server was woken for reason and is starting again
+func (pt *powerTest) expectAwake(reason string) {
+	pt.t.Helper()
+	if pt.server.Status.Sleeping || pt.server.Status.WakeReason != reason || pt.server.Status.WokenAt == nil {
+		pt.t.Errorf("expected server woken by %s, status %+v", reason, pt.server.Status)
+	}
+	pt.expect(1, minecraftv2.PhaseStarting)
+	pt.settle()
+	pt.reconcile()
+	pt.expect(1, minecraftv2.PhaseRunning)
+}
+
+// powerServer is a running Auto server created an hour ago, with no players since
+func powerServer(state minecraftv2.PowerState) *minecraftv2.MinecraftServer {
+	return &minecraftv2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "survival",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+		Spec: minecraftv2.MinecraftServerSpec{
+			ServerID:   "server-1",
+			TenantID:   "tenant-1",
+			PowerState: state,
+			AutoStop:   &minecraftv2.AutoStopConfig{Enabled: true, IdleTimeoutMinutes: 5},
+		},
+		Status: minecraftv2.MinecraftServerStatus{Phase: minecraftv2.PhaseRunning},
+	}
+}
+
+// powerClient builds a fake client holding server and its StatefulSet with the given replicas
+func powerClient(server *minecraftv2.MinecraftServer, replicas int32) client.Client {
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: server.Name, Namespace: server.Namespace},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+	return fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(server, statefulSet).
+		WithStatusSubresource(server).
+		Build()
+}
+
+func TestDesiredReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(server *minecraftv2.MinecraftServer)
+		expected int32
+	}{
+		{"On", func(s *minecraftv2.MinecraftServer) { s.Spec.PowerState = minecraftv2.PowerOn }, 1},
+		{"Off", func(s *minecraftv2.MinecraftServer) { s.Spec.PowerState = minecraftv2.PowerOff }, 0},
+		{"Auto awake", func(*minecraftv2.MinecraftServer) {}, 1},
+		{"Auto sleeping", func(s *minecraftv2.MinecraftServer) { s.Status.Sleeping = true }, 0},
+		{"sleeping left over after leaving Auto", func(s *minecraftv2.MinecraftServer) {
+			s.Spec.PowerState = minecraftv2.PowerOn
+			s.Status.Sleeping = true
+		}, 1},
+		{"stopped without powerState", func(s *minecraftv2.MinecraftServer) {
+			s.Spec.PowerState = ""
+			s.Spec.Stopped = true
+		}, 0},
+		{"upgrade restoring", func(s *minecraftv2.MinecraftServer) {
+			s.Spec.PowerState = minecraftv2.PowerOn
+			s.Status.Upgrade = &minecraftv2.UpgradeStatus{Phase: minecraftv2.UpgradeRestoring}
+		}, 0},
+		{"upgrade rolling back", func(s *minecraftv2.MinecraftServer) {
+			s.Status.Upgrade = &minecraftv2.UpgradeStatus{Phase: minecraftv2.UpgradeRollingBack}
+		}, 0},
+		{"upgrade rolling out", func(s *minecraftv2.MinecraftServer) {
+			s.Status.Upgrade = &minecraftv2.UpgradeStatus{Phase: minecraftv2.UpgradeRollingOut}
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := powerServer(minecraftv2.PowerAuto)
+			tt.change(server)
+			if replicas := desiredReplicas(server); replicas != tt.expected {
+				t.Errorf("expected %d replicas, got %d", tt.expected, replicas)
+			}
+		})
+	}
+}
+
+func TestCheckSleep(t *testing.T) {
+	recent := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	tests := []struct {
+		name   string
+		change func(server *minecraftv2.MinecraftServer)
+		sleeps bool
+	}{
+		{"idle past the timeout", func(*minecraftv2.MinecraftServer) {}, true},
+		{"On", func(s *minecraftv2.MinecraftServer) { s.Spec.PowerState = minecraftv2.PowerOn }, false},
+		{"Off", func(s *minecraftv2.MinecraftServer) { s.Spec.PowerState = minecraftv2.PowerOff }, false},
+		{"already sleeping", func(s *minecraftv2.MinecraftServer) { s.Status.Sleeping = true }, false},
+		{"starting", func(s *minecraftv2.MinecraftServer) { s.Status.Phase = minecraftv2.PhaseStarting }, false},
+		{"players online", func(s *minecraftv2.MinecraftServer) { s.Status.PlayerCount = 1 }, false},
+		{"player left recently", func(s *minecraftv2.MinecraftServer) { s.Status.LastPlayerActivity = &recent }, false},
+		{"woken recently", func(s *minecraftv2.MinecraftServer) { s.Status.WokenAt = &recent }, false},
+		{"longer timeout", func(s *minecraftv2.MinecraftServer) { s.Spec.AutoStop.IdleTimeoutMinutes = 120 }, false},
+		{"upgrade in progress", func(s *minecraftv2.MinecraftServer) {
+			s.Status.Upgrade = &minecraftv2.UpgradeStatus{Phase: minecraftv2.UpgradeDryRun}
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := powerServer(minecraftv2.PowerAuto)
+			tt.change(server)
+			c := powerClient(server, 1)
+			r := &MinecraftServerReconciler{Client: c, Scheme: testScheme, Clock: clocktesting.NewFakePassiveClock(time.Now())}
+
+			slept, err := r.checkSleep(ctx, server)
+			if err != nil {
+				t.Fatalf("checkSleep: %v", err)
+			}
+			if slept != tt.sleeps {
+				t.Fatalf("expected sleep %v, got %v", tt.sleeps, slept)
+			}
+
+			var stored minecraftv2.MinecraftServer
+			if err := c.Get(ctx, client.ObjectKeyFromObject(server), &stored); err != nil {
+				t.Fatal(err)
+			}
+			var statefulSet appsv1.StatefulSet
+			if err := c.Get(ctx, client.ObjectKeyFromObject(server), &statefulSet); err != nil {
+				t.Fatal(err)
+			}
+			if !tt.sleeps {
+				if *statefulSet.Spec.Replicas != 1 || stored.Status.AutoStoppedAt != nil {
+					t.Errorf("server was put to sleep: replicas %d, status %+v", *statefulSet.Spec.Replicas, stored.Status)
+				}
+				return
+			}
+			if !stored.Status.Sleeping || stored.Status.AutoStoppedAt == nil {
+				t.Errorf("sleep not saved, status %+v", stored.Status)
+			}
+			if *statefulSet.Spec.Replicas != 0 || desiredReplicas(&stored) != 0 {
+				t.Errorf("expected the StatefulSet scaled down, got %d replicas", *statefulSet.Spec.Replicas)
+			}
+		})
+	}
+}
+
+func TestWakeServer(t *testing.T) {
+	tests := []struct {
+		name   string
+		state  minecraftv2.PowerState
+		asleep bool
+		wakes  bool
+	}{
+		{"sleeping Auto", minecraftv2.PowerAuto, true, true},
+		{"awake Auto", minecraftv2.PowerAuto, false, false},
+		{"On", minecraftv2.PowerOn, false, false},
+		{"Off", minecraftv2.PowerOff, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := powerServer(tt.state)
+			server.Status.Sleeping = tt.asleep
+			c := powerClient(server, 0)
+			r := &MinecraftServerReconciler{Client: c, Scheme: testScheme}
+
+			woken, err := r.wakeServer(ctx, server, minecraftv2.WakeReasonCommand)
+			if err != nil {
+				t.Fatalf("wakeServer: %v", err)
+			}
+			if woken != tt.wakes {
+				t.Fatalf("expected woken %v, got %v", tt.wakes, woken)
+			}
+
+			var stored minecraftv2.MinecraftServer
+			if err := c.Get(ctx, client.ObjectKeyFromObject(server), &stored); err != nil {
+				t.Fatal(err)
+			}
+			if !tt.wakes {
+				if stored.Status.WokenAt != nil || stored.Status.Sleeping != tt.asleep {
+					t.Errorf("status changed: %+v", stored.Status)
+				}
+				return
+			}
+			if stored.Status.Sleeping || stored.Status.WokenAt == nil || stored.Status.WakeReason != minecraftv2.WakeReasonCommand {
+				t.Errorf("wake-up not saved, status %+v", stored.Status)
+			}
+			if desiredReplicas(&stored) != 1 {
+				t.Errorf("expected a woken server to scale up")
+			}
+		})
+	}
+}
+
+func TestReconcilePowerStateWakeUps(t *testing.T) {
+	stoppedAt := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name     string
+		state    minecraftv2.PowerState
+		annotate string
+		replicas int32
+		reason   string
+	}{
+		{"stays asleep", minecraftv2.PowerAuto, "", 0, ""},
+		{"wake request after sleeping", minecraftv2.PowerAuto, stoppedAt.Add(time.Minute).UTC().Format(time.RFC3339), 0, minecraftv2.WakeReasonCommand},
+		{"wake request from before sleeping", minecraftv2.PowerAuto, stoppedAt.Add(-time.Minute).UTC().Format(time.RFC3339), 0, ""},
+		{"invalid wake request", minecraftv2.PowerAuto, "soon", 0, ""},
+		{"scaled up by mc-router", minecraftv2.PowerAuto, "", 1, minecraftv2.WakeReasonPlayerConnect},
+		{"switched to On", minecraftv2.PowerOn, "", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := powerServer(tt.state)
+			server.Status.Sleeping = true
+			server.Status.AutoStoppedAt = &metav1.Time{Time: stoppedAt}
+			if tt.annotate != "" {
+				server.Annotations = map[string]string{wakeRequestedAnnotation: tt.annotate}
+			}
+			r := &MinecraftServerReconciler{Client: powerClient(server, tt.replicas), Scheme: testScheme}
+
+			if err := r.reconcilePowerState(context.Background(), server); err != nil {
+				t.Fatalf("reconcilePowerState: %v", err)
+			}
+			asleep := tt.reason == "" && tt.state == minecraftv2.PowerAuto
+			if server.Status.Sleeping != asleep || server.Status.WakeReason != tt.reason {
+				t.Errorf("expected sleeping %v and wake reason %q, status %+v", asleep, tt.reason, server.Status)
+			}
+			if tt.reason != "" && server.Status.WokenAt == nil {
+				t.Error("woken server has no wokenAt")
+			}
+		})
+	}
+}
+
+// TestWakeAndPowerCommandsOverNATS runs a server through sleep, wake-up, stop and start end to end
+func TestWakeAndPowerCommandsOverNATS(t *testing.T) {
+	pt := newPowerTest(t, minecraftv2.PowerAuto)
+	pt.run()
+	pt.sleep()
+
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	srv := natsserver.RunServer(&opts)
+	defer srv.Shutdown()
+
+	config := events.DefaultConfig()
+	config.NATSUrl = srv.ClientURL()
+	publisher, err := events.NewEventPublisher(config)
+	if err != nil {
+		t.Fatalf("NewEventPublisher: %v", err)
+	}
+	defer publisher.Close()
+	pt.r.EventPublisher = publisher
+
+	subscriber, err := events.NewCommandSubscriber(publisher, pt.r)
+	if err != nil {
+		t.Fatalf("NewCommandSubscriber: %v", err)
+	}
+	ctx, cancel := context.WithCancel(pt.ctx)
+	defer cancel()
+	go func() { _ = subscriber.Start(ctx) }()
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer nc.Close()
+
+	command := func(action string) events.CommandReply {
+		t.Helper()
+		subject := "cmd." + pt.server.Spec.TenantID + "." + pt.server.Spec.ServerID + "." + action
+		var msg *nats.Msg
+		for deadline := time.Now().Add(5 * time.Second); ; {
+			if msg, err = nc.Request(subject, nil, 5*time.Second); err == nil || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatalf("%s command: %v", action, err)
+		}
+		var reply events.CommandReply
+		if err := json.Unmarshal(msg.Data, &reply); err != nil {
+			t.Fatalf("invalid reply: %v", err)
+		}
+		if !reply.Success {
+			t.Fatalf("%s command failed: %s", action, reply.Error)
+		}
+		return reply
+	}
+
+	// start wakes a sleeping Auto server
+	if reply := command(events.CommandStart); reply.Output != "Server woken" {
+		t.Errorf("unexpected start reply %q", reply.Output)
+	}
+	pt.reconcile()
+	pt.expectAwake(minecraftv2.WakeReasonCommand)
+
+	// stop switches the server Off, and start turns it back into Auto since it uses auto-stop
+	command(events.CommandStop)
+	pt.reconcile()
+	if pt.server.Spec.PowerState != minecraftv2.PowerOff {
+		t.Errorf("expected Off after stop, got %s", pt.server.Spec.PowerState)
+	}
+	pt.expect(0, minecraftv2.PhaseStopping)
+	pt.settle()
+	pt.reconcile()
+	pt.expect(0, minecraftv2.PhaseStopped)
+
+	command(events.CommandStart)
+	pt.reload()
+	if pt.server.Spec.PowerState != minecraftv2.PowerAuto {
+		t.Errorf("expected Auto after start, got %s", pt.server.Spec.PowerState)
+	}
+	pt.run()
+}
+
+// autoStoppedServer is a server the pre-powerState operator put to sleep by setting spec.stopped
+func autoStoppedServer() *minecraftv2.MinecraftServer {
+	stoppedAt := metav1.NewTime(time.Now().Add(-time.Hour))
+	return &minecraftv2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "default"},
+		Spec: minecraftv2.MinecraftServerSpec{
+			ServerID:  "server-1",
+			TenantID:  "tenant-1",
+			Stopped:   true,
+			AutoStart: &minecraftv2.AutoStartConfig{Enabled: true},
+			AutoStop:  &minecraftv2.AutoStopConfig{Enabled: true},
+		},
+		Status: minecraftv2.MinecraftServerStatus{AutoStoppedAt: &stoppedAt},
+	}
+}
+
+func TestAutoStoppedBeforePowerState(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(server *minecraftv2.MinecraftServer)
+		expected bool
+	}{
+		{"auto-stopped", func(*minecraftv2.MinecraftServer) {}, true},
+		{"stopped by hand", func(s *minecraftv2.MinecraftServer) { s.Status.AutoStoppedAt = nil }, false},
+		{"auto-start disabled", func(s *minecraftv2.MinecraftServer) { s.Spec.AutoStart.Enabled = false }, false},
+		{"no auto-start", func(s *minecraftv2.MinecraftServer) { s.Spec.AutoStart = nil }, false},
+		{"running", func(s *minecraftv2.MinecraftServer) { s.Spec.Stopped = false }, false},
+		{"switched off by a stop command", func(s *minecraftv2.MinecraftServer) { s.Spec.PowerState = minecraftv2.PowerOff }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := autoStoppedServer()
+			tt.change(server)
+			if got := autoStoppedBeforePowerState(server); got != tt.expected {
+				t.Errorf("autoStoppedBeforePowerState() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReconcilePowerStateMigratesAutoStopped(t *testing.T) {
+	ctx := context.Background()
+	server := autoStoppedServer()
+	zero := int32(0)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: server.Name, Namespace: server.Namespace},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &zero},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(server, statefulSet).
+		WithStatusSubresource(server).
+		Build()
+	r := &MinecraftServerReconciler{Client: c, Scheme: testScheme}
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(server), server); err != nil {
+		t.Fatalf("failed to get server: %v", err)
+	}
+	if err := r.reconcilePowerState(ctx, server); err != nil {
+		t.Fatalf("reconcilePowerState: %v", err)
+	}
+
+	if server.Spec.EffectivePowerState() != minecraftv2.PowerAuto || !server.Status.Sleeping {
+		t.Errorf("expected a sleeping Auto server, got powerState %q, sleeping %v", server.Spec.PowerState, server.Status.Sleeping)
+	}
+	if server.Status.AutoStoppedAt == nil {
+		t.Error("migration dropped status.autoStoppedAt")
+	}
+	if desiredReplicas(server) != 0 {
+		t.Errorf("migrated server should stay scaled down, got %d replicas", desiredReplicas(server))
+	}
+
+	var stored minecraftv2.MinecraftServer
+	if err := c.Get(ctx, client.ObjectKeyFromObject(server), &stored); err != nil {
+		t.Fatalf("failed to get server: %v", err)
+	}
+	if stored.Spec.PowerState != minecraftv2.PowerAuto || stored.Spec.Stopped {
+		t.Errorf("migration not saved: powerState %q, stopped %v", stored.Spec.PowerState, stored.Spec.Stopped)
+	}
+
+	// A player connecting through mc-router now wakes it
+	one := int32(1)
+	statefulSet.Spec.Replicas = &one
+	if err := c.Update(ctx, statefulSet); err != nil {
+		t.Fatalf("failed to scale StatefulSet: %v", err)
+	}
+	if err := r.reconcilePowerState(ctx, server); err != nil {
+		t.Fatalf("reconcilePowerState: %v", err)
+	}
+	if server.Status.Sleeping || server.Status.WakeReason != minecraftv2.WakeReasonPlayerConnect {
+		t.Errorf("expected server woken by a player, status %+v", server.Status)
+	}
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// defaultProxyResources applies when a network sets no proxy resources
+func defaultProxyResources() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("250m"),
+			corev1.ResourceMemory: resource.MustParse("768Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("768Mi"),
+		},
+	}
+}
+
+// tryOrder lists the member names players are sent to, lobby first
+func tryOrder(lobby string, members []minecraftv2.NetworkMember) []string {
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		if member.Name == lobby {
+			names = append([]string{member.Name}, names...)
+		} else {
+			names = append(names, member.Name)
+		}
+	}
+	return names
+}
+
+// buildProxyConfig renders the proxy configuration file and returns its name and content
+func buildProxyConfig(network *minecraftv2.MinecraftNetwork, members []minecraftv2.NetworkMember) (string, string, error) {
+	if network.Spec.UsesModernForwarding() {
+		return "velocity.toml", buildVelocityConfig(network, members), nil
+	}
+
+	content, err := buildBungeeConfig(network, members)
+	if err != nil {
+		return "", "", err
+	}
+	return "config.yml", content, nil
+}
+
+// buildVelocityConfig renders velocity.toml with modern forwarding and the members as [servers]
+func buildVelocityConfig(network *minecraftv2.MinecraftNetwork, members []minecraftv2.NetworkMember) string {
+	var b strings.Builder
+	line := func(key, value string) {
+		fmt.Fprintf(&b, "%s = %s\n", key, value)
+	}
+
+	line("config-version", tomlString("2.7"))
+	line("bind", tomlString(fmt.Sprintf("0.0.0.0:%d", proxyPort)))
+	line("motd", tomlString(network.Spec.MOTD))
+	line("show-max-players", strconv.Itoa(network.Spec.MaxPlayers))
+	line("online-mode", "true")
+	line("force-key-authentication", "true")
+	line("player-info-forwarding-mode", tomlString("modern"))
+	// itzg/mc-proxy copies the mounted secret next to velocity.toml
+	line("forwarding-secret-file", tomlString(forwardingSecretKey))
+	line("announce-forge", "false")
+	line("kick-existing-players", "false")
+	line("ping-passthrough", tomlString("DISABLED"))
+
+	b.WriteString("\n[servers]\n")
+	for _, member := range members {
+		line(tomlString(member.Name), tomlString(member.Address))
+	}
+	try := tryOrder(network.Spec.Lobby, members)
+	quoted := make([]string, 0, len(try))
+	for _, name := range try {
+		quoted = append(quoted, tomlString(name))
+	}
+	line("try", "["+strings.Join(quoted, ", ")+"]")
+
+	// An empty table stops Velocity from adding its example forced hosts
+	b.WriteString("\n[forced-hosts]\n")
+
+	return b.String()
+}
+
+// tomlString quotes a TOML basic string
+func tomlString(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch {
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// buildBungeeConfig renders the BungeeCord/Waterfall config.yml with IP forwarding and the members as servers
+func buildBungeeConfig(network *minecraftv2.MinecraftNetwork, members []minecraftv2.NetworkMember) (string, error) {
+	servers := make(map[string]interface{}, len(members))
+	for _, member := range members {
+		servers[member.Name] = map[string]interface{}{
+			"address":    member.Address,
+			"motd":       network.Spec.MOTD,
+			"restricted": false,
+		}
+	}
+
+	config := map[string]interface{}{
+		"online_mode": true,
+		"ip_forward":  true,
+		"servers":     servers,
+		"listeners": []interface{}{
+			map[string]interface{}{
+				"host":                 fmt.Sprintf("0.0.0.0:%d", proxyPort),
+				"motd":                 network.Spec.MOTD,
+				"max_players":          network.Spec.MaxPlayers,
+				"priorities":           tryOrder(network.Spec.Lobby, members),
+				"force_default_server": false,
+				"query_enabled":        false,
+				"tab_list":             "GLOBAL_PING",
+			},
+		},
+	}
+
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to render proxy config: %w", err)
+	}
+	return string(content), nil
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// DefaultUsageInterval is how often resource usage is collected when not configured
+const DefaultUsageInterval = time.Minute
+
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get
+// +kubebuilder:rbac:groups=core,resources=nodes/proxy,verbs=get
+
+// RunUsageCollector collects the resource usage of every server at UsageInterval until ctx is done
+// Runs as a manager Runnable, separate from reconciles, so collection keeps its own cadence
+func (r *MinecraftServerReconciler) RunUsageCollector(ctx context.Context) error {
+	if r.UsageSource == nil {
+		return nil
+	}
+
+	interval := r.UsageInterval
+	if interval <= 0 {
+		interval = DefaultUsageInterval
+	}
+
+	logger := log.FromContext(ctx).WithName("usage-collector")
+	logger.Info("Starting resource usage collector", "interval", interval.String())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.collectResourceUsage(log.IntoContext(ctx, logger))
+		}
+	}
+}
+
+// collectResourceUsage updates Status.ResourceUsage of every server
+func (r *MinecraftServerReconciler) collectResourceUsage(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	var servers minecraftv2.MinecraftServerList
+	if err := r.List(ctx, &servers); err != nil {
+		logger.Error(err, "Failed to list servers for usage collection")
+		return
+	}
+
+	for i := range servers.Items {
+		server := &servers.Items[i]
+		if !server.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.updateResourceUsage(ctx, server); err != nil {
+			logger.V(1).Info("Could not collect resource usage", "server", server.Name, "namespace", server.Namespace, "error", err)
+		}
+	}
+}
+
+// updateResourceUsage reads the usage of the server pod and patches it into the status
+// Usage is cleared while no pod is running, so a stopped server doesn't report stale numbers
+func (r *MinecraftServerReconciler) updateResourceUsage(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	var resourceUsage *minecraftv2.ResourceUsage
+
+	pod := &corev1.Pod{}
+	err := r.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-0", server.Name), Namespace: server.Namespace}, pod)
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("failed to get pod: %w", err)
+	case pod.Status.Phase == corev1.PodRunning:
+		podUsage, err := r.UsageSource.PodUsage(ctx, pod, fmt.Sprintf("minecraft-data-%s-0", server.Name))
+		if err != nil {
+			return err
+		}
+		resourceUsage = &minecraftv2.ResourceUsage{
+			CPU:     podUsage.CPU,
+			Memory:  podUsage.Memory,
+			Storage: podUsage.Storage,
+			NetworkIO: &minecraftv2.NetworkIOStats{
+				RxBytes: podUsage.RxBytes,
+				TxBytes: podUsage.TxBytes,
+			},
+		}
+	}
+
+	if equality.Semantic.DeepEqual(resourceUsage, server.Status.ResourceUsage) {
+		return nil
+	}
+
+	patch := client.MergeFrom(server.DeepCopy())
+	server.Status.ResourceUsage = resourceUsage
+	if err := r.Status().Patch(ctx, server, patch); err != nil {
+		return fmt.Errorf("failed to patch resource usage: %w", err)
+	}
+	r.mirrorStatus(ctx, server)
+
+	return nil
+}
+
+// ignoreUsageUpdates drops server updates that only changed status.resourceUsage
+// The collector writes usage every interval; a full reconcile for each write would hit registries and the API server for nothing
+var ignoreUsageUpdates = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !usageOnlyUpdate(e.ObjectOld, e.ObjectNew)
+	},
+}
+
+// usageOnlyUpdate reports whether newObj differs from oldObj in nothing but its resource usage
+func usageOnlyUpdate(oldObj, newObj client.Object) bool {
+	oldServer, ok := oldObj.(*minecraftv2.MinecraftServer)
+	if !ok {
+		return false
+	}
+	newServer, ok := newObj.(*minecraftv2.MinecraftServer)
+	if !ok {
+		return false
+	}
+	if equality.Semantic.DeepEqual(oldServer.Status.ResourceUsage, newServer.Status.ResourceUsage) {
+		return false
+	}
+
+	before, after := oldServer.DeepCopy(), newServer.DeepCopy()
+	for _, server := range []*minecraftv2.MinecraftServer{before, after} {
+		server.ResourceVersion = ""
+		server.ManagedFields = nil
+		server.Status.ResourceUsage = nil
+	}
+	return equality.Semantic.DeepEqual(before, after)
+}
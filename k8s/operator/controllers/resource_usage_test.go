@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/usage"
+)
+
+func TestUsageOnlyUpdate(t *testing.T) {
+	base := &minecraftv2.MinecraftServer{}
+	base.ResourceVersion = "1"
+	base.Status.Phase = minecraftv2.PhaseRunning
+	base.Status.ResourceUsage = &minecraftv2.ResourceUsage{CPU: resource.MustParse("100m")}
+
+	tests := []struct {
+		name   string
+		change func(server *minecraftv2.MinecraftServer)
+		want   bool
+	}{
+		{"usage only", func(s *minecraftv2.MinecraftServer) { s.Status.ResourceUsage.CPU = resource.MustParse("200m") }, true},
+		{"usage cleared", func(s *minecraftv2.MinecraftServer) { s.Status.ResourceUsage = nil }, true},
+		{"nothing", func(s *minecraftv2.MinecraftServer) {}, false},
+		{"usage and phase", func(s *minecraftv2.MinecraftServer) {
+			s.Status.ResourceUsage.CPU = resource.MustParse("200m")
+			s.Status.Phase = minecraftv2.PhaseStopping
+		}, false},
+		{"usage and annotation", func(s *minecraftv2.MinecraftServer) {
+			s.Status.ResourceUsage.CPU = resource.MustParse("200m")
+			s.Annotations = map[string]string{wakeRequestedAnnotation: "2024-01-01T00:00:00Z"}
+		}, false},
+		{"spec", func(s *minecraftv2.MinecraftServer) { s.Generation++ }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := base.DeepCopy()
+			updated.ResourceVersion = "2"
+			tt.change(updated)
+			if got := usageOnlyUpdate(base, updated); got != tt.want {
+				t.Errorf("usageOnlyUpdate = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeUsageSource returns a fixed usage and records the claim it was asked about
+type fakeUsageSource struct {
+	usage *usage.Usage
+	err   error
+	claim string
+}
+
+func (f *fakeUsageSource) PodUsage(_ context.Context, _ *corev1.Pod, claimName string) (*usage.Usage, error) {
+	f.claim = claimName
+	return f.usage, f.err
+}
+
+// usageTest is a server with an optional pod in the fake client; statusPatches counts status writes
+type usageTest struct {
+	r             *MinecraftServerReconciler
+	source        *fakeUsageSource
+	statusPatches int
+}
+
+func newUsageTest(t *testing.T, server *minecraftv2.MinecraftServer, objects ...client.Object) *usageTest {
+	t.Helper()
+	ut := &usageTest{source: &fakeUsageSource{usage: &usage.Usage{
+		CPU:     resource.MustParse("250m"),
+		Memory:  resource.MustParse("1Gi"),
+		Storage: resource.MustParse("3Gi"),
+		RxBytes: 1000,
+		TxBytes: 2000,
+	}}}
+	c := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(append(objects, server)...).
+		WithStatusSubresource(server).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				ut.statusPatches++
+				return c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+	ut.r = &MinecraftServerReconciler{Client: c, Scheme: testScheme, UsageSource: ut.source}
+	return ut
+}
+
+// update runs updateResourceUsage on the stored server and returns it as stored afterwards
+func (ut *usageTest) update(t *testing.T) *minecraftv2.MinecraftServer {
+	t.Helper()
+	key := types.NamespacedName{Name: "survival", Namespace: "default"}
+	var server minecraftv2.MinecraftServer
+	if err := ut.r.Get(context.Background(), key, &server); err != nil {
+		t.Fatal(err)
+	}
+	if err := ut.r.updateResourceUsage(context.Background(), &server); err != nil {
+		t.Fatalf("updateResourceUsage: %v", err)
+	}
+	if err := ut.r.Get(context.Background(), key, &server); err != nil {
+		t.Fatal(err)
+	}
+	return &server
+}
+
+func usageServer() *minecraftv2.MinecraftServer {
+	return &minecraftv2.MinecraftServer{ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "default"}}
+}
+
+func serverPod(phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival-0", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestUpdateResourceUsageForRunningPod(t *testing.T) {
+	ut := newUsageTest(t, usageServer(), serverPod(corev1.PodRunning))
+
+	server := ut.update(t)
+	got := server.Status.ResourceUsage
+	if got == nil {
+		t.Fatal("expected resource usage to be set")
+	}
+	if got.CPU.String() != "250m" || got.Memory.String() != "1Gi" || got.Storage.String() != "3Gi" {
+		t.Errorf("unexpected usage %+v", got)
+	}
+	if got.NetworkIO == nil || got.NetworkIO.RxBytes != 1000 || got.NetworkIO.TxBytes != 2000 {
+		t.Errorf("unexpected network usage %+v", got.NetworkIO)
+	}
+	if ut.source.claim != "minecraft-data-survival-0" {
+		t.Errorf("expected the data volume claim, got %q", ut.source.claim)
+	}
+
+	// The same numbers again don't write the status
+	ut.update(t)
+	if ut.statusPatches != 1 {
+		t.Errorf("expected 1 status patch, got %d", ut.statusPatches)
+	}
+
+	// New numbers do
+	ut.source.usage.CPU = resource.MustParse("500m")
+	if server := ut.update(t); server.Status.ResourceUsage.CPU.String() != "500m" || ut.statusPatches != 2 {
+		t.Errorf("expected the new CPU usage to be patched, got %s after %d patches", server.Status.ResourceUsage.CPU.String(), ut.statusPatches)
+	}
+}
+
+func TestUpdateResourceUsageClearedWithoutPod(t *testing.T) {
+	server := usageServer()
+	server.Status.ResourceUsage = &minecraftv2.ResourceUsage{CPU: resource.MustParse("250m")}
+	ut := newUsageTest(t, server)
+
+	if server := ut.update(t); server.Status.ResourceUsage != nil {
+		t.Errorf("expected usage to be cleared, got %+v", server.Status.ResourceUsage)
+	}
+	if ut.source.claim != "" {
+		t.Error("expected the source not to be read without a pod")
+	}
+
+	// Already cleared: nothing to write
+	ut.update(t)
+	if ut.statusPatches != 1 {
+		t.Errorf("expected 1 status patch, got %d", ut.statusPatches)
+	}
+}
+
+func TestUpdateResourceUsagePendingPod(t *testing.T) {
+	ut := newUsageTest(t, usageServer(), serverPod(corev1.PodPending))
+
+	if server := ut.update(t); server.Status.ResourceUsage != nil {
+		t.Errorf("expected no usage for a pending pod, got %+v", server.Status.ResourceUsage)
+	}
+	if ut.statusPatches != 0 {
+		t.Errorf("expected no status patch, got %d", ut.statusPatches)
+	}
+}
+
+func TestUpdateResourceUsageSourceError(t *testing.T) {
+	ut := newUsageTest(t, usageServer(), serverPod(corev1.PodRunning))
+	ut.source.err = errors.New("metrics unavailable")
+
+	var server minecraftv2.MinecraftServer
+	if err := ut.r.Get(context.Background(), types.NamespacedName{Name: "survival", Namespace: "default"}, &server); err != nil {
+		t.Fatal(err)
+	}
+	if err := ut.r.updateResourceUsage(context.Background(), &server); err == nil {
+		t.Error("expected the source error to be returned")
+	}
+	if ut.statusPatches != 0 {
+		t.Errorf("expected no status patch, got %d", ut.statusPatches)
+	}
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/router"
+)
+
+// hostnameIndexField indexes servers by status.hostname for the built-in router
+const hostnameIndexField = "status.hostname"
+
+// indexHostname returns the routing hostname a server was assigned
+func indexHostname(obj client.Object) []string {
+	server, ok := obj.(*minecraftv2.MinecraftServer)
+	if !ok || server.Status.Hostname == "" {
+		return nil
+	}
+	return []string{server.Status.Hostname}
+}
+
+// Resolve implements router.Resolver; a hostname shared by several servers resolves to none of them
+func (r *MinecraftServerReconciler) Resolve(ctx context.Context, hostname string) (*router.Backend, error) {
+	var servers minecraftv2.MinecraftServerList
+	if err := r.List(ctx, &servers, client.MatchingFields{hostnameIndexField: hostname}); err != nil {
+		return nil, fmt.Errorf("failed to list servers for %s: %w", hostname, err)
+	}
+	if len(servers.Items) != 1 {
+		return nil, nil
+	}
+
+	server := &servers.Items[0]
+	if !server.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	displayName := server.Spec.DisplayName
+	if displayName == "" {
+		displayName = server.Name
+	}
+
+	phase := server.Status.Phase
+	return &router.Backend{
+		ID:          server.Spec.ServerID,
+		DisplayName: displayName,
+		Address:     fmt.Sprintf("%s.%s.svc:25565", server.Name, server.Namespace),
+		Ready:       phase == minecraftv2.PhaseRunning,
+		Starting:    desiredReplicas(server) > 0 && (phase == minecraftv2.PhaseStarting || phase == minecraftv2.PhasePending),
+		CanWake:     server.Spec.EffectivePowerState() == minecraftv2.PowerAuto,
+		MaxPlayers:  server.Spec.Config.MaxPlayers,
+	}, nil
+}
+
+// Wake implements router.Resolver by waking a sleeping Auto server
+func (r *MinecraftServerReconciler) Wake(ctx context.Context, id, player string) error {
+	server, err := r.findServerByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if server.Spec.EffectivePowerState() != minecraftv2.PowerAuto {
+		return fmt.Errorf("server %s is not in the Auto power state", id)
+	}
+
+	woken, err := r.wakeServer(ctx, server, minecraftv2.WakeReasonPlayerConnect)
+	if err != nil {
+		return err
+	}
+	if woken {
+		log.FromContext(ctx).Info("Server woken by player connection", "serverID", id, "player", player)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// LoadSizeTiers reads custom size tiers from a YAML map of tier name to cpuRequest/cpuLimit/memory/storage
+// Tiers in the file replace built-in tiers of the same name; the other built-in tiers stay available
+func LoadSizeTiers(path string) (map[string]minecraftv2.SizeTier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var custom map[string]minecraftv2.SizeTier
+	if err := yaml.UnmarshalStrict(data, &custom); err != nil {
+		return nil, fmt.Errorf("failed to parse size tiers: %w", err)
+	}
+
+	tiers := minecraftv2.DefaultSizeTiers()
+	for name, tier := range custom {
+		if tier.Memory.IsZero() || tier.CPULimit.IsZero() || tier.Storage.IsZero() {
+			return nil, fmt.Errorf("size tier %s must set cpuLimit, memory and storage", name)
+		}
+		tiers[name] = tier
+	}
+	return tiers, nil
+}
+
+// resolveResources returns the server's resources with the gaps filled from its size tier
+func (r *MinecraftServerReconciler) resolveResources(server *minecraftv2.MinecraftServer) (minecraftv2.MinecraftServerResources, error) {
+	if server.Spec.Size == "" {
+		return server.Spec.Resources.Resolve(nil), nil
+	}
+
+	tiers := r.SizeTiers
+	if tiers == nil {
+		tiers = minecraftv2.DefaultSizeTiers()
+	}
+	tier, ok := tiers[server.Spec.Size]
+	if !ok {
+		return minecraftv2.MinecraftServerResources{}, fmt.Errorf("unknown size tier %q", server.Spec.Size)
+	}
+	return server.Spec.Resources.Resolve(&tier), nil
+}
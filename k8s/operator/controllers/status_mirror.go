@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/events"
+)
+
+// mirrorStatus copies the server status into the JetStream status bucket
+// Called after every successful status write so the bucket never lags behind the CR
+func (r *MinecraftServerReconciler) mirrorStatus(ctx context.Context, server *minecraftv2.MinecraftServer) {
+	if r.EventPublisher == nil {
+		return
+	}
+
+	if err := r.EventPublisher.PutServerStatus(statusSnapshot(server)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to mirror server status")
+	}
+}
+
+// statusSnapshot converts the server status into the snapshot stored in the status bucket
+func statusSnapshot(server *minecraftv2.MinecraftServer) *events.ServerStatusSnapshot {
+	snapshot := &events.ServerStatusSnapshot{
+		ServerID:        server.Spec.ServerID,
+		TenantID:        server.Spec.TenantID,
+		Namespace:       server.Namespace,
+		ResourceName:    server.Name,
+		Phase:           string(server.Status.Phase),
+		Message:         server.Status.Message,
+		ExternalIP:      server.Status.ExternalIP,
+		ExternalPort:    int(server.Status.Port),
+		Hostname:        server.Status.Hostname,
+		Sleeping:        server.Status.Sleeping,
+		BedrockEndpoint: server.Status.BedrockEndpoint,
+		PlayerCount:     server.Status.PlayerCount,
+		MaxPlayers:      server.Status.MaxPlayers,
+		Players:         server.Status.Players,
+		Version:         runningVersion(server),
+		UpdatedAt:       server.Status.LastUpdated.Time,
+	}
+
+	if server.Status.LastBackup != nil {
+		lastBackup := server.Status.LastBackup.Time
+		snapshot.LastBackup = &lastBackup
+	}
+
+	if usage := server.Status.ResourceUsage; usage != nil {
+		snapshot.ResourceUsage = &events.ResourceUsageSnapshot{
+			CPU:     usage.CPU.String(),
+			Memory:  usage.Memory.String(),
+			Storage: usage.Storage.String(),
+		}
+		if usage.NetworkIO != nil {
+			snapshot.ResourceUsage.RxBytes = usage.NetworkIO.RxBytes
+			snapshot.ResourceUsage.TxBytes = usage.NetworkIO.TxBytes
+		}
+	}
+
+	return snapshot
+}
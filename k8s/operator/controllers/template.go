@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// templateIndexField indexes servers by the template they reference
+const templateIndexField = "spec.templateRef.name"
+
+// indexTemplateRef returns the template a server is built from
+func indexTemplateRef(obj client.Object) []string {
+	server, ok := obj.(*minecraftv2.MinecraftServer)
+	if !ok || server.Spec.TemplateRef == nil {
+		return nil
+	}
+	return []string{server.Spec.TemplateRef.Name}
+}
+
+// errTemplateNotFound means a server references a template that doesn't exist and was never built from it
+type errTemplateNotFound struct {
+	name string
+}
+
+func (e *errTemplateNotFound) Error() string {
+	return fmt.Sprintf("MinecraftServerTemplate %q not found", e.name)
+}
+
+// applyTemplate writes the referenced template into the server's spec
+// The spec is updated in place, so everything reading the server (router, API server, networks) sees the result.
+// A server that was already built keeps its spec when its template is deleted.
+func (r *MinecraftServerReconciler) applyTemplate(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	logger := log.FromContext(ctx)
+
+	if server.Spec.TemplateRef == nil {
+		// Detached from its template: keep the values, forget where they came from
+		if _, ok := server.Annotations[minecraftv2.TemplateAppliedAnnotation]; !ok {
+			return nil
+		}
+		delete(server.Annotations, minecraftv2.TemplateAppliedAnnotation)
+		delete(server.Annotations, minecraftv2.TemplateDefaultsAnnotation)
+		if err := r.Update(ctx, server); err != nil {
+			return fmt.Errorf("failed to detach server from template: %w", err)
+		}
+		server.Status.TemplateRevision = 0
+		return nil
+	}
+
+	var template minecraftv2.MinecraftServerTemplate
+	if err := r.Get(ctx, types.NamespacedName{Name: server.Spec.TemplateRef.Name}, &template); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get template: %w", err)
+		}
+		if _, built := server.Annotations[minecraftv2.TemplateAppliedAnnotation]; built {
+			logger.Info("Template not found, keeping the spec it was built from", "template", server.Spec.TemplateRef.Name)
+			return nil
+		}
+		return &errTemplateNotFound{name: server.Spec.TemplateRef.Name}
+	}
+
+	var applied *minecraftv2.MinecraftServerTemplateSpec
+	if value, ok := server.Annotations[minecraftv2.TemplateAppliedAnnotation]; ok {
+		applied = &minecraftv2.MinecraftServerTemplateSpec{}
+		if err := json.Unmarshal([]byte(value), applied); err != nil {
+			// Without the previous revision every differing value counts as an override
+			logger.Info("Ignoring invalid template annotation", "error", err.Error())
+			applied = nil
+		}
+	}
+
+	spec := template.Spec.DeepCopy()
+	spec.Description = ""
+	spec.ApplyBoolDefaults()
+	appliedJSON, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+
+	// Servers attached to a template after they were created don't know which defaults were chosen; all of them follow
+	unset, ok := minecraftv2.ParseTemplateDefaults(server.Annotations)
+	if !ok {
+		unset = server.Spec.FieldsAtDefault()
+	}
+
+	before := server.DeepCopy()
+	server.Spec.ApplyTemplate(spec.DeepCopy(), applied, unset)
+	server.Default()
+	if server.Annotations == nil {
+		server.Annotations = map[string]string{}
+	}
+	server.Annotations[minecraftv2.TemplateAppliedAnnotation] = string(appliedJSON)
+	server.Annotations[minecraftv2.TemplateDefaultsAnnotation] = unset.String()
+
+	if !equality.Semantic.DeepEqual(before.Spec, server.Spec) || !equality.Semantic.DeepEqual(before.Annotations, server.Annotations) {
+		if err := r.Update(ctx, server); err != nil {
+			return fmt.Errorf("failed to apply template: %w", err)
+		}
+		logger.Info("Template applied", "template", template.Name, "revision", template.Generation)
+	}
+
+	server.Status.TemplateRevision = template.Generation
+	return nil
+}
+
+// serversForTemplate maps a template event to the servers built from it
+func (r *MinecraftServerReconciler) serversForTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	var servers minecraftv2.MinecraftServerList
+	if err := r.List(ctx, &servers, client.MatchingFields{templateIndexField: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list servers for template", "template", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(servers.Items))
+	for _, server := range servers.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: server.Name, Namespace: server.Namespace},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+func TestApplyTemplateNotFound(t *testing.T) {
+	server := &minecraftv2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "default"},
+		Spec: minecraftv2.MinecraftServerSpec{
+			TemplateRef: &minecraftv2.TemplateReference{Name: "missing"},
+		},
+	}
+	r := &MinecraftServerReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(server).Build()}
+
+	err := r.applyTemplate(context.Background(), server)
+	var notFound *errTemplateNotFound
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected errTemplateNotFound, got %v", err)
+	}
+
+	// Still recognised once wrapped
+	if !errors.As(fmt.Errorf("reconcile: %w", err), &notFound) {
+		t.Error("expected errors.As to find the wrapped errTemplateNotFound")
+	}
+}
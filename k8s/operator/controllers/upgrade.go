@@ -0,0 +1,487 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+	"minecraft-platform-operator/pkg/router"
+)
+
+// releaseVersionPattern finds the release version in a reported version name such as "Paper 1.20.4"
+var releaseVersionPattern = regexp.MustCompile(`\b1\.\d+(\.\d+)?\b`)
+
+// runningVersion is the Minecraft version the server runs, which lags spec.version during an upgrade
+func runningVersion(server *minecraftv2.MinecraftServer) string {
+	if server.Status.Version != "" {
+		return server.Status.Version
+	}
+	return server.Spec.Version
+}
+
+// recordHighestVersion raises Status.HighestVersion to the version the running server reports
+// The server list ping resolves aliases such as LATEST; when it fails the version the pod was started with is used.
+func (r *MinecraftServerReconciler) recordHighestVersion(ctx context.Context, server *minecraftv2.MinecraftServer) {
+	logger := log.FromContext(ctx)
+
+	var pod corev1.Pod
+	if err := r.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-0", server.Name), Namespace: server.Namespace}, &pod); err != nil {
+		return
+	}
+
+	var version string
+	if pod.Status.PodIP != "" {
+		pingCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+		name, err := router.QueryVersion(pingCtx, net.JoinHostPort(pod.Status.PodIP, "25565"))
+		cancel()
+		if err != nil {
+			logger.V(1).Info("Server list ping failed", "error", err.Error())
+		}
+		version = releaseVersionPattern.FindString(name)
+	}
+	if version == "" {
+		version = containerEnv(&pod, "minecraft-server", "VERSION")
+	}
+
+	if _, err := minecraftv2.ParseMinecraftVersion(version); err != nil {
+		return
+	}
+	if server.Status.HighestVersion == version || minecraftv2.IsDowngrade(version, server.Status.HighestVersion) {
+		return
+	}
+	logger.Info("Recording highest version the world has been opened with", "version", version, "previous", server.Status.HighestVersion)
+	server.Status.HighestVersion = version
+}
+
+// reconcileUpgrade moves a spec.version change through snapshot, optional dry run, rollout and rollback
+// Status.Version is the version the StatefulSet runs; the caller writes the status.
+// Servers without a world yet take the new version directly.
+func (r *MinecraftServerReconciler) reconcileUpgrade(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	status := &server.Status
+
+	// Only an upgrade waiting for the maintenance window stays queued
+	held := false
+	defer func() {
+		if !held {
+			clearPendingChange(server, minecraftv2.PendingUpgrade)
+		}
+	}()
+
+	// New servers, and servers from before upgrades were tracked, start on the spec version
+	if status.Version == "" {
+		status.Version = server.Spec.Version
+		return nil
+	}
+
+	if upgrade := status.Upgrade; upgrade != nil && upgrade.InProgress() {
+		switch {
+		case upgrade.ToVersion == server.Spec.Version:
+			return r.advanceUpgrade(ctx, server)
+		case upgrade.Phase == minecraftv2.UpgradeRestoring || upgrade.Phase == minecraftv2.UpgradeRollingOut ||
+			upgrade.Phase == minecraftv2.UpgradeRollingBack:
+			// The server already runs the new version or is being restored; the new target starts afterwards
+			return r.advanceUpgrade(ctx, server)
+		default:
+			// Nothing has changed on the server yet, so the upgrade starts over towards the new target
+			if err := r.deleteDryRun(ctx, server); err != nil {
+				return err
+			}
+			status.Upgrade = nil
+		}
+	}
+
+	if server.Spec.Version == status.Version {
+		return nil
+	}
+
+	// The webhook rejects downgrades without a backup to restore; this covers servers admitted without it
+	var restoreBackup string
+	if minecraftv2.IsDowngrade(server.Spec.Version, status.HighestVersion) {
+		if server.Spec.Upgrade == nil || !server.Spec.Upgrade.AllowDowngrade || server.Spec.Upgrade.RestoreBackup == "" {
+			message := fmt.Sprintf("Refusing to downgrade from %s to %s without upgrade.allowDowngrade and upgrade.restoreBackup",
+				status.HighestVersion, server.Spec.Version)
+			if status.Upgrade == nil || status.Upgrade.Message != message {
+				status.Upgrade = &minecraftv2.UpgradeStatus{FromVersion: status.Version, ToVersion: server.Spec.Version}
+				r.setUpgradePhase(server, minecraftv2.UpgradeFailed, message)
+			}
+			return nil
+		}
+		restoreBackup = server.Spec.Upgrade.RestoreBackup
+		// The webhook checks the ID too; it names a file on the shared backup volume
+		if !minecraftv2.ValidBackupID(restoreBackup) {
+			message := fmt.Sprintf("Refusing to restore backup %q: not a valid backup ID", restoreBackup)
+			if status.Upgrade == nil || status.Upgrade.Message != message {
+				status.Upgrade = &minecraftv2.UpgradeStatus{FromVersion: status.Version, ToVersion: server.Spec.Version}
+				r.setUpgradePhase(server, minecraftv2.UpgradeFailed, message)
+			}
+			return nil
+		}
+	}
+
+	// A rolled back or failed upgrade isn't retried until spec.version changes or the retry annotation is set
+	// Refused downgrades never took a snapshot and start as soon as they are acknowledged
+	if upgrade := status.Upgrade; upgrade != nil && upgrade.ToVersion == server.Spec.Version && upgrade.Snapshot != "" &&
+		(upgrade.Phase == minecraftv2.UpgradeRolledBack || upgrade.Phase == minecraftv2.UpgradeFailed) {
+		if _, retry := server.Annotations[minecraftv2.RetryUpgradeAnnotation]; !retry {
+			return nil
+		}
+		if err := r.removeAnnotation(ctx, server, minecraftv2.RetryUpgradeAnnotation); err != nil {
+			return err
+		}
+	}
+
+	hasWorld, err := r.hasWorld(ctx, server)
+	if err != nil {
+		return err
+	}
+	if !hasWorld {
+		status.Version = server.Spec.Version
+		status.Upgrade = nil
+		return nil
+	}
+
+	// The rollout and a possible rollback restart the server, so the upgrade starts in the maintenance window
+	if r.holdDisruptiveChanges(ctx, server) {
+		held = true
+		r.setPendingChange(server, minecraftv2.PendingUpgrade, fmt.Sprintf("version %s to %s", status.Version, server.Spec.Version))
+		return nil
+	}
+
+	status.Upgrade = &minecraftv2.UpgradeStatus{FromVersion: status.Version, ToVersion: server.Spec.Version, RestoreBackup: restoreBackup}
+	r.setUpgradePhase(server, minecraftv2.UpgradeSnapshotting, "Taking a snapshot of the world")
+	log.FromContext(ctx).Info("Starting upgrade", "from", status.Version, "to", server.Spec.Version)
+	return r.advanceUpgrade(ctx, server)
+}
+
+// advanceUpgrade runs the current upgrade phase and moves to the next one when it is done
+func (r *MinecraftServerReconciler) advanceUpgrade(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	logger := log.FromContext(ctx)
+	upgrade := server.Status.Upgrade
+	timeout := server.Spec.Upgrade.TimeoutDuration()
+	expired := upgrade.PhaseStartedAt != nil && r.now().Sub(upgrade.PhaseStartedAt.Time) > timeout
+
+	switch upgrade.Phase {
+	case minecraftv2.UpgradeSnapshotting:
+		// Named after the upgrade's start, so a retry of the same versions takes a fresh snapshot
+		// instead of finding the previous attempt's finished Job
+		if upgrade.Snapshot == "" {
+			var startedAt int64
+			if upgrade.PhaseStartedAt != nil {
+				startedAt = upgrade.PhaseStartedAt.Unix()
+			}
+			upgrade.Snapshot = snapshotJobName(server, fmt.Sprintf("version/%s/%s/%d", upgrade.FromVersion, upgrade.ToVersion, startedAt))
+		}
+		done, err := r.ensureSnapshot(ctx, server, upgrade.Snapshot)
+		if err != nil {
+			r.setUpgradePhase(server, minecraftv2.UpgradeFailed, fmt.Sprintf("Upgrade to %s stopped: %v", upgrade.ToVersion, err))
+			return nil
+		}
+		if !done {
+			return nil
+		}
+		if server.Spec.Upgrade != nil && server.Spec.Upgrade.DryRun {
+			r.setUpgradePhase(server, minecraftv2.UpgradeDryRun, fmt.Sprintf("Starting %s on a copy of the world", upgrade.ToVersion))
+			return nil
+		}
+		r.beginRollout(server)
+
+	case minecraftv2.UpgradeDryRun:
+		ready, failure, err := r.reconcileDryRun(ctx, server)
+		if err != nil {
+			return err
+		}
+		if !ready && failure == "" && expired {
+			failure = fmt.Sprintf("was not Ready within %s", timeout)
+		}
+		if !ready && failure == "" {
+			return nil
+		}
+		if err := r.deleteDryRun(ctx, server); err != nil {
+			return err
+		}
+		if failure != "" {
+			r.setUpgradePhase(server, minecraftv2.UpgradeFailed, fmt.Sprintf("Dry run of %s failed: it %s; the server keeps %s",
+				upgrade.ToVersion, failure, upgrade.FromVersion))
+			logger.Info("Upgrade dry run failed", "to", upgrade.ToVersion, "reason", failure)
+			return nil
+		}
+		r.beginRollout(server)
+
+	case minecraftv2.UpgradeRestoring:
+		finished, succeeded, err := r.restoreBackup(ctx, server, upgrade.RestoreBackup)
+		if err != nil || !finished {
+			return err
+		}
+		if !succeeded {
+			// The restore empties the volume first, so the snapshot has to be put back
+			r.setUpgradePhase(server, minecraftv2.UpgradeRollingBack, fmt.Sprintf("backup %s could not be restored", upgrade.RestoreBackup))
+			logger.Info("Rolling back downgrade", "to", upgrade.ToVersion, "backup", upgrade.RestoreBackup)
+			return nil
+		}
+		r.startRollout(server)
+
+	case minecraftv2.UpgradeRollingOut:
+		// Only a running server can prove the new version works, so the timeout counts from its start
+		waiting := fmt.Sprintf("%s is rolled out when the server starts", upgrade.ToVersion)
+		if desiredReplicas(server) == 0 {
+			if upgrade.Message != waiting {
+				r.setUpgradePhase(server, minecraftv2.UpgradeRollingOut, waiting)
+			}
+			return nil
+		}
+		if upgrade.Message == waiting {
+			r.startRollout(server)
+			return nil
+		}
+		ready, failure, err := r.podOnVersion(ctx, server, fmt.Sprintf("%s-0", server.Name), upgrade.ToVersion)
+		if err != nil {
+			return err
+		}
+		if ready {
+			// The world is now the restored backup, which the older version can open
+			if upgrade.RestoreBackup != "" {
+				server.Status.HighestVersion = upgrade.ToVersion
+			}
+			r.setUpgradePhase(server, minecraftv2.UpgradeSucceeded, fmt.Sprintf("Upgraded from %s to %s", upgrade.FromVersion, upgrade.ToVersion))
+			logger.Info("Upgrade succeeded", "from", upgrade.FromVersion, "to", upgrade.ToVersion)
+			return nil
+		}
+		if failure == "" && expired {
+			failure = fmt.Sprintf("was not Ready within %s", timeout)
+		}
+		if failure == "" {
+			return nil
+		}
+		// Scaled down by desiredReplicas while the snapshot is restored
+		server.Status.Version = upgrade.FromVersion
+		r.setUpgradePhase(server, minecraftv2.UpgradeRollingBack, fmt.Sprintf("%s %s", upgrade.ToVersion, failure))
+		logger.Info("Rolling back upgrade", "to", upgrade.ToVersion, "reason", failure)
+
+	case minecraftv2.UpgradeRollingBack:
+		return r.restoreSnapshot(ctx, server)
+	}
+
+	return nil
+}
+
+// beginRollout moves on once the snapshot and dry run are done; a downgrade restores its backup first
+func (r *MinecraftServerReconciler) beginRollout(server *minecraftv2.MinecraftServer) {
+	upgrade := server.Status.Upgrade
+	if upgrade.RestoreBackup != "" {
+		r.setUpgradePhase(server, minecraftv2.UpgradeRestoring, fmt.Sprintf("Restoring backup %s for %s", upgrade.RestoreBackup, upgrade.ToVersion))
+		return
+	}
+	r.startRollout(server)
+}
+
+// startRollout switches the server to the new version
+func (r *MinecraftServerReconciler) startRollout(server *minecraftv2.MinecraftServer) {
+	upgrade := server.Status.Upgrade
+	server.Status.Version = upgrade.ToVersion
+	r.setUpgradePhase(server, minecraftv2.UpgradeRollingOut, fmt.Sprintf("Waiting for the server to become Ready on %s", upgrade.ToVersion))
+}
+
+// setUpgradePhase moves the upgrade to phase and restarts the phase timer
+func (r *MinecraftServerReconciler) setUpgradePhase(server *minecraftv2.MinecraftServer, phase minecraftv2.UpgradePhase, message string) {
+	now := metav1.NewTime(r.now())
+	upgrade := server.Status.Upgrade
+	upgrade.Phase = phase
+	upgrade.PhaseStartedAt = &now
+	upgrade.Message = message
+}
+
+// podOnVersion reports whether the pod runs version and is Ready, or why it is failing
+func (r *MinecraftServerReconciler) podOnVersion(ctx context.Context, server *minecraftv2.MinecraftServer, name, version string) (bool, string, error) {
+	var pod corev1.Pod
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: server.Namespace}, &pod); err != nil {
+		if errors.IsNotFound(err) {
+			return false, "", nil
+		}
+		return false, "", fmt.Errorf("failed to get pod %s: %w", name, err)
+	}
+
+	// The StatefulSet may not have replaced the pod yet
+	if containerEnv(&pod, "minecraft-server", "VERSION") != version {
+		return false, "", nil
+	}
+
+	for _, cs := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+			return false, fmt.Sprintf("is crash looping (container %s, %d restarts)", cs.Name, cs.RestartCount), nil
+		}
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return true, "", nil
+		}
+	}
+	return false, "", nil
+}
+
+// containerEnv returns the value of an environment variable of a pod container
+func containerEnv(pod *corev1.Pod, container, name string) string {
+	for _, c := range pod.Spec.Containers {
+		if c.Name != container {
+			continue
+		}
+		for _, env := range c.Env {
+			if env.Name == name {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+// restoreSnapshot restores the pre-upgrade snapshot once the server pod is gone, then lets the server start again
+func (r *MinecraftServerReconciler) restoreSnapshot(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	upgrade := server.Status.Upgrade
+	finished, succeeded, err := r.restoreBackup(ctx, server, upgrade.Snapshot)
+	switch {
+	case err != nil || !finished:
+		return err
+	case succeeded:
+		r.setUpgradePhase(server, minecraftv2.UpgradeRolledBack, fmt.Sprintf("Rolled back to %s because %s", upgrade.FromVersion, upgrade.Message))
+	default:
+		r.setUpgradePhase(server, minecraftv2.UpgradeFailed, fmt.Sprintf("Restoring snapshot %s failed; the world may still be in %s format, restore a backup before starting",
+			upgrade.Snapshot, upgrade.ToVersion))
+	}
+	return nil
+}
+
+// restoreBackup replaces the server's world with a backup archive once the server pod is gone
+// The Job is named after the phase start, so a later upgrade restoring the same backup runs its own Job.
+func (r *MinecraftServerReconciler) restoreBackup(ctx context.Context, server *minecraftv2.MinecraftServer, backupID string) (finished, succeeded bool, err error) {
+	var pod corev1.Pod
+	err = r.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-0", server.Name), Namespace: server.Namespace}, &pod)
+	if err == nil {
+		return false, false, nil
+	}
+	if !errors.IsNotFound(err) {
+		return false, false, fmt.Errorf("failed to get server pod: %w", err)
+	}
+
+	if !minecraftv2.ValidBackupID(backupID) {
+		return true, false, nil
+	}
+
+	var startedAt int64
+	if t := server.Status.Upgrade.PhaseStartedAt; t != nil {
+		startedAt = t.Unix()
+	}
+	jobName := snapshotJobName(server, fmt.Sprintf("restore/%s/%d", backupID, startedAt))
+	var job batchv1.Job
+	err = r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: server.Namespace}, &job)
+	if errors.IsNotFound(err) {
+		restore := buildRestoreJob(server, jobName, fmt.Sprintf("minecraft-data-%s-0", server.Name), backupFilename(server, backupID))
+		if err := controllerutil.SetControllerReference(server, restore, r.Scheme); err != nil {
+			return false, false, err
+		}
+		if err := r.Create(ctx, restore); err != nil {
+			return false, false, fmt.Errorf("failed to create restore job: %w", err)
+		}
+		log.FromContext(ctx).Info("Restoring backup", "backup", backupID, "job", jobName)
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to get restore job: %w", err)
+	}
+
+	succeeded, _, finished = jobResult(&job)
+	return finished, succeeded, nil
+}
+
+// dryRunName is the StatefulSet running the new version on a copy of the world
+func dryRunName(server *minecraftv2.MinecraftServer) string {
+	return server.Name + "-dryrun"
+}
+
+// reconcileDryRun runs the new version on a volume restored from the snapshot, with no Service in front of it
+// Reports whether the copy became Ready, or why it failed
+func (r *MinecraftServerReconciler) reconcileDryRun(ctx context.Context, server *minecraftv2.MinecraftServer) (bool, string, error) {
+	upgrade := server.Status.Upgrade
+	resources, err := r.resolveResources(server)
+	if err != nil {
+		return false, "", err
+	}
+
+	// A downgrade starts from its restore backup, since the snapshot is in the newer format
+	archive := backupFilename(server, upgrade.Snapshot)
+	if upgrade.RestoreBackup != "" {
+		archive = backupFilename(server, upgrade.RestoreBackup)
+	}
+
+	name := dryRunName(server)
+	statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: server.Namespace}}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, statefulSet, func() error {
+		if err := controllerutil.SetControllerReference(server, statefulSet, r.Scheme); err != nil {
+			return err
+		}
+		if !statefulSet.CreationTimestamp.IsZero() {
+			return nil
+		}
+
+		// Not a network member and not routed to: the copy only has to start
+		podSpec := r.buildPodSpec(server, nil, resources)
+		for i := range podSpec.Containers[0].Env {
+			if podSpec.Containers[0].Env[i].Name == "VERSION" {
+				podSpec.Containers[0].Env[i].Value = upgrade.ToVersion
+			}
+		}
+		podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+			Name:    "restore-snapshot",
+			Image:   "alpine:latest",
+			Command: restoreCommand(archive),
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "minecraft-data", MountPath: "/data"},
+				{Name: "backup-storage", MountPath: "/backups", ReadOnly: true},
+			},
+		})
+		podSpec.Volumes = append(podSpec.Volumes, backupStorageVolume(true))
+
+		labels := map[string]string{"app": name, "minecraft.platform.com/dry-run-of": server.Name}
+		replicas := int32(1)
+		statefulSet.Labels = labels
+		statefulSet.Spec = appsv1.StatefulSetSpec{
+			Replicas:             &replicas,
+			ServiceName:          name,
+			Selector:             &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			Template:             corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}, Spec: podSpec},
+			VolumeClaimTemplates: r.buildVolumeClaimTemplates(server, resources),
+		}
+		return nil
+	})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to create dry run: %w", err)
+	}
+
+	return r.podOnVersion(ctx, server, name+"-0", upgrade.ToVersion)
+}
+
+// deleteDryRun removes the dry run StatefulSet and its copy of the world
+func (r *MinecraftServerReconciler) deleteDryRun(ctx context.Context, server *minecraftv2.MinecraftServer) error {
+	name := dryRunName(server)
+	objects := []client.Object{
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: server.Namespace}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("minecraft-data-%s-0", name), Namespace: server.Namespace}},
+	}
+	for _, object := range objects {
+		if err := r.Delete(ctx, object, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete dry run: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	minecraftv2 "minecraft-platform-operator/api/v2"
+)
+
+// upgradeTest drives reconcileUpgrade on a fake client, playing the Job controller and the kubelet itself
+type upgradeTest struct {
+	t      *testing.T
+	ctx    context.Context
+	clock  *clocktesting.FakePassiveClock
+	r      *MinecraftServerReconciler
+	server *minecraftv2.MinecraftServer
+}
+
+// newUpgradeTest creates a running server with a world on 1.20.4 whose spec asks for 1.21.1
+func newUpgradeTest(t *testing.T, upgrade *minecraftv2.UpgradeConfig) *upgradeTest {
+	t.Helper()
+	server := &minecraftv2.MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "default", UID: "server-uid"},
+		Spec: minecraftv2.MinecraftServerSpec{
+			ServerID:     "server-1",
+			TenantID:     "tenant-1",
+			ServerType:   "PAPER",
+			Version:      "1.21.1",
+			RCONPassword: "rcon-secret",
+			Upgrade:      upgrade,
+		},
+		Status: minecraftv2.MinecraftServerStatus{Version: "1.20.4", HighestVersion: "1.20.4"},
+	}
+	server.Default()
+	claim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "minecraft-data-survival-0", Namespace: "default"}}
+
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(server, claim).Build()
+	clock := clocktesting.NewFakePassiveClock(time.Now())
+	ut := &upgradeTest{
+		t:      t,
+		ctx:    context.Background(),
+		clock:  clock,
+		r:      &MinecraftServerReconciler{Client: c, Scheme: testScheme, Clock: clock},
+		server: server,
+	}
+	ut.setPod("survival-0", "1.20.4", true)
+	return ut
+}
+
+// reconcile runs reconcileUpgrade and returns the upgrade status
+func (ut *upgradeTest) reconcile() *minecraftv2.UpgradeStatus {
+	ut.t.Helper()
+	if err := ut.r.reconcileUpgrade(ut.ctx, ut.server); err != nil {
+		ut.t.Fatalf("reconcileUpgrade: %v", err)
+	}
+	if ut.server.Status.Upgrade == nil {
+		ut.t.Fatal("no upgrade in status")
+	}
+	return ut.server.Status.Upgrade
+}
+
+// expectPhase checks the upgrade phase and the version the StatefulSet runs
+func (ut *upgradeTest) expectPhase(phase minecraftv2.UpgradePhase, version string) {
+	ut.t.Helper()
+	upgrade := ut.server.Status.Upgrade
+	if upgrade == nil || upgrade.Phase != phase {
+		ut.t.Fatalf("expected phase %s, got %+v", phase, upgrade)
+	}
+	if ut.server.Status.Version != version {
+		ut.t.Errorf("expected status.version %s, got %s", version, ut.server.Status.Version)
+	}
+}
+
+// setPod replaces a pod with one running version, Ready or not
+func (ut *upgradeTest) setPod(name, version string, ready bool) {
+	ut.t.Helper()
+	ut.deletePod(name)
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ut.server.Namespace},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "minecraft-server",
+			Env:  []corev1.EnvVar{{Name: "VERSION", Value: version}},
+		}}},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}}},
+	}
+	if err := ut.r.Create(ut.ctx, pod); err != nil {
+		ut.t.Fatalf("failed to create pod: %v", err)
+	}
+}
+
+// deletePod plays the StatefulSet controller scaling the server down
+func (ut *upgradeTest) deletePod(name string) {
+	ut.t.Helper()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ut.server.Namespace}}
+	if err := ut.r.Delete(ut.ctx, pod); err != nil && !errors.IsNotFound(err) {
+		ut.t.Fatalf("failed to delete pod: %v", err)
+	}
+}
+
+// finishJob plays the Job controller: the named Job completes or fails
+func (ut *upgradeTest) finishJob(name string, succeeded bool) {
+	ut.t.Helper()
+	var job batchv1.Job
+	if err := ut.r.Get(ut.ctx, types.NamespacedName{Name: name, Namespace: ut.server.Namespace}, &job); err != nil {
+		ut.t.Fatalf("failed to get job %s: %v", name, err)
+	}
+	conditionType := batchv1.JobComplete
+	if !succeeded {
+		conditionType = batchv1.JobFailed
+	}
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+	})
+	if err := ut.r.Status().Update(ut.ctx, &job); err != nil {
+		ut.t.Fatalf("failed to finish job %s: %v", name, err)
+	}
+}
+
+// restoreJobs lists the restore Jobs created for the server
+func (ut *upgradeTest) restoreJobs() []batchv1.Job {
+	ut.t.Helper()
+	var jobs batchv1.JobList
+	if err := ut.r.List(ut.ctx, &jobs, client.MatchingLabels{"app": "minecraft-restore"}); err != nil {
+		ut.t.Fatalf("failed to list jobs: %v", err)
+	}
+	return jobs.Items
+}
+
+// expire moves the clock past the upgrade timeout
+func (ut *upgradeTest) expire() {
+	ut.clock.SetTime(ut.clock.Now().Add(minecraftv2.DefaultUpgradeTimeout + time.Minute))
+}
+
+func TestUpgradeSnapshotThenRollout(t *testing.T) {
+	ut := newUpgradeTest(t, nil)
+
+	upgrade := ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeSnapshotting, "1.20.4")
+	if upgrade.Snapshot == "" {
+		t.Fatal("no snapshot recorded")
+	}
+
+	// The server keeps the old version until the snapshot has finished
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeSnapshotting, "1.20.4")
+
+	ut.finishJob(upgrade.Snapshot, true)
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeRollingOut, "1.21.1")
+
+	// The old pod doesn't count; the new one has to become Ready
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeRollingOut, "1.21.1")
+	ut.setPod("survival-0", "1.21.1", false)
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeRollingOut, "1.21.1")
+	ut.setPod("survival-0", "1.21.1", true)
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeSucceeded, "1.21.1")
+}
+
+func TestUpgradeFailedSnapshotStopsUpgrade(t *testing.T) {
+	ut := newUpgradeTest(t, nil)
+
+	upgrade := ut.reconcile()
+	ut.finishJob(upgrade.Snapshot, false)
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeFailed, "1.20.4")
+}
+
+func TestUpgradeDryRun(t *testing.T) {
+	ut := newUpgradeTest(t, &minecraftv2.UpgradeConfig{DryRun: true})
+
+	upgrade := ut.reconcile()
+	ut.finishJob(upgrade.Snapshot, true)
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeDryRun, "1.20.4")
+
+	// The copy runs the new version on a volume restored from the snapshot
+	ut.reconcile()
+	var dryRun appsv1.StatefulSet
+	if err := ut.r.Get(ut.ctx, types.NamespacedName{Name: "survival-dryrun", Namespace: "default"}, &dryRun); err != nil {
+		t.Fatalf("dry run not created: %v", err)
+	}
+	if env := containerEnv(&corev1.Pod{Spec: dryRun.Spec.Template.Spec}, "minecraft-server", "VERSION"); env != "1.21.1" {
+		t.Errorf("dry run runs %q, expected 1.21.1", env)
+	}
+	ut.expectPhase(minecraftv2.UpgradeDryRun, "1.20.4")
+
+	ut.setPod("survival-dryrun-0", "1.21.1", true)
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeRollingOut, "1.21.1")
+	if err := ut.r.Get(ut.ctx, types.NamespacedName{Name: "survival-dryrun", Namespace: "default"}, &dryRun); !errors.IsNotFound(err) {
+		t.Errorf("dry run not deleted: %v", err)
+	}
+}
+
+func TestUpgradeDryRunTimeout(t *testing.T) {
+	ut := newUpgradeTest(t, &minecraftv2.UpgradeConfig{DryRun: true})
+
+	upgrade := ut.reconcile()
+	ut.finishJob(upgrade.Snapshot, true)
+	ut.reconcile()
+	ut.reconcile()
+	ut.setPod("survival-dryrun-0", "1.21.1", false)
+
+	// The server itself was never touched, so a failed dry run leaves it on the old version
+	ut.expire()
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeFailed, "1.20.4")
+}
+
+func TestUpgradeRollbackOnTimeout(t *testing.T) {
+	ut := newUpgradeTest(t, nil)
+
+	upgrade := ut.reconcile()
+	snapshot := upgrade.Snapshot
+	ut.finishJob(snapshot, true)
+	ut.reconcile()
+	ut.setPod("survival-0", "1.21.1", false)
+
+	ut.expire()
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeRollingBack, "1.20.4")
+	if desiredReplicas(ut.server) != 0 {
+		t.Error("server not scaled down for the rollback")
+	}
+
+	// The snapshot is only restored once the pod is gone
+	ut.reconcile()
+	if jobs := ut.restoreJobs(); len(jobs) != 0 {
+		t.Fatalf("restore started while the pod runs: %d jobs", len(jobs))
+	}
+	ut.deletePod("survival-0")
+	ut.reconcile()
+	jobs := ut.restoreJobs()
+	if len(jobs) != 1 {
+		t.Fatalf("expected one restore job, got %d", len(jobs))
+	}
+	if command := jobs[0].Spec.Template.Spec.Containers[0].Command; command[len(command)-1] != "/backups/"+backupFilename(ut.server, snapshot) {
+		t.Errorf("restore doesn't use the snapshot: %v", command)
+	}
+	ut.expectPhase(minecraftv2.UpgradeRollingBack, "1.20.4")
+
+	ut.finishJob(jobs[0].Name, true)
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeRolledBack, "1.20.4")
+
+	// A rolled back upgrade waits for the retry annotation
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeRolledBack, "1.20.4")
+}
+
+func TestUpgradeCrashLoopRollsBack(t *testing.T) {
+	ut := newUpgradeTest(t, nil)
+
+	upgrade := ut.reconcile()
+	ut.finishJob(upgrade.Snapshot, true)
+	ut.reconcile()
+
+	ut.deletePod("survival-0")
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival-0", Namespace: "default"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "minecraft-server",
+			Env:  []corev1.EnvVar{{Name: "VERSION", Value: "1.21.1"}},
+		}}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:         "minecraft-server",
+			RestartCount: 4,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}},
+	}
+	if err := ut.r.Create(ut.ctx, pod); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+
+	// No need to wait for the timeout
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeRollingBack, "1.20.4")
+}
+
+func TestUpgradeRetryTakesFreshSnapshot(t *testing.T) {
+	ut := newUpgradeTest(t, nil)
+
+	// The first attempt times out and is rolled back
+	previous := ut.reconcile().Snapshot
+	ut.finishJob(previous, true)
+	ut.reconcile()
+	ut.expire()
+	ut.reconcile()
+	ut.deletePod("survival-0")
+	ut.reconcile()
+	ut.finishJob(ut.restoreJobs()[0].Name, true)
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeRolledBack, "1.20.4")
+
+	// Retried while the first snapshot's Job is still around
+	ut.clock.SetTime(ut.clock.Now().Add(time.Minute))
+	ut.server.Annotations = map[string]string{minecraftv2.RetryUpgradeAnnotation: "true"}
+	upgrade := ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeSnapshotting, "1.20.4")
+	if upgrade.Snapshot == previous {
+		t.Fatalf("retry reused snapshot %s", previous)
+	}
+	if _, retry := ut.server.Annotations[minecraftv2.RetryUpgradeAnnotation]; retry {
+		t.Error("retry annotation not removed")
+	}
+
+	// The world changed since the old snapshot, so the retry waits for its own
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeSnapshotting, "1.20.4")
+	ut.finishJob(upgrade.Snapshot, true)
+	ut.reconcile()
+	ut.expectPhase(minecraftv2.UpgradeRollingOut, "1.21.1")
+}
@@ -4,9 +4,13 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -14,11 +18,18 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	minecraftv1 "minecraft-platform-operator/api/v1"
+	minecraftv2 "minecraft-platform-operator/api/v2"
 	"minecraft-platform-operator/controllers"
 	"minecraft-platform-operator/pkg/events"
+	"minecraft-platform-operator/pkg/modpack"
+	"minecraft-platform-operator/pkg/registry"
+	"minecraft-platform-operator/pkg/router"
+	"minecraft-platform-operator/pkg/usage"
 )
 
 var (
@@ -29,14 +40,29 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(minecraftv1.AddToScheme(scheme))
+	utilruntime.Must(minecraftv2.AddToScheme(scheme))
 }
 
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
-	var natsURL string
 	var enableEvents bool
+	var enableCommands bool
+	var enableWebhooks bool
+	var webhookPort int
+	var webhookCertDir string
+	var usageInterval time.Duration
+	var exporterImage string
+	var pinImages bool
+	var maintenanceWindowsFile string
+	var sizeTiersFile string
+	var serviceType string
+	var networkDefaults controllers.NetworkDefaults
+	var routerOptions router.Options
+	var natsStreamSubjects string
+	var natsTenantPrefixes string
+	natsConfig := events.DefaultConfig()
 
 	// Default kubeconfig path
 	var kubeconfig string
@@ -51,8 +77,39 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-	flag.StringVar(&natsURL, "nats-url", "nats://nats.minecraft-system:4222", "NATS server URL for event publishing")
+	flag.StringVar(&natsConfig.NATSUrl, "nats-url", natsConfig.NATSUrl, "NATS server URL for event publishing")
+	flag.StringVar(&natsConfig.TLSCAFile, "nats-tls-ca", "", "CA bundle used to verify the NATS server certificate")
+	flag.StringVar(&natsConfig.TLSCertFile, "nats-tls-cert", "", "Client certificate for NATS mTLS")
+	flag.StringVar(&natsConfig.TLSKeyFile, "nats-tls-key", "", "Client key for NATS mTLS")
+	flag.StringVar(&natsConfig.CredsFile, "nats-creds", "", "NATS JWT/NKey .creds file")
+	flag.StringVar(&natsConfig.NKeySeedFile, "nats-nkey-seed", "", "NATS NKey seed file (ignored if --nats-creds is set)")
+	flag.StringVar(&natsConfig.StreamName, "nats-stream", natsConfig.StreamName, "JetStream stream that captures operator events")
+	flag.StringVar(&natsStreamSubjects, "nats-stream-subjects", "", "Comma-separated stream subjects (default: server.*, sync.*, tenant.*.server.*.* and, with --nats-legacy-subjects, k8s.* under each subject prefix)")
+	flag.DurationVar(&natsConfig.StreamMaxAge, "nats-stream-max-age", natsConfig.StreamMaxAge, "Maximum age of messages kept in the stream")
+	flag.Int64Var(&natsConfig.StreamMaxMsgs, "nats-stream-max-msgs", natsConfig.StreamMaxMsgs, "Maximum number of messages kept in the stream")
+	flag.IntVar(&natsConfig.StreamReplicas, "nats-stream-replicas", natsConfig.StreamReplicas, "Stream replicas in a clustered JetStream")
+	flag.StringVar(&natsConfig.StatusBucket, "nats-status-bucket", natsConfig.StatusBucket, "JetStream KV bucket mirroring live server status (empty disables)")
+	flag.StringVar(&natsConfig.SubjectPrefix, "nats-subject-prefix", "", "Prefix prepended to every published subject")
+	flag.StringVar(&natsTenantPrefixes, "nats-tenant-prefixes", "", "Per-tenant subject prefixes as tenant=prefix,tenant=prefix")
+	flag.BoolVar(&natsConfig.TenantStreams, "nats-tenant-streams", false, "Store each tenant's events in its own JetStream stream")
+	flag.BoolVar(&natsConfig.LegacySubjects, "nats-legacy-subjects", natsConfig.LegacySubjects, "Also publish events on the shared k8s.<type> subjects (deprecated; defaults to false in the next release)")
 	flag.BoolVar(&enableEvents, "enable-events", true, "Enable NATS event publishing")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", true, "Serve the MinecraftServer admission and v1 <-> v2 conversion webhooks; the installed CRD relies on them")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the admission webhook server binds to.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "Directory containing tls.crt and tls.key for the webhook server")
+	flag.DurationVar(&usageInterval, "resource-usage-interval", controllers.DefaultUsageInterval, "How often server CPU/memory/storage/network usage is collected (0 disables)")
+	flag.StringVar(&serviceType, "default-service-type", string(corev1.ServiceTypeLoadBalancer), "Game Service type for servers without spec.network.serviceType (LoadBalancer, NodePort or ClusterIP)")
+	flag.StringVar(&networkDefaults.LoadBalancerClass, "default-load-balancer-class", "", "Load balancer class for LoadBalancer game Services without spec.network.loadBalancerClass")
+	flag.StringVar(&networkDefaults.Hostname, "default-router-hostname", "", "mc-router hostname for servers without spec.network.hostname (empty: no router annotation)")
+	flag.StringVar(&networkDefaults.Domain, "router-domain", "", "Give servers <displayName>.<tenant>.<domain> hostnames under this domain (takes precedence over --default-router-hostname)")
+	flag.StringVar(&routerOptions.Address, "router-bind-address", "", "Address the built-in wake-on-connect router listens on, e.g. :25565 (empty disables; use instead of mc-router)")
+	flag.DurationVar(&routerOptions.HoldTimeout, "router-hold-timeout", 25*time.Second, "How long the router holds a login to a waking server before kicking the player")
+	flag.DurationVar(&routerOptions.RetryAfter, "router-retry-after", 60*time.Second, "Start time shown to players kicked while their server wakes up")
+	flag.StringVar(&exporterImage, "exporter-image", "minecraft-platform-operator:latest", "Image of the in-game metrics sidecar injected by spec.monitoring (must contain /exporter)")
+	flag.BoolVar(&pinImages, "pin-image-digests", true, "Resolve spec.image tags to digests through the image registry and run servers on the pinned digest")
+	flag.StringVar(&maintenanceWindowsFile, "maintenance-windows-file", "", "YAML file of tenant default maintenance windows (tenantId: {schedule, duration, timezone}) for servers without spec.maintenanceWindow")
+	flag.StringVar(&sizeTiersFile, "size-tiers-file", "", "YAML file of custom spec.size tiers (name: {cpuRequest, cpuLimit, memory, storage}), merged over the built-in XS-XL tiers")
+	flag.BoolVar(&enableCommands, "enable-commands", true, "Execute server commands received on NATS cmd.* subjects (requires --enable-events)")
 
 	opts := zap.Options{
 		Development: true,
@@ -62,15 +119,33 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	switch networkDefaults.ServiceType = corev1.ServiceType(serviceType); networkDefaults.ServiceType {
+	case corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort, corev1.ServiceTypeClusterIP:
+	default:
+		setupLog.Error(nil, "invalid --default-service-type", "value", serviceType)
+		os.Exit(1)
+	}
+	if networkDefaults.Domain != "" {
+		if msgs := validation.IsDNS1123Subdomain(networkDefaults.Domain); len(msgs) > 0 {
+			setupLog.Error(nil, "invalid --router-domain", "value", networkDefaults.Domain, "reason", strings.Join(msgs, "; "))
+			os.Exit(1)
+		}
+	}
+
 	// Initialize event publisher if enabled
 	var eventPublisher *events.EventPublisher
 	if enableEvents {
-		var err error
-		eventPublisher, err = events.NewEventPublisher(&events.EventPublisherConfig{
-			NATSUrl:    natsURL,
-			StreamName: "MINECRAFT_EVENTS",
-			Enabled:    true,
-		})
+		if natsStreamSubjects != "" {
+			natsConfig.StreamSubjects = strings.Split(natsStreamSubjects, ",")
+		}
+		tenantPrefixes, err := events.ParseTenantPrefixes(natsTenantPrefixes)
+		if err != nil {
+			setupLog.Error(err, "invalid --nats-tenant-prefixes")
+			os.Exit(1)
+		}
+		natsConfig.TenantSubjectPrefixes = tenantPrefixes
+
+		eventPublisher, err = events.NewEventPublisher(natsConfig)
 		if err != nil {
 			setupLog.Info("Warning: Could not connect to NATS, events will be disabled", "error", err)
 			eventPublisher = nil
@@ -101,6 +176,10 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "minecraft-platform-operator.minecraft.platform.com",
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -114,17 +193,99 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controllers.MinecraftServerReconciler{
-		Client:         mgr.GetClient(),
-		Scheme:         mgr.GetScheme(),
-		EventPublisher: eventPublisher,
-		Clientset:      clientset,
-		RestConfig:     restConfig,
-	}).SetupWithManager(mgr); err != nil {
+	reconciler := &controllers.MinecraftServerReconciler{
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		EventPublisher:  eventPublisher,
+		Clientset:       clientset,
+		RestConfig:      restConfig,
+		UsageInterval:   usageInterval,
+		Network:         networkDefaults,
+		ExporterImage:   exporterImage,
+		ModpackResolver: modpack.NewHTTPResolver(nil),
+		Recorder:        mgr.GetEventRecorderFor("minecraftserver-controller"),
+	}
+	if sizeTiersFile != "" {
+		reconciler.SizeTiers, err = controllers.LoadSizeTiers(sizeTiersFile)
+		if err != nil {
+			setupLog.Error(err, "invalid --size-tiers-file", "path", sizeTiersFile)
+			os.Exit(1)
+		}
+	}
+	if maintenanceWindowsFile != "" {
+		reconciler.MaintenanceWindows, err = controllers.LoadMaintenanceWindows(maintenanceWindowsFile)
+		if err != nil {
+			setupLog.Error(err, "invalid --maintenance-windows-file", "path", maintenanceWindowsFile)
+			os.Exit(1)
+		}
+	}
+	if pinImages {
+		reconciler.ImageResolver = registry.NewHTTPResolver(nil)
+	}
+	if usageInterval > 0 {
+		reconciler.UsageSource = usage.NewKubernetesSource(clientset.CoreV1().RESTClient())
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MinecraftServer")
 		os.Exit(1)
 	}
 
+	if err = (&controllers.MinecraftNetworkReconciler{
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Network: networkDefaults,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MinecraftNetwork")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.MinecraftServerTemplateReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MinecraftServerTemplate")
+		os.Exit(1)
+	}
+
+	// Collect server resource usage into status
+	if err := mgr.Add(manager.RunnableFunc(reconciler.RunUsageCollector)); err != nil {
+		setupLog.Error(err, "unable to add resource usage collector")
+		os.Exit(1)
+	}
+
+	// Route players by hostname and wake sleeping servers on connect
+	if routerOptions.Address != "" {
+		if err := mgr.Add(router.New(routerOptions, reconciler)); err != nil {
+			setupLog.Error(err, "unable to add router")
+			os.Exit(1)
+		}
+	}
+
+	// Apply defaults and reject invalid specs at admission time, and convert between v1 and v2
+	// The CRD from config/crd uses the Webhook conversion strategy, so without webhooks the v1 API fails;
+	// only disable them with the plain CRD (conversion strategy None) and v2 clients
+	if enableWebhooks {
+		if err = (&minecraftv2.MinecraftServer{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MinecraftServer")
+			os.Exit(1)
+		}
+	} else {
+		setupLog.Info("Webhooks disabled: MinecraftServer v1 is not converted and specs are not defaulted or validated")
+	}
+
+	// Execute server commands sent over NATS (cmd.<tenant>.<server>.<action>)
+	if eventPublisher != nil && enableCommands {
+		commandSubscriber, err := events.NewCommandSubscriber(eventPublisher, reconciler)
+		if err != nil {
+			setupLog.Error(err, "unable to create command subscriber")
+			os.Exit(1)
+		}
+		if err := mgr.Add(manager.RunnableFunc(commandSubscriber.Start)); err != nil {
+			setupLog.Error(err, "unable to add command subscriber")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
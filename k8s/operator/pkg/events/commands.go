@@ -0,0 +1,260 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Command actions accepted on cmd.<tenant>.<server>.<action>
+const (
+	CommandStart   = "start"
+	CommandStop    = "stop"
+	CommandRestart = "restart"
+	CommandRCON    = "rcon"
+	CommandBackup  = "backup"
+)
+
+const (
+	// CommandSubject is the wildcard subject the operator subscribes to
+	CommandSubject = "cmd.*.*.*"
+
+	// CommandQueueGroup load-balances commands across operator replicas
+	CommandQueueGroup = "minecraft-operator"
+
+	// commandTimeout bounds how long a single command may run before replying
+	commandTimeout = 30 * time.Second
+
+	// maxConcurrentCommands bounds how many commands run at once; further commands wait for a free slot
+	maxConcurrentCommands = 16
+)
+
+var (
+	// ErrCommandForbidden is returned when the requesting tenant does not own the server
+	ErrCommandForbidden = errors.New("server does not belong to tenant")
+
+	// ErrServerNotFound is returned when no MinecraftServer matches the server ID
+	ErrServerNotFound = errors.New("server not found")
+)
+
+// ServerCommand is a command addressed to a single server
+type ServerCommand struct {
+	TenantID string `json:"tenant_id"`
+	ServerID string `json:"server_id"`
+	Action   string `json:"action"`
+
+	// Command is the console command for rcon actions
+	Command string `json:"command,omitempty"`
+
+	// RequestID is echoed back in the reply for correlation
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// CommandReply reports the outcome of a ServerCommand
+type CommandReply struct {
+	RequestID string    `json:"request_id,omitempty"`
+	TenantID  string    `json:"tenant_id"`
+	ServerID  string    `json:"server_id"`
+	Action    string    `json:"action"`
+	Success   bool      `json:"success"`
+	Output    string    `json:"output,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Code      string    `json:"code,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Reply error codes
+const (
+	ReplyCodeBadRequest = "bad_request"
+	ReplyCodeForbidden  = "forbidden"
+	ReplyCodeNotFound   = "not_found"
+	ReplyCodeFailed     = "failed"
+)
+
+// CommandHandler executes server commands received over NATS
+// Implementations must verify that cmd.TenantID owns cmd.ServerID and return ErrCommandForbidden otherwise
+type CommandHandler interface {
+	HandleCommand(ctx context.Context, cmd *ServerCommand) (string, error)
+}
+
+// CommandSubscriber listens for server commands and replies with their outcome
+type CommandSubscriber struct {
+	publisher *EventPublisher
+	handler   CommandHandler
+
+	// slots holds a token for every command being handled
+	slots chan struct{}
+}
+
+// NewCommandSubscriber creates a command subscriber on the publisher's NATS connection
+// The subscriber follows the publisher's connection, so it resubscribes after a Reconnect
+func NewCommandSubscriber(publisher *EventPublisher, handler CommandHandler) (*CommandSubscriber, error) {
+	if publisher == nil || !publisher.IsConnected() {
+		return nil, fmt.Errorf("event publisher is not connected to NATS")
+	}
+
+	return &CommandSubscriber{
+		publisher: publisher,
+		handler:   handler,
+		slots:     make(chan struct{}, maxConcurrentCommands),
+	}, nil
+}
+
+// Start subscribes to the command subject and blocks until ctx is cancelled
+// Handlers run with a context derived from ctx so they stop when the operator shuts down;
+// Start returns once the commands still running have replied
+func (cs *CommandSubscriber) Start(ctx context.Context) error {
+	for {
+		conn, replaced := cs.publisher.connection()
+		if conn == nil {
+			return fmt.Errorf("event publisher is not connected to NATS")
+		}
+
+		sub, err := conn.QueueSubscribe(CommandSubject, CommandQueueGroup, func(msg *nats.Msg) {
+			cs.dispatch(ctx, msg)
+		})
+		if err != nil && !conn.IsClosed() {
+			return fmt.Errorf("failed to subscribe to %s: %w", CommandSubject, err)
+		}
+		if err == nil {
+			log.Printf("CommandSubscriber listening on %s (queue: %s)", CommandSubject, CommandQueueGroup)
+		}
+
+		// A closed connection is replaced by the publisher; subscribe again on the new one
+		select {
+		case <-ctx.Done():
+			if sub != nil {
+				if err := sub.Unsubscribe(); err != nil && !errors.Is(err, nats.ErrConnectionClosed) {
+					log.Printf("Warning: Could not unsubscribe from commands: %v", err)
+				}
+			}
+			cs.drain()
+			return nil
+		case <-replaced:
+			log.Printf("CommandSubscriber resubscribing on the new NATS connection")
+		}
+	}
+}
+
+// dispatch handles the message on its own goroutine, so a slow RCON or backup command
+// doesn't hold up the commands of other tenants behind it
+func (cs *CommandSubscriber) dispatch(ctx context.Context, msg *nats.Msg) {
+	select {
+	case cs.slots <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	go func() {
+		defer func() { <-cs.slots }()
+		cs.handleMessage(ctx, msg)
+	}()
+}
+
+// drain waits for the running commands by taking every slot; nothing is dispatched afterwards
+func (cs *CommandSubscriber) drain() {
+	for i := 0; i < cap(cs.slots); i++ {
+		cs.slots <- struct{}{}
+	}
+}
+
+// handleMessage parses, authorises and executes a single command message
+func (cs *CommandSubscriber) handleMessage(ctx context.Context, msg *nats.Msg) {
+	cmd, err := ParseCommand(msg.Subject, msg.Data)
+	if err != nil {
+		cs.respond(msg, &CommandReply{
+			Success: false,
+			Error:   err.Error(),
+			Code:    ReplyCodeBadRequest,
+		}, cmd)
+		return
+	}
+
+	cmdCtx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	output, err := cs.handler.HandleCommand(cmdCtx, cmd)
+	reply := &CommandReply{
+		Success: err == nil,
+		Output:  output,
+	}
+	if err != nil {
+		reply.Error = err.Error()
+		switch {
+		case errors.Is(err, ErrCommandForbidden):
+			reply.Code = ReplyCodeForbidden
+		case errors.Is(err, ErrServerNotFound):
+			reply.Code = ReplyCodeNotFound
+		default:
+			reply.Code = ReplyCodeFailed
+		}
+	}
+
+	log.Printf("Handled command %s (tenant: %s, server: %s, success: %t)", cmd.Action, cmd.TenantID, cmd.ServerID, reply.Success)
+	cs.respond(msg, reply, cmd)
+}
+
+// respond sends the reply if the message was a request
+func (cs *CommandSubscriber) respond(msg *nats.Msg, reply *CommandReply, cmd *ServerCommand) {
+	if msg.Reply == "" {
+		return // Fire-and-forget publish, nobody is waiting
+	}
+
+	if cmd != nil {
+		reply.RequestID = cmd.RequestID
+		reply.TenantID = cmd.TenantID
+		reply.ServerID = cmd.ServerID
+		reply.Action = cmd.Action
+	}
+	reply.Timestamp = time.Now()
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		log.Printf("Warning: Could not marshal command reply: %v", err)
+		return
+	}
+	if err := msg.Respond(data); err != nil {
+		log.Printf("Warning: Could not send command reply: %v", err)
+	}
+}
+
+// ParseCommand builds a ServerCommand from the subject and optional JSON payload
+// The tenant and server always come from the subject; a payload naming a different tenant or server is rejected
+func ParseCommand(subject string, data []byte) (*ServerCommand, error) {
+	tokens := strings.Split(subject, ".")
+	if len(tokens) != 4 || tokens[0] != "cmd" {
+		return nil, fmt.Errorf("invalid command subject: %s", subject)
+	}
+
+	cmd := &ServerCommand{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, cmd); err != nil {
+			return nil, fmt.Errorf("invalid command payload: %w", err)
+		}
+	}
+
+	tenantID, serverID, action := tokens[1], tokens[2], tokens[3]
+	if (cmd.TenantID != "" && cmd.TenantID != tenantID) || (cmd.ServerID != "" && cmd.ServerID != serverID) {
+		return nil, fmt.Errorf("payload does not match subject %s", subject)
+	}
+	cmd.TenantID = tenantID
+	cmd.ServerID = serverID
+	cmd.Action = action
+
+	switch action {
+	case CommandStart, CommandStop, CommandRestart, CommandBackup:
+	case CommandRCON:
+		if strings.TrimSpace(cmd.Command) == "" {
+			return cmd, fmt.Errorf("rcon action requires a command")
+		}
+	default:
+		return cmd, fmt.Errorf("unknown action: %s", action)
+	}
+
+	return cmd, nil
+}
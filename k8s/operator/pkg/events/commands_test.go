@@ -0,0 +1,157 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		payload string
+		want    ServerCommand
+		wantErr bool
+	}{
+		{"from the subject", "cmd.tenant-1.server-1.start", "", ServerCommand{TenantID: "tenant-1", ServerID: "server-1", Action: CommandStart}, false},
+		{"rcon with payload", "cmd.tenant-1.server-1.rcon", `{"command":"say hi","request_id":"r1"}`,
+			ServerCommand{TenantID: "tenant-1", ServerID: "server-1", Action: CommandRCON, Command: "say hi", RequestID: "r1"}, false},
+		{"payload repeating the subject", "cmd.tenant-1.server-1.stop", `{"tenant_id":"tenant-1","server_id":"server-1"}`,
+			ServerCommand{TenantID: "tenant-1", ServerID: "server-1", Action: CommandStop}, false},
+		{"payload for another tenant", "cmd.tenant-1.server-1.stop", `{"tenant_id":"tenant-2"}`, ServerCommand{}, true},
+		{"payload for another server", "cmd.tenant-1.server-1.stop", `{"server_id":"server-2"}`, ServerCommand{}, true},
+		{"action in the payload is ignored", "cmd.tenant-1.server-1.stop", `{"action":"start"}`, ServerCommand{TenantID: "tenant-1", ServerID: "server-1", Action: CommandStop}, false},
+		{"too few tokens", "cmd.tenant-1.start", "", ServerCommand{}, true},
+		{"other prefix", "events.tenant-1.server-1.start", "", ServerCommand{}, true},
+		{"invalid JSON", "cmd.tenant-1.server-1.start", "{", ServerCommand{}, true},
+		{"unknown action", "cmd.tenant-1.server-1.explode", "", ServerCommand{}, true},
+		{"rcon without command", "cmd.tenant-1.server-1.rcon", `{"command":"  "}`, ServerCommand{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := ParseCommand(tt.subject, []byte(tt.payload))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", cmd)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCommand: %v", err)
+			}
+			if *cmd != tt.want {
+				t.Errorf("got %+v, want %+v", *cmd, tt.want)
+			}
+		})
+	}
+}
+
+// blockingHandler holds commands for server "slow" until release is closed
+type blockingHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *blockingHandler) HandleCommand(ctx context.Context, cmd *ServerCommand) (string, error) {
+	if cmd.ServerID == "slow" {
+		h.started <- struct{}{}
+		select {
+		case <-h.release:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	return cmd.Action + " " + cmd.ServerID, nil
+}
+
+func TestSlowCommandDoesNotBlockOthers(t *testing.T) {
+	srv := runServer(t)
+	publisher := newTestPublisher(t, srv)
+	nc, _ := connect(t, srv)
+
+	handler := &blockingHandler{started: make(chan struct{}, 1), release: make(chan struct{})}
+	subscriber, err := NewCommandSubscriber(publisher, handler)
+	if err != nil {
+		t.Fatalf("NewCommandSubscriber: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = subscriber.Start(ctx) }()
+
+	// Wait for the subscription, then start a command that doesn't finish on its own
+	eventually(t, func() error {
+		_, err := request(nc, "cmd.tenant-2.fast.start", nil)
+		return err
+	})
+	slow := make(chan *CommandReply, 1)
+	go func() {
+		msg, err := nc.Request("cmd.tenant-1.slow.backup", nil, 5*time.Second)
+		if err != nil {
+			t.Errorf("slow request: %v", err)
+			slow <- nil
+			return
+		}
+		reply, _ := decodeReply(msg.Data)
+		slow <- reply
+	}()
+	<-handler.started
+
+	reply, err := request(nc, "cmd.tenant-2.fast.restart", nil)
+	if err != nil {
+		t.Fatalf("a command was held up by another tenant's slow command: %v", err)
+	}
+	if !reply.Success || reply.Output != "restart fast" {
+		t.Errorf("unexpected reply %+v", reply)
+	}
+
+	close(handler.release)
+	if reply := <-slow; reply == nil || !reply.Success || reply.Output != "backup slow" {
+		t.Errorf("unexpected reply to the slow command %+v", reply)
+	}
+}
+
+func TestStartWaitsForRunningCommands(t *testing.T) {
+	srv := runServer(t)
+	publisher := newTestPublisher(t, srv)
+	nc, _ := connect(t, srv)
+
+	handler := &blockingHandler{started: make(chan struct{}, 1), release: make(chan struct{})}
+	subscriber, err := NewCommandSubscriber(publisher, handler)
+	if err != nil {
+		t.Fatalf("NewCommandSubscriber: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- subscriber.Start(ctx) }()
+
+	eventually(t, func() error {
+		_, err := request(nc, "cmd.tenant-1.fast.start", nil)
+		return err
+	})
+	replied := make(chan *CommandReply, 1)
+	go func() {
+		msg, err := nc.Request("cmd.tenant-1.slow.backup", nil, 5*time.Second)
+		if err != nil {
+			replied <- nil
+			return
+		}
+		reply, _ := decodeReply(msg.Data)
+		replied <- reply
+	}()
+	<-handler.started
+
+	// Shutting down cancels the running command, which still replies before Start returns
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	select {
+	case reply := <-replied:
+		if reply == nil || reply.Success || reply.Code != ReplyCodeFailed {
+			t.Errorf("expected the cancelled command to fail, got %+v", reply)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the running command did not reply")
+	}
+}
@@ -0,0 +1,212 @@
+package events
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Default stream settings, matching what the operator has always created
+const (
+	DefaultStreamName     = "MINECRAFT_EVENTS"
+	DefaultStreamMaxAge   = 24 * time.Hour
+	DefaultStreamMaxMsgs  = 100000
+	DefaultStreamReplicas = 1
+)
+
+// defaultStreamSubjects are the api-server subjects captured by the shared stream before any prefix is applied
+var defaultStreamSubjects = []string{"server.*", "sync.*"}
+
+// Subjects captured for operator events in the shared stream
+const (
+	tenantStreamSubject = "tenant.*.server.*.*"
+	legacyStreamSubject = "k8s.*"
+)
+
+// EventPublisherConfig configuration for event publisher
+// The publisher keeps its config so Reconnect dials the same server with the same credentials
+type EventPublisherConfig struct {
+	NATSUrl      string
+	StreamName   string
+	StatusBucket string
+	Enabled      bool
+
+	// TLS: CA bundle to verify the server, plus an optional client certificate for mTLS
+	TLSCAFile   string
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// CredsFile is a chained JWT + NKey seed .creds file (decentralised auth)
+	CredsFile string
+
+	// NKeySeedFile is a plain NKey seed file (used when CredsFile is not set)
+	NKeySeedFile string
+
+	// Stream settings; zero values fall back to the defaults above
+	StreamSubjects []string
+	StreamMaxAge   time.Duration
+	StreamMaxMsgs  int64
+	StreamReplicas int
+
+	// SubjectPrefix is prepended to every published subject, e.g. "prod" -> "prod.tenant.<id>.server.<id>.running"
+	SubjectPrefix string
+
+	// TenantSubjectPrefixes overrides SubjectPrefix for specific tenants (tenantID -> prefix)
+	// Pointing a tenant at a prefix exported from its own NATS account isolates it at the account level
+	TenantSubjectPrefixes map[string]string
+
+	// TenantStreams stores each tenant's events in its own stream (<StreamName>_<tenantID>)
+	// instead of the shared stream, so retention and access can be managed per tenant
+	TenantStreams bool
+
+	// LegacySubjects also publishes events on k8s.<type> for consumers not yet using tenant subjects
+	// On by default for one more release so existing k8s.* consumers keep receiving events after an upgrade
+	LegacySubjects bool
+}
+
+// DefaultConfig returns default configuration
+func DefaultConfig() *EventPublisherConfig {
+	return &EventPublisherConfig{
+		NATSUrl:        "nats://nats.minecraft-system:4222",
+		StreamName:     DefaultStreamName,
+		StatusBucket:   DefaultStatusBucket,
+		Enabled:        true,
+		StreamMaxAge:   DefaultStreamMaxAge,
+		StreamMaxMsgs:  DefaultStreamMaxMsgs,
+		StreamReplicas: DefaultStreamReplicas,
+		LegacySubjects: true,
+	}
+}
+
+// connectOptions builds the NATS connection options, including TLS and credentials
+func (c *EventPublisherConfig) connectOptions() ([]nats.Option, error) {
+	opts := []nats.Option{
+		nats.Name("minecraft-operator"),
+		nats.ReconnectWait(time.Second),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			log.Printf("Operator NATS disconnected: %v", err)
+		}),
+	}
+
+	if c.TLSCAFile != "" {
+		opts = append(opts, nats.RootCAs(c.TLSCAFile))
+	}
+	if c.TLSCertFile != "" || c.TLSKeyFile != "" {
+		if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+			return nil, fmt.Errorf("both TLS cert and key files are required for client certificates")
+		}
+		opts = append(opts, nats.ClientCert(c.TLSCertFile, c.TLSKeyFile))
+	}
+
+	switch {
+	case c.CredsFile != "":
+		opts = append(opts, nats.UserCredentials(c.CredsFile))
+	case c.NKeySeedFile != "":
+		nkeyOpt, err := nats.NkeyOptionFromSeed(c.NKeySeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load NKey seed: %w", err)
+		}
+		opts = append(opts, nkeyOpt)
+	}
+
+	return opts, nil
+}
+
+// streamConfig builds the JetStream stream configuration
+func (c *EventPublisherConfig) streamConfig() *nats.StreamConfig {
+	name := c.StreamName
+	if name == "" {
+		name = DefaultStreamName
+	}
+	maxAge := c.StreamMaxAge
+	if maxAge == 0 {
+		maxAge = DefaultStreamMaxAge
+	}
+	maxMsgs := c.StreamMaxMsgs
+	if maxMsgs == 0 {
+		maxMsgs = DefaultStreamMaxMsgs
+	}
+	replicas := c.StreamReplicas
+	if replicas == 0 {
+		replicas = DefaultStreamReplicas
+	}
+
+	subjects := c.StreamSubjects
+	if len(subjects) == 0 {
+		subjects = c.defaultSubjects()
+	}
+
+	return &nats.StreamConfig{
+		Name:      name,
+		Subjects:  subjects,
+		Retention: nats.LimitsPolicy,
+		MaxAge:    maxAge,
+		MaxMsgs:   maxMsgs,
+		Replicas:  replicas,
+		Storage:   nats.FileStorage,
+	}
+}
+
+// defaultSubjects returns the default stream subjects under every configured prefix
+func (c *EventPublisherConfig) defaultSubjects() []string {
+	prefixes := map[string]bool{c.SubjectPrefix: true}
+	for _, prefix := range c.TenantSubjectPrefixes {
+		prefixes[prefix] = true
+	}
+
+	base := append([]string{}, defaultStreamSubjects...)
+	if !c.TenantStreams {
+		base = append(base, tenantStreamSubject)
+	}
+	if c.LegacySubjects {
+		base = append(base, legacyStreamSubject)
+	}
+
+	var subjects []string
+	for prefix := range prefixes {
+		for _, subject := range base {
+			subjects = append(subjects, withPrefix(prefix, subject))
+		}
+	}
+	sort.Strings(subjects)
+	return subjects
+}
+
+// subjectPrefix returns the subject prefix for a tenant
+func (c *EventPublisherConfig) subjectPrefix(tenantID string) string {
+	if prefix, ok := c.TenantSubjectPrefixes[tenantID]; ok {
+		return prefix
+	}
+	return c.SubjectPrefix
+}
+
+// withPrefix joins a prefix and subject with a dot, ignoring an empty prefix
+func withPrefix(prefix, subject string) string {
+	if prefix == "" {
+		return subject
+	}
+	return strings.TrimSuffix(prefix, ".") + "." + subject
+}
+
+// ParseTenantPrefixes parses "tenantA=prefixA,tenantB=prefixB" into a map
+func ParseTenantPrefixes(value string) (map[string]string, error) {
+	prefixes := map[string]string{}
+	if strings.TrimSpace(value) == "" {
+		return prefixes, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		tenantID, prefix, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || tenantID == "" || prefix == "" {
+			return nil, fmt.Errorf("invalid tenant prefix %q, expected tenant=prefix", pair)
+		}
+		prefixes[tenantID] = prefix
+	}
+
+	return prefixes, nil
+}
@@ -0,0 +1,355 @@
+package events
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nkeys"
+)
+
+// testCerts are PEM files for a CA and the server and client certificates it signed
+type testCerts struct {
+	ca, serverCert, serverKey, clientCert, clientKey string
+}
+
+// writeTestCerts creates a CA with a server certificate for 127.0.0.1 and a client certificate
+func writeTestCerts(t *testing.T) testCerts {
+	t.Helper()
+	dir := t.TempDir()
+
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		return key
+	}
+
+	caKey := newKey()
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("failed to parse CA: %v", err)
+	}
+
+	issue := func(name string, serial int64, usage x509.ExtKeyUsage) (string, string) {
+		key := newKey()
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("failed to create %s certificate: %v", name, err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatalf("failed to encode %s key: %v", name, err)
+		}
+		return writePEM(name+".pem", "CERTIFICATE", der), writePEM(name+"-key.pem", "EC PRIVATE KEY", keyDER)
+	}
+
+	certs := testCerts{ca: writePEM("ca.pem", "CERTIFICATE", caDER)}
+	certs.serverCert, certs.serverKey = issue("server", 2, x509.ExtKeyUsageServerAuth)
+	certs.clientCert, certs.clientKey = issue("client", 3, x509.ExtKeyUsageClientAuth)
+	return certs
+}
+
+// runServerWith starts an embedded JetStream server after configure has adjusted its options
+func runServerWith(t *testing.T, configure func(opts *server.Options)) *server.Server {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	configure(&opts)
+	srv := natsserver.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+// expectPublish checks that publisher is connected and its events reach the stream
+func expectPublish(t *testing.T, publisher *EventPublisher) {
+	t.Helper()
+	if !publisher.IsConnected() {
+		t.Fatal("publisher not connected")
+	}
+	if err := publisher.PublishServerRunning("server-1", "tenant-1", "minecraft", "", 25565, 1, 1); err != nil {
+		t.Fatalf("PublishServerRunning: %v", err)
+	}
+	_, js, _ := publisher.current()
+	if _, err := js.GetLastMsg(DefaultStreamName, "tenant.tenant-1.server.server-1.running"); err != nil {
+		t.Errorf("event not stored in stream: %v", err)
+	}
+}
+
+func TestConnectWithMutualTLS(t *testing.T) {
+	certs := writeTestCerts(t)
+	srv := runServerWith(t, func(opts *server.Options) {
+		tlsConfig, err := server.GenTLSConfig(&server.TLSConfigOpts{
+			CertFile: certs.serverCert,
+			KeyFile:  certs.serverKey,
+			CaFile:   certs.ca,
+			Verify:   true,
+		})
+		if err != nil {
+			t.Fatalf("GenTLSConfig: %v", err)
+		}
+		opts.TLSConfig = tlsConfig
+		opts.TLSVerify = true
+	})
+
+	config := DefaultConfig()
+	config.NATSUrl = srv.ClientURL()
+	config.TLSCAFile = certs.ca
+	config.TLSCertFile = certs.clientCert
+	config.TLSKeyFile = certs.clientKey
+	publisher, err := NewEventPublisher(config)
+	if err != nil {
+		t.Fatalf("NewEventPublisher: %v", err)
+	}
+	defer publisher.Close()
+	expectPublish(t, publisher)
+
+	// The connection is replaced with the same TLS settings
+	old, _, _ := publisher.current()
+	old.Close()
+	eventually(t, func() error {
+		if conn, _, _ := publisher.current(); conn == old || !publisher.IsConnected() {
+			return errNotReconnected
+		}
+		return nil
+	})
+	expectPublish(t, publisher)
+
+	// The server requires a client certificate
+	noClientCert := *config
+	noClientCert.TLSCertFile, noClientCert.TLSKeyFile = "", ""
+	if publisher, err := NewEventPublisher(&noClientCert); err == nil {
+		publisher.Close()
+		t.Error("expected a connection without a client certificate to fail")
+	}
+
+	// Half a client certificate is a configuration error
+	noKey := *config
+	noKey.TLSKeyFile = ""
+	if _, err := NewEventPublisher(&noKey); err == nil {
+		t.Error("expected a client certificate without a key to be rejected")
+	}
+}
+
+func TestConnectWithNKey(t *testing.T) {
+	user, err := nkeys.CreateUser()
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	publicKey, _ := user.PublicKey()
+	seed, _ := user.Seed()
+	seedFile := filepath.Join(t.TempDir(), "operator.nk")
+	if err := os.WriteFile(seedFile, seed, 0o600); err != nil {
+		t.Fatalf("failed to write seed: %v", err)
+	}
+
+	srv := runServerWith(t, func(opts *server.Options) {
+		opts.Nkeys = []*server.NkeyUser{{Nkey: publicKey}}
+	})
+
+	config := DefaultConfig()
+	config.NATSUrl = srv.ClientURL()
+	config.NKeySeedFile = seedFile
+	publisher, err := NewEventPublisher(config)
+	if err != nil {
+		t.Fatalf("NewEventPublisher: %v", err)
+	}
+	defer publisher.Close()
+	expectPublish(t, publisher)
+
+	anonymous := *config
+	anonymous.NKeySeedFile = ""
+	if publisher, err := NewEventPublisher(&anonymous); err == nil {
+		publisher.Close()
+		t.Error("expected a connection without the NKey to fail")
+	}
+}
+
+func TestConnectWithCredsFile(t *testing.T) {
+	// Decentralised auth: an operator signs the account, the account signs the user in the .creds file
+	operator, _ := nkeys.CreateOperator()
+	operatorKey, _ := operator.PublicKey()
+	operatorJWT, err := jwt.NewOperatorClaims(operatorKey).Encode(operator)
+	if err != nil {
+		t.Fatalf("failed to encode operator: %v", err)
+	}
+	operatorClaims, err := jwt.DecodeOperatorClaims(operatorJWT)
+	if err != nil {
+		t.Fatalf("failed to decode operator: %v", err)
+	}
+
+	// JetStream needs a system account in operator mode
+	system, _ := nkeys.CreateAccount()
+	systemKey, _ := system.PublicKey()
+	systemJWT, err := jwt.NewAccountClaims(systemKey).Encode(operator)
+	if err != nil {
+		t.Fatalf("failed to encode system account: %v", err)
+	}
+
+	account, _ := nkeys.CreateAccount()
+	accountKey, _ := account.PublicKey()
+	accountClaims := jwt.NewAccountClaims(accountKey)
+	accountClaims.Limits.JetStreamLimits = jwt.JetStreamLimits{MemoryStorage: -1, DiskStorage: -1, Streams: -1, Consumer: -1}
+	accountJWT, err := accountClaims.Encode(operator)
+	if err != nil {
+		t.Fatalf("failed to encode account: %v", err)
+	}
+
+	user, _ := nkeys.CreateUser()
+	userKey, _ := user.PublicKey()
+	userSeed, _ := user.Seed()
+	userJWT, err := jwt.NewUserClaims(userKey).Encode(account)
+	if err != nil {
+		t.Fatalf("failed to encode user: %v", err)
+	}
+	creds, err := jwt.FormatUserConfig(userJWT, userSeed)
+	if err != nil {
+		t.Fatalf("failed to format creds: %v", err)
+	}
+	credsFile := filepath.Join(t.TempDir(), "operator.creds")
+	if err := os.WriteFile(credsFile, creds, 0o600); err != nil {
+		t.Fatalf("failed to write creds: %v", err)
+	}
+
+	srv := runServerWith(t, func(opts *server.Options) {
+		resolver := &server.MemAccResolver{}
+		if err := resolver.Store(accountKey, accountJWT); err != nil {
+			t.Fatalf("failed to store account: %v", err)
+		}
+		if err := resolver.Store(systemKey, systemJWT); err != nil {
+			t.Fatalf("failed to store system account: %v", err)
+		}
+		opts.TrustedOperators = []*jwt.OperatorClaims{operatorClaims}
+		opts.SystemAccount = systemKey
+		opts.AccountResolver = resolver
+	})
+
+	config := DefaultConfig()
+	config.NATSUrl = srv.ClientURL()
+	config.CredsFile = credsFile
+	publisher, err := NewEventPublisher(config)
+	if err != nil {
+		t.Fatalf("NewEventPublisher: %v", err)
+	}
+	defer publisher.Close()
+	expectPublish(t, publisher)
+
+	anonymous := *config
+	anonymous.CredsFile = ""
+	if publisher, err := NewEventPublisher(&anonymous); err == nil {
+		publisher.Close()
+		t.Error("expected a connection without credentials to fail")
+	}
+}
+
+func TestStreamConfiguration(t *testing.T) {
+	srv := runServer(t)
+	_, js := connect(t, srv)
+
+	config := DefaultConfig()
+	config.NATSUrl = srv.ClientURL()
+	config.StreamName = "OPERATOR_EVENTS"
+	config.StreamSubjects = []string{"tenant.*.server.*.*", "audit.>"}
+	config.StreamMaxAge = 2 * time.Hour
+	config.StreamMaxMsgs = 500
+	publisher, err := NewEventPublisher(config)
+	if err != nil {
+		t.Fatalf("NewEventPublisher: %v", err)
+	}
+	defer publisher.Close()
+
+	info, err := js.StreamInfo("OPERATOR_EVENTS")
+	if err != nil {
+		t.Fatalf("stream not created: %v", err)
+	}
+	if !reflect.DeepEqual(info.Config.Subjects, config.StreamSubjects) {
+		t.Errorf("expected subjects %v, got %v", config.StreamSubjects, info.Config.Subjects)
+	}
+	if info.Config.MaxAge != 2*time.Hour || info.Config.MaxMsgs != 500 || info.Config.Replicas != DefaultStreamReplicas {
+		t.Errorf("unexpected limits: max age %s, max msgs %d, replicas %d", info.Config.MaxAge, info.Config.MaxMsgs, info.Config.Replicas)
+	}
+	if _, err := js.StreamInfo(DefaultStreamName); err == nil {
+		t.Errorf("default stream %s created alongside the configured one", DefaultStreamName)
+	}
+
+	// An existing stream is brought in line with the configuration on the next connect
+	publisher.Close()
+	config.StreamMaxAge = time.Hour
+	config.StreamSubjects = []string{"tenant.*.server.*.*"}
+	publisher, err = NewEventPublisher(config)
+	if err != nil {
+		t.Fatalf("NewEventPublisher: %v", err)
+	}
+	defer publisher.Close()
+	info, err = js.StreamInfo("OPERATOR_EVENTS")
+	if err != nil {
+		t.Fatalf("StreamInfo: %v", err)
+	}
+	if !reflect.DeepEqual(info.Config.Subjects, config.StreamSubjects) || info.Config.MaxAge != time.Hour {
+		t.Errorf("stream not updated: subjects %v, max age %s", info.Config.Subjects, info.Config.MaxAge)
+	}
+}
+
+func TestDefaultStreamSubjects(t *testing.T) {
+	srv := runServer(t)
+	publisher := newTestPublisher(t, srv)
+	_, js := connect(t, srv)
+
+	info, err := js.StreamInfo(DefaultStreamName)
+	if err != nil {
+		t.Fatalf("stream not created: %v", err)
+	}
+	expected := []string{"k8s.*", "server.*", "sync.*", "tenant.*.server.*.*"}
+	if !reflect.DeepEqual(info.Config.Subjects, expected) {
+		t.Errorf("expected subjects %v, got %v", expected, info.Config.Subjects)
+	}
+	if info.Config.MaxAge != DefaultStreamMaxAge || info.Config.MaxMsgs != DefaultStreamMaxMsgs {
+		t.Errorf("unexpected limits: max age %s, max msgs %d", info.Config.MaxAge, info.Config.MaxMsgs)
+	}
+	expectPublish(t, publisher)
+}
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
+
+	"minecraft-platform-operator/pkg/metrics"
 )
 
 // K8sStateEvent represents K8s state change to publish
@@ -19,6 +22,7 @@ type K8sStateEvent struct {
 	ResourceName    string    `json:"resource_name"`
 	Phase           string    `json:"phase"`
 	Message         string    `json:"message"`
+	Reason          string    `json:"reason,omitempty"`
 	ExternalIP      string    `json:"external_ip,omitempty"`
 	ExternalPort    int       `json:"external_port,omitempty"`
 	PlayerCount     int       `json:"player_count,omitempty"`
@@ -27,28 +31,39 @@ type K8sStateEvent struct {
 	Timestamp       time.Time `json:"timestamp"`
 }
 
+// Reason codes attached to error events so consumers can react without parsing messages
+const (
+	ReasonImagePullBackOff   = "ImagePullBackOff"
+	ReasonCrashLoopBackOff   = "CrashLoopBackOff"
+	ReasonPVCPending         = "PVCPending"
+	ReasonRCONAuthFailed     = "RCONAuthFailed"
+	ReasonQuotaExceeded      = "QuotaExceeded"
+	ReasonReconcileFailed    = "ReconcileFailed"
+	ReasonTemplateNotFound   = "TemplateNotFound"
+	ReasonModpackFailed      = "ModpackFailed"
+	ReasonImageResolveFailed = "ImageResolveFailed"
+)
+
 // EventPublisher publishes events to NATS
 type EventPublisher struct {
-	conn    *nats.Conn
-	js      nats.JetStreamContext
+	config  *EventPublisherConfig
 	natsURL string
 	enabled bool
-}
 
-// EventPublisherConfig configuration for event publisher
-type EventPublisherConfig struct {
-	NATSUrl    string
-	StreamName string
-	Enabled    bool
-}
+	// connMu guards the connection, which Reconnect replaces while events are published
+	connMu   sync.RWMutex
+	conn     *nats.Conn
+	js       nats.JetStreamContext
+	statusKV nats.KeyValue
+	replaced chan struct{}
+	closed   bool
 
-// DefaultConfig returns default configuration
-func DefaultConfig() *EventPublisherConfig {
-	return &EventPublisherConfig{
-		NATSUrl:    "nats://nats.minecraft-system:4222",
-		StreamName: "MINECRAFT_EVENTS",
-		Enabled:    true,
-	}
+	// reconnectMu serialises Reconnect
+	reconnectMu sync.Mutex
+
+	// tenantStreams tracks which per-tenant streams have been ensured
+	mu            sync.Mutex
+	tenantStreams map[string]bool
 }
 
 // NewEventPublisher creates a new event publisher
@@ -59,57 +74,163 @@ func NewEventPublisher(config *EventPublisherConfig) (*EventPublisher, error) {
 
 	if !config.Enabled {
 		log.Println("EventPublisher disabled, events will not be published")
-		return &EventPublisher{enabled: false, natsURL: config.NATSUrl}, nil
+		return &EventPublisher{enabled: false, config: config, natsURL: config.NATSUrl}, nil
 	}
 
-	// Connect to NATS
-	conn, err := nats.Connect(config.NATSUrl,
-		nats.Name("minecraft-operator"),
-		nats.ReconnectWait(time.Second),
-		nats.MaxReconnects(-1),
-		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
-			log.Printf("Operator NATS disconnected: %v", err)
-		}),
+	ep := &EventPublisher{
+		config:   config,
+		natsURL:  config.NATSUrl,
+		enabled:  true,
+		replaced: make(chan struct{}),
+
+		tenantStreams: map[string]bool{},
+	}
+	if err := ep.connect(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("EventPublisher connected to NATS at %s", config.NATSUrl)
+	return ep, nil
+}
+
+// connect opens a new NATS connection, binds the stream and status bucket and makes it the current connection
+// The client reconnects on its own; once it gives up and closes the connection, Reconnect starts a new one
+func (ep *EventPublisher) connect() error {
+	opts, err := ep.config.connectOptions()
+	if err != nil {
+		return err
+	}
+	opts = append(opts,
 		nats.ReconnectHandler(func(nc *nats.Conn) {
 			log.Printf("Operator NATS reconnected to %s", nc.ConnectedUrl())
+			ep.rebind(nc)
+		}),
+		nats.ClosedHandler(func(nc *nats.Conn) {
+			go ep.reconnectAfterClose(nc)
 		}),
 	)
+
+	// Connect to NATS
+	conn, err := nats.Connect(ep.config.NATSUrl, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+		return fmt.Errorf("failed to connect to NATS: %w", err)
 	}
 
 	// Create JetStream context
 	js, err := conn.JetStream()
 	if err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+		return fmt.Errorf("failed to create JetStream context: %w", err)
 	}
 
-	// Create stream if it doesn't exist
-	_, err = js.AddStream(&nats.StreamConfig{
-		Name:      config.StreamName,
-		Subjects:  []string{"server.*", "k8s.*", "sync.*"},
-		Retention: nats.LimitsPolicy,
-		MaxAge:    24 * time.Hour,
-		MaxMsgs:   100000,
-		Storage:   nats.FileStorage,
-	})
-	if err != nil && !errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
-		log.Printf("Warning: Could not create stream: %v (may already exist)", err)
+	statusKV := ep.bind(js)
+
+	ep.mu.Lock()
+	ep.tenantStreams = map[string]bool{}
+	ep.mu.Unlock()
+
+	ep.connMu.Lock()
+	defer ep.connMu.Unlock()
+	if ep.closed {
+		conn.Close()
+		return fmt.Errorf("event publisher is closed")
 	}
+	ep.conn, ep.js, ep.statusKV = conn, js, statusKV
+	close(ep.replaced)
+	ep.replaced = make(chan struct{})
+	return nil
+}
 
-	log.Printf("EventPublisher connected to NATS at %s", config.NATSUrl)
-	return &EventPublisher{
-		conn:    conn,
-		js:      js,
-		natsURL: config.NATSUrl,
-		enabled: true,
-	}, nil
+// bind creates or updates the stream and returns the status KV bucket, or nil when it can't be bound
+// Events still flow if the bucket can't be created
+func (ep *EventPublisher) bind(js nats.JetStreamContext) nats.KeyValue {
+	// Create stream if it doesn't exist, otherwise bring it in line with the config
+	ensureStream(js, ep.config.streamConfig())
+
+	if ep.config.StatusBucket == "" {
+		return nil
+	}
+	statusKV, err := bindStatusBucket(js, ep.config.StatusBucket)
+	if err != nil {
+		log.Printf("Warning: Could not bind status bucket %s: %v", ep.config.StatusBucket, err)
+		return nil
+	}
+	return statusKV
+}
+
+// rebind recreates the stream and bucket after the client reconnected, in case the server lost them
+func (ep *EventPublisher) rebind(nc *nats.Conn) {
+	conn, js, _ := ep.current()
+	if conn != nc {
+		return
+	}
+	statusKV := ep.bind(js)
+
+	ep.mu.Lock()
+	ep.tenantStreams = map[string]bool{}
+	ep.mu.Unlock()
+
+	ep.connMu.Lock()
+	if ep.conn == nc && statusKV != nil {
+		ep.statusKV = statusKV
+	}
+	ep.connMu.Unlock()
+}
+
+// reconnectAfterClose replaces a connection the client gave up on, retrying until it succeeds or Close is called
+func (ep *EventPublisher) reconnectAfterClose(nc *nats.Conn) {
+	for wait := time.Second; ; wait = min(2*wait, time.Minute) {
+		ep.connMu.RLock()
+		done := ep.closed || ep.conn != nc
+		ep.connMu.RUnlock()
+		if done {
+			return
+		}
+		err := ep.Reconnect()
+		if err == nil {
+			return
+		}
+		log.Printf("Warning: Could not reconnect to NATS, retrying in %s: %v", wait, err)
+		time.Sleep(wait)
+	}
+}
+
+// current returns the connection, JetStream context and status bucket in use
+func (ep *EventPublisher) current() (*nats.Conn, nats.JetStreamContext, nats.KeyValue) {
+	ep.connMu.RLock()
+	defer ep.connMu.RUnlock()
+	return ep.conn, ep.js, ep.statusKV
+}
+
+// connection returns the current connection and a channel that is closed once Reconnect replaces it
+func (ep *EventPublisher) connection() (*nats.Conn, <-chan struct{}) {
+	ep.connMu.RLock()
+	defer ep.connMu.RUnlock()
+	return ep.conn, ep.replaced
+}
+
+// ensureStream creates the stream or updates an existing one to match cfg
+// Failures are logged rather than returned so events still flow to an externally managed stream
+func ensureStream(js nats.JetStreamContext, cfg *nats.StreamConfig) {
+	_, err := js.StreamInfo(cfg.Name)
+	switch {
+	case errors.Is(err, nats.ErrStreamNotFound):
+		if _, err := js.AddStream(cfg); err != nil {
+			log.Printf("Warning: Could not create stream %s: %v", cfg.Name, err)
+		}
+	case err != nil:
+		log.Printf("Warning: Could not look up stream %s: %v", cfg.Name, err)
+	default:
+		if _, err := js.UpdateStream(cfg); err != nil {
+			log.Printf("Warning: Could not update stream %s: %v", cfg.Name, err)
+		}
+	}
 }
 
 // PublishStateChange publishes a K8s state change event
 func (ep *EventPublisher) PublishStateChange(event *K8sStateEvent) error {
-	if !ep.enabled || ep.conn == nil {
+	conn, js, _ := ep.current()
+	if !ep.enabled || conn == nil {
 		return nil // Silently skip if disabled
 	}
 
@@ -119,19 +240,39 @@ func (ep *EventPublisher) PublishStateChange(event *K8sStateEvent) error {
 
 	data, err := json.Marshal(event)
 	if err != nil {
+		metrics.EventPublishFailures.WithLabelValues(event.TenantID, event.ServerID, event.Type).Inc()
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
-	subject := fmt.Sprintf("k8s.%s", event.Type)
-	_, err = ep.js.Publish(subject, data)
-	if err != nil {
-		return fmt.Errorf("failed to publish event: %w", err)
+	ep.ensureTenantStream(js, event.TenantID)
+
+	for _, subject := range ep.subjects(event) {
+		if _, err := js.Publish(subject, data); err != nil {
+			metrics.EventPublishFailures.WithLabelValues(event.TenantID, event.ServerID, event.Type).Inc()
+			return fmt.Errorf("failed to publish event on %s: %w", subject, err)
+		}
 	}
 
 	log.Printf("Published state change: %s (server: %s, phase: %s)", event.Type, event.ServerID, event.Phase)
 	return nil
 }
 
+// subjects returns the subjects an event is published on, honouring tenant prefixes
+// Events always go to the tenant-scoped subject, and to the legacy k8s.<type> subject while LegacySubjects is on (the default)
+func (ep *EventPublisher) subjects(event *K8sStateEvent) []string {
+	config := ep.config
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	prefix := config.subjectPrefix(event.TenantID)
+	subjects := []string{withPrefix(prefix, TenantSubject(event.TenantID, event.ServerID, event.Type))}
+	if config.LegacySubjects {
+		subjects = append(subjects, withPrefix(prefix, fmt.Sprintf("k8s.%s", event.Type)))
+	}
+	return subjects
+}
+
 // PublishServerStarting publishes a server starting event
 func (ep *EventPublisher) PublishServerStarting(serverID, tenantID, namespace, resourceName string) error {
 	return ep.PublishStateChange(&K8sStateEvent{
@@ -176,16 +317,18 @@ func (ep *EventPublisher) PublishServerStopped(serverID, tenantID, namespace str
 	})
 }
 
-// PublishServerError publishes a server error event
-func (ep *EventPublisher) PublishServerError(serverID, tenantID, namespace, errorMsg string) error {
+// PublishServerError publishes a server error event with a machine-readable reason code
+func (ep *EventPublisher) PublishServerError(serverID, tenantID, namespace, resourceName, reason, errorMsg string) error {
 	return ep.PublishStateChange(&K8sStateEvent{
-		Type:      "error",
-		ServerID:  serverID,
-		TenantID:  tenantID,
-		Namespace: namespace,
-		Phase:     "Error",
-		Message:   errorMsg,
-		Timestamp: time.Now(),
+		Type:         "error",
+		ServerID:     serverID,
+		TenantID:     tenantID,
+		Namespace:    namespace,
+		ResourceName: resourceName,
+		Phase:        "Error",
+		Message:      errorMsg,
+		Reason:       reason,
+		Timestamp:    time.Now(),
 	})
 }
 
@@ -204,32 +347,46 @@ func (ep *EventPublisher) PublishPlayerCountUpdate(serverID, tenantID string, pl
 
 // Close closes the NATS connection
 func (ep *EventPublisher) Close() {
-	if ep.conn != nil {
-		ep.conn.Close()
+	ep.connMu.Lock()
+	ep.closed = true
+	conn := ep.conn
+	ep.connMu.Unlock()
+
+	if conn != nil {
+		conn.Close()
 		log.Println("EventPublisher connection closed")
 	}
 }
 
 // IsConnected returns true if connected to NATS
 func (ep *EventPublisher) IsConnected() bool {
-	return ep.enabled && ep.conn != nil && ep.conn.IsConnected()
+	conn, _, _ := ep.current()
+	return ep.enabled && conn != nil && conn.IsConnected()
 }
 
-// Reconnect attempts to reconnect to NATS
+// Reconnect replaces a closed NATS connection with a new one using the configuration the publisher was created with
+// A connection that is only disconnected is left to the client, which reconnects on its own
 func (ep *EventPublisher) Reconnect() error {
-	if ep.conn != nil && ep.conn.IsConnected() {
-		return nil // Already connected
+	if !ep.enabled {
+		return nil
 	}
 
-	config := DefaultConfig()
-	newPub, err := NewEventPublisher(config)
-	if err != nil {
-		return err
-	}
+	ep.reconnectMu.Lock()
+	defer ep.reconnectMu.Unlock()
 
-	ep.conn = newPub.conn
-	ep.js = newPub.js
-	ep.enabled = newPub.enabled
+	ep.connMu.RLock()
+	conn, closed := ep.conn, ep.closed
+	ep.connMu.RUnlock()
+	if closed {
+		return fmt.Errorf("event publisher is closed")
+	}
+	if conn != nil && !conn.IsClosed() {
+		return nil
+	}
 
+	if err := ep.connect(); err != nil {
+		return err
+	}
+	log.Printf("EventPublisher reconnected to NATS at %s", ep.natsURL)
 	return nil
 }
@@ -0,0 +1,368 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+)
+
+// runServer starts an embedded JetStream server that is shut down with the test
+func runServer(t *testing.T) *server.Server {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	srv := natsserver.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+// newTestPublisher connects a publisher with the default stream and status bucket to srv
+func newTestPublisher(t *testing.T, srv *server.Server) *EventPublisher {
+	t.Helper()
+	config := DefaultConfig()
+	config.NATSUrl = srv.ClientURL()
+	publisher, err := NewEventPublisher(config)
+	if err != nil {
+		t.Fatalf("NewEventPublisher: %v", err)
+	}
+	t.Cleanup(publisher.Close)
+	return publisher
+}
+
+// connect opens a client connection to srv for the test's side of a round-trip
+func connect(t *testing.T, srv *server.Server) (*nats.Conn, nats.JetStreamContext) {
+	t.Helper()
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	js, err := nc.JetStream()
+	if err != nil {
+		t.Fatalf("JetStream: %v", err)
+	}
+	return nc, js
+}
+
+// errNotReconnected is returned by checks waiting for the publisher to replace its connection
+var errNotReconnected = errors.New("publisher did not reconnect")
+
+// eventually retries check until it succeeds or 5s have passed
+func eventually(t *testing.T, check func() error) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := check()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestPublishStateChange(t *testing.T) {
+	srv := runServer(t)
+	publisher := newTestPublisher(t, srv)
+	_, js := connect(t, srv)
+
+	if err := publisher.PublishServerRunning("server-1", "tenant-1", "minecraft", "10.0.0.1", 25565, 1, 1); err != nil {
+		t.Fatalf("PublishServerRunning: %v", err)
+	}
+
+	msg, err := js.GetLastMsg(DefaultStreamName, "tenant.tenant-1.server.server-1.running")
+	if err != nil {
+		t.Fatalf("event not stored in stream: %v", err)
+	}
+	var event K8sStateEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		t.Fatalf("invalid event: %v", err)
+	}
+	if event.Type != "running" || event.ServerID != "server-1" || event.TenantID != "tenant-1" || event.ExternalPort != 25565 {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	// Legacy subjects are still on by default
+	if _, err := js.GetLastMsg(DefaultStreamName, "k8s.running"); err != nil {
+		t.Errorf("legacy event not stored: %v", err)
+	}
+}
+
+func TestPublishWithoutLegacySubjects(t *testing.T) {
+	srv := runServer(t)
+	config := DefaultConfig()
+	config.NATSUrl = srv.ClientURL()
+	config.LegacySubjects = false
+	publisher, err := NewEventPublisher(config)
+	if err != nil {
+		t.Fatalf("NewEventPublisher: %v", err)
+	}
+	defer publisher.Close()
+	_, js := connect(t, srv)
+
+	if err := publisher.PublishServerStopped("server-1", "tenant-1", "minecraft"); err != nil {
+		t.Fatalf("PublishServerStopped: %v", err)
+	}
+	if _, err := js.GetLastMsg(DefaultStreamName, "tenant.tenant-1.server.server-1.stopped"); err != nil {
+		t.Errorf("event not stored in stream: %v", err)
+	}
+	if _, err := js.GetLastMsg(DefaultStreamName, "k8s.stopped"); !errors.Is(err, nats.ErrMsgNotFound) {
+		t.Errorf("expected no legacy event, got %v", err)
+	}
+}
+
+func TestServerStatusKV(t *testing.T) {
+	srv := runServer(t)
+	publisher := newTestPublisher(t, srv)
+	_, js := connect(t, srv)
+
+	snapshot := &ServerStatusSnapshot{ServerID: "server-1", TenantID: "tenant-1", Phase: "Running", PlayerCount: 3}
+	if err := publisher.PutServerStatus(snapshot); err != nil {
+		t.Fatalf("PutServerStatus: %v", err)
+	}
+
+	kv, err := js.KeyValue(DefaultStatusBucket)
+	if err != nil {
+		t.Fatalf("status bucket not created: %v", err)
+	}
+	entry, err := kv.Get(StatusKey("tenant-1", "server-1"))
+	if err != nil {
+		t.Fatalf("status not stored: %v", err)
+	}
+	var stored ServerStatusSnapshot
+	if err := json.Unmarshal(entry.Value(), &stored); err != nil {
+		t.Fatalf("invalid snapshot: %v", err)
+	}
+	if stored.Phase != "Running" || stored.PlayerCount != 3 || stored.UpdatedAt.IsZero() {
+		t.Errorf("unexpected snapshot %+v", stored)
+	}
+
+	if err := publisher.DeleteServerStatus("tenant-1", "server-1"); err != nil {
+		t.Fatalf("DeleteServerStatus: %v", err)
+	}
+	if _, err := kv.Get(StatusKey("tenant-1", "server-1")); !errors.Is(err, nats.ErrKeyNotFound) {
+		t.Errorf("expected deleted status, got %v", err)
+	}
+	// Deleting again is not an error
+	if err := publisher.DeleteServerStatus("tenant-1", "server-1"); err != nil {
+		t.Errorf("DeleteServerStatus of a missing key: %v", err)
+	}
+}
+
+// ownerHandler accepts commands for servers of tenant-1
+type ownerHandler struct{}
+
+func (ownerHandler) HandleCommand(_ context.Context, cmd *ServerCommand) (string, error) {
+	if cmd.TenantID != "tenant-1" {
+		return "", ErrCommandForbidden
+	}
+	return cmd.Action + " " + cmd.ServerID, nil
+}
+
+// request sends a command and decodes the reply
+func request(nc *nats.Conn, subject string, payload []byte) (*CommandReply, error) {
+	msg, err := nc.Request(subject, payload, time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return decodeReply(msg.Data)
+}
+
+// decodeReply decodes a CommandReply
+func decodeReply(data []byte) (*CommandReply, error) {
+	var reply CommandReply
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// startSubscriber runs a command subscriber until the test ends
+func startSubscriber(t *testing.T, publisher *EventPublisher) {
+	t.Helper()
+	subscriber, err := NewCommandSubscriber(publisher, ownerHandler{})
+	if err != nil {
+		t.Fatalf("NewCommandSubscriber: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- subscriber.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Start: %v", err)
+		}
+	})
+}
+
+func TestCommandRoundTrip(t *testing.T) {
+	srv := runServer(t)
+	publisher := newTestPublisher(t, srv)
+	nc, _ := connect(t, srv)
+	startSubscriber(t, publisher)
+
+	var reply *CommandReply
+	eventually(t, func() (err error) {
+		reply, err = request(nc, "cmd.tenant-1.server-1.start", []byte(`{"request_id":"r1"}`))
+		return err
+	})
+	if !reply.Success || reply.Output != "start server-1" || reply.RequestID != "r1" || reply.Action != CommandStart {
+		t.Errorf("unexpected reply %+v", reply)
+	}
+
+	tests := []struct {
+		name    string
+		subject string
+		payload string
+		code    string
+	}{
+		{"other tenant", "cmd.tenant-2.server-1.stop", "", ReplyCodeForbidden},
+		{"unknown action", "cmd.tenant-1.server-1.explode", "", ReplyCodeBadRequest},
+		{"rcon without command", "cmd.tenant-1.server-1.rcon", "", ReplyCodeBadRequest},
+		{"payload for another server", "cmd.tenant-1.server-1.stop", `{"server_id":"server-2"}`, ReplyCodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, err := request(nc, tt.subject, []byte(tt.payload))
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if reply.Success || reply.Code != tt.code {
+				t.Errorf("expected %s, got %+v", tt.code, reply)
+			}
+		})
+	}
+}
+
+func TestReconnectAfterClose(t *testing.T) {
+	srv := runServer(t)
+	publisher := newTestPublisher(t, srv)
+	nc, js := connect(t, srv)
+	startSubscriber(t, publisher)
+
+	eventually(t, func() error {
+		_, err := request(nc, "cmd.tenant-1.server-1.start", nil)
+		return err
+	})
+
+	// A connection the client gave up on is replaced, and the subscriber follows it
+	old, _, _ := publisher.current()
+	old.Close()
+	eventually(t, func() error {
+		if conn, _, _ := publisher.current(); conn == old || !publisher.IsConnected() {
+			return errNotReconnected
+		}
+		return nil
+	})
+	eventually(t, func() error {
+		_, err := request(nc, "cmd.tenant-1.server-1.stop", nil)
+		return err
+	})
+
+	if err := publisher.PublishServerStopped("server-1", "tenant-1", "minecraft"); err != nil {
+		t.Fatalf("publish after reconnect: %v", err)
+	}
+	if _, err := js.GetLastMsg(DefaultStreamName, "tenant.tenant-1.server.server-1.stopped"); err != nil {
+		t.Errorf("event after reconnect not stored: %v", err)
+	}
+	if err := publisher.PutServerStatus(&ServerStatusSnapshot{ServerID: "server-1", TenantID: "tenant-1"}); err != nil {
+		t.Errorf("status after reconnect: %v", err)
+	}
+}
+
+func TestCloseStopsReconnecting(t *testing.T) {
+	srv := runServer(t)
+	publisher := newTestPublisher(t, srv)
+
+	publisher.Close()
+	if err := publisher.Reconnect(); err == nil {
+		t.Error("expected Reconnect to fail after Close")
+	}
+	if publisher.IsConnected() {
+		t.Error("publisher still connected after Close")
+	}
+}
+
+func TestPublishWithSubjectPrefix(t *testing.T) {
+	srv := runServer(t)
+	config := DefaultConfig()
+	config.NATSUrl = srv.ClientURL()
+	config.SubjectPrefix = "prod"
+	publisher, err := NewEventPublisher(config)
+	if err != nil {
+		t.Fatalf("NewEventPublisher: %v", err)
+	}
+	defer publisher.Close()
+	_, js := connect(t, srv)
+
+	if err := publisher.PublishServerStopped("server-1", "tenant-1", "minecraft"); err != nil {
+		t.Fatalf("PublishServerStopped: %v", err)
+	}
+	for _, subject := range []string{"prod.tenant.tenant-1.server.server-1.stopped", "prod.k8s.stopped"} {
+		if _, err := js.GetLastMsg(DefaultStreamName, subject); err != nil {
+			t.Errorf("event not stored on %s: %v", subject, err)
+		}
+	}
+	if _, err := js.GetLastMsg(DefaultStreamName, "tenant.tenant-1.server.server-1.stopped"); !errors.Is(err, nats.ErrMsgNotFound) {
+		t.Errorf("expected no unprefixed event, got %v", err)
+	}
+}
+
+func TestPublishToTenantStreams(t *testing.T) {
+	srv := runServer(t)
+	config := DefaultConfig()
+	config.NATSUrl = srv.ClientURL()
+	config.TenantStreams = true
+	publisher, err := NewEventPublisher(config)
+	if err != nil {
+		t.Fatalf("NewEventPublisher: %v", err)
+	}
+	defer publisher.Close()
+	_, js := connect(t, srv)
+
+	for _, tenantID := range []string{"tenant-1", "acme corp/eu"} {
+		if err := publisher.PublishServerStopped("server-1", tenantID, "minecraft"); err != nil {
+			t.Fatalf("PublishServerStopped for %q: %v", tenantID, err)
+		}
+	}
+
+	// Each tenant's events are kept in its own stream, which captures nothing of other tenants
+	info, err := js.StreamInfo("MINECRAFT_EVENTS_tenant-1")
+	if err != nil {
+		t.Fatalf("tenant stream not created: %v", err)
+	}
+	if !reflect.DeepEqual(info.Config.Subjects, []string{"tenant.tenant-1.>"}) {
+		t.Errorf("unexpected tenant stream subjects %v", info.Config.Subjects)
+	}
+	if info.State.Msgs != 1 {
+		t.Errorf("expected 1 event in the tenant stream, got %d", info.State.Msgs)
+	}
+	if _, err := js.GetLastMsg("MINECRAFT_EVENTS_tenant-1", "tenant.tenant-1.server.server-1.stopped"); err != nil {
+		t.Errorf("event not stored in tenant stream: %v", err)
+	}
+	if _, err := js.GetLastMsg("MINECRAFT_EVENTS_acme_corp_eu", "tenant.acme_corp/eu.server.server-1.stopped"); err != nil {
+		t.Errorf("event not stored in sanitised tenant stream: %v", err)
+	}
+
+	// The shared stream no longer captures tenant subjects
+	shared, err := js.StreamInfo(DefaultStreamName)
+	if err != nil {
+		t.Fatalf("shared stream not created: %v", err)
+	}
+	for _, subject := range shared.Config.Subjects {
+		if subject == tenantStreamSubject {
+			t.Errorf("shared stream still captures %s", subject)
+		}
+	}
+}
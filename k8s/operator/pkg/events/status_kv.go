@@ -0,0 +1,108 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// DefaultStatusBucket is the JetStream KV bucket holding the latest status of every server
+const DefaultStatusBucket = "MINECRAFT_SERVER_STATUS"
+
+// ServerStatusSnapshot is the latest known state of a server, stored under <tenantID>.<serverID>
+// Consumers read it in O(1) or watch <tenantID>.> instead of replaying the event stream
+type ServerStatusSnapshot struct {
+	ServerID        string                 `json:"server_id"`
+	TenantID        string                 `json:"tenant_id"`
+	Namespace       string                 `json:"namespace"`
+	ResourceName    string                 `json:"resource_name"`
+	Phase           string                 `json:"phase"`
+	Message         string                 `json:"message"`
+	ExternalIP      string                 `json:"external_ip,omitempty"`
+	ExternalPort    int                    `json:"external_port,omitempty"`
+	Hostname        string                 `json:"hostname,omitempty"`
+	Sleeping        bool                   `json:"sleeping,omitempty"`
+	BedrockEndpoint string                 `json:"bedrock_endpoint,omitempty"`
+	PlayerCount     int                    `json:"player_count"`
+	MaxPlayers      int                    `json:"max_players"`
+	Players         []string               `json:"players,omitempty"`
+	Version         string                 `json:"version,omitempty"`
+	ResourceUsage   *ResourceUsageSnapshot `json:"resource_usage,omitempty"`
+	LastBackup      *time.Time             `json:"last_backup,omitempty"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+// ResourceUsageSnapshot is the resource usage part of a ServerStatusSnapshot
+type ResourceUsageSnapshot struct {
+	CPU     string `json:"cpu,omitempty"`
+	Memory  string `json:"memory,omitempty"`
+	Storage string `json:"storage,omitempty"`
+	RxBytes int64  `json:"rx_bytes,omitempty"`
+	TxBytes int64  `json:"tx_bytes,omitempty"`
+}
+
+// StatusKey returns the KV key for a server
+func StatusKey(tenantID, serverID string) string {
+	return fmt.Sprintf("%s.%s", SubjectToken(tenantID), SubjectToken(serverID))
+}
+
+// bindStatusBucket returns the status KV bucket, creating it if it doesn't exist
+// History is 1 because only the latest status matters; the event stream keeps transitions
+func bindStatusBucket(js nats.JetStreamContext, bucket string) (nats.KeyValue, error) {
+	kv, err := js.KeyValue(bucket)
+	if err == nil {
+		return kv, nil
+	}
+	if !errors.Is(err, nats.ErrBucketNotFound) {
+		return nil, err
+	}
+
+	return js.CreateKeyValue(&nats.KeyValueConfig{
+		Bucket:      bucket,
+		Description: "Latest status of each Minecraft server",
+		History:     1,
+		Storage:     nats.FileStorage,
+	})
+}
+
+// PutServerStatus writes the latest status snapshot for a server
+func (ep *EventPublisher) PutServerStatus(snapshot *ServerStatusSnapshot) error {
+	_, _, statusKV := ep.current()
+	if !ep.enabled || statusKV == nil {
+		return nil // Silently skip if disabled or bucket unavailable
+	}
+
+	if snapshot.UpdatedAt.IsZero() {
+		snapshot.UpdatedAt = time.Now()
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status snapshot: %w", err)
+	}
+
+	if _, err := statusKV.Put(StatusKey(snapshot.TenantID, snapshot.ServerID), data); err != nil {
+		return fmt.Errorf("failed to put status snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteServerStatus removes a server's status snapshot, e.g. when the server is deleted
+func (ep *EventPublisher) DeleteServerStatus(tenantID, serverID string) error {
+	_, _, statusKV := ep.current()
+	if !ep.enabled || statusKV == nil {
+		return nil
+	}
+
+	if err := statusKV.Delete(StatusKey(tenantID, serverID)); err != nil && !errors.Is(err, nats.ErrKeyNotFound) {
+		return fmt.Errorf("failed to delete status snapshot: %w", err)
+	}
+
+	log.Printf("Deleted status snapshot (tenant: %s, server: %s)", tenantID, serverID)
+	return nil
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Tenant-scoped subjects
+//
+// Operator events are published on tenant.<tenantID>.server.<serverID>.<type> so that a
+// consumer can be granted tenant.<tenantID>.> and see nothing of other tenants. With
+// TenantStreams enabled each tenant's events are also stored in their own stream.
+
+// TenantSubject returns the subject for an event of eventType on a tenant's server
+func TenantSubject(tenantID, serverID, eventType string) string {
+	return fmt.Sprintf("tenant.%s.server.%s.%s", SubjectToken(tenantID), SubjectToken(serverID), eventType)
+}
+
+// TenantWildcard returns the subject matching every event of a tenant
+func TenantWildcard(tenantID string) string {
+	return fmt.Sprintf("tenant.%s.>", SubjectToken(tenantID))
+}
+
+// SubjectToken makes an ID safe to use as a single subject token
+// Dots, wildcards and whitespace would otherwise change the subject hierarchy
+func SubjectToken(id string) string {
+	if id == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+		return r
+	}, id)
+}
+
+// tenantStreamName returns the per-tenant stream name, e.g. MINECRAFT_EVENTS_<tenant>
+// Stream names can't contain dots, wildcards, path separators or whitespace, so those become underscores
+func tenantStreamName(base, tenantID string) string {
+	if tenantID == "" {
+		tenantID = "_"
+	}
+	return fmt.Sprintf("%s_%s", base, strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`.*>/\`, r) || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, tenantID))
+}
+
+// tenantStreamConfig builds the stream holding one tenant's events
+// Retention mirrors the shared stream so switching modes doesn't change what is kept
+func (c *EventPublisherConfig) tenantStreamConfig(tenantID string) *nats.StreamConfig {
+	cfg := c.streamConfig()
+	cfg.Name = tenantStreamName(cfg.Name, tenantID)
+	cfg.Subjects = []string{withPrefix(c.subjectPrefix(tenantID), TenantWildcard(tenantID))}
+	return cfg
+}
+
+// ensureTenantStream creates the tenant's stream the first time the tenant publishes
+func (ep *EventPublisher) ensureTenantStream(js nats.JetStreamContext, tenantID string) {
+	if ep.config == nil || !ep.config.TenantStreams {
+		return
+	}
+
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	if ep.tenantStreams[tenantID] {
+		return
+	}
+	ensureStream(js, ep.config.tenantStreamConfig(tenantID))
+	ep.tenantStreams[tenantID] = true
+}
@@ -0,0 +1,63 @@
+package events
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTenantStreamName(t *testing.T) {
+	tests := []struct {
+		tenantID string
+		expected string
+	}{
+		{"tenant-1", "MINECRAFT_EVENTS_tenant-1"},
+		{"acme.eu", "MINECRAFT_EVENTS_acme_eu"},
+		{"a*b>c", "MINECRAFT_EVENTS_a_b_c"},
+		{`org/team\eu`, "MINECRAFT_EVENTS_org_team_eu"},
+		{"acme corp\tteam\n", "MINECRAFT_EVENTS_acme_corp_team_"},
+		{"no break", "MINECRAFT_EVENTS_no_break"},
+		{"bell\a", "MINECRAFT_EVENTS_bell_"},
+		{"", "MINECRAFT_EVENTS__"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tenantID, func(t *testing.T) {
+			if name := tenantStreamName(DefaultStreamName, tt.tenantID); name != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, name)
+			}
+		})
+	}
+}
+
+func TestSubjectsWithPrefixes(t *testing.T) {
+	config := DefaultConfig()
+	config.SubjectPrefix = "prod"
+	config.TenantSubjectPrefixes = map[string]string{"tenant-2": "acct.tenant-2."}
+	publisher := &EventPublisher{config: config}
+
+	tests := []struct {
+		tenantID string
+		expected []string
+	}{
+		{"tenant-1", []string{"prod.tenant.tenant-1.server.server-1.running", "prod.k8s.running"}},
+		{"tenant-2", []string{"acct.tenant-2.tenant.tenant-2.server.server-1.running", "acct.tenant-2.k8s.running"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tenantID, func(t *testing.T) {
+			subjects := publisher.subjects(&K8sStateEvent{Type: "running", TenantID: tt.tenantID, ServerID: "server-1"})
+			if !reflect.DeepEqual(subjects, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, subjects)
+			}
+		})
+	}
+
+	// The shared stream captures every prefix
+	expected := []string{
+		"acct.tenant-2.k8s.*", "acct.tenant-2.server.*", "acct.tenant-2.sync.*", "acct.tenant-2.tenant.*.server.*.*",
+		"prod.k8s.*", "prod.server.*", "prod.sync.*", "prod.tenant.*.server.*.*",
+	}
+	subjects := config.streamConfig().Subjects
+	if !sort.StringsAreSorted(subjects) || !reflect.DeepEqual(subjects, expected) {
+		t.Errorf("expected stream subjects %v, got %v", expected, subjects)
+	}
+}
@@ -0,0 +1,154 @@
+package gamemetrics
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"minecraft-platform-operator/pkg/rcon"
+)
+
+var (
+	upDesc = prometheus.NewDesc("minecraft_game_rcon_up",
+		"Whether the last RCON scrape of the server succeeded", nil, nil)
+	tpsDesc = prometheus.NewDesc("minecraft_game_tps",
+		"Ticks per second averaged over window", []string{"window"}, nil)
+	tickDurationDesc = prometheus.NewDesc("minecraft_game_tick_duration_seconds",
+		"Average duration of a server tick", nil, nil)
+	chunksDesc = prometheus.NewDesc("minecraft_game_loaded_chunks",
+		"Number of loaded chunks across all worlds", nil, nil)
+	entitiesDesc = prometheus.NewDesc("minecraft_game_entities",
+		"Number of loaded entities across all worlds", nil, nil)
+	scrapeDurationDesc = prometheus.NewDesc("minecraft_game_scrape_duration_seconds",
+		"Duration of the RCON scrape", nil, nil)
+)
+
+// Collector reads in-game metrics over RCON on every Prometheus scrape
+type Collector struct {
+	address  string
+	password string
+	timeout  time.Duration
+	commands Commands
+
+	// Scrapes are serialised so concurrent Prometheus replicas don't open parallel RCON sessions
+	mu sync.Mutex
+}
+
+// NewCollector creates a Collector for a server of serverType reachable at address
+func NewCollector(address, password, serverType string, timeout time.Duration) *Collector {
+	return &Collector{
+		address:  address,
+		password: password,
+		timeout:  timeout,
+		commands: CommandsFor(serverType),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	ch <- tpsDesc
+	ch <- tickDurationDesc
+	ch <- chunksDesc
+	ch <- entitiesDesc
+	ch <- scrapeDurationDesc
+}
+
+// Collect implements prometheus.Collector
+// Metrics that can't be read or parsed are left out of the scrape rather than reported as zero
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	start := time.Now()
+	defer func() {
+		ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, time.Since(start).Seconds())
+	}()
+
+	client, err := rcon.Connect(c.address, c.password, c.timeout)
+	if err != nil {
+		log.Printf("RCON connection failed: %v", err)
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0)
+		return
+	}
+	defer client.Close()
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1)
+
+	if c.commands.TPS != "" {
+		c.collectTPS(client, ch)
+	}
+
+	if c.commands.MSPT != "" {
+		if response, ok := execute(client, c.commands.MSPT); ok {
+			if mspt, err := ParsePaperMSPT(response); err != nil {
+				log.Printf("Failed to parse mspt: %v", err)
+			} else {
+				ch <- prometheus.MustNewConstMetric(tickDurationDesc, prometheus.GaugeValue, mspt/1000)
+			}
+		}
+	}
+
+	if c.commands.Chunks != "" {
+		if response, ok := execute(client, c.commands.Chunks); ok {
+			if chunks, err := ParsePaperChunks(response); err != nil {
+				log.Printf("Failed to parse chunk info: %v", err)
+			} else {
+				ch <- prometheus.MustNewConstMetric(chunksDesc, prometheus.GaugeValue, float64(chunks))
+			}
+		}
+	}
+
+	if c.commands.Entities != "" {
+		if response, ok := execute(client, c.commands.Entities); ok {
+			if entities, err := ParseEntityCount(response); err != nil {
+				log.Printf("Failed to parse entity count: %v", err)
+			} else {
+				ch <- prometheus.MustNewConstMetric(entitiesDesc, prometheus.GaugeValue, float64(entities))
+			}
+		}
+	}
+}
+
+// collectTPS reads TPS, and the tick time on Forge-family servers, whose tps command reports both
+func (c *Collector) collectTPS(client *rcon.Client, ch chan<- prometheus.Metric) {
+	response, ok := execute(client, c.commands.TPS)
+	if !ok {
+		return
+	}
+
+	if c.commands.TPS == "tps" {
+		tps, err := ParsePaperTPS(response)
+		if err != nil {
+			log.Printf("Failed to parse tps: %v", err)
+			return
+		}
+		for i, window := range []string{"1m", "5m", "15m"} {
+			ch <- prometheus.MustNewConstMetric(tpsDesc, prometheus.GaugeValue, tps[i], window)
+		}
+		return
+	}
+
+	parse := ParseForgeTPS
+	if c.commands.TPS == "neoforge tps" {
+		parse = ParseNeoForgeTPS
+	}
+	tps, mspt, err := parse(response)
+	if err != nil {
+		log.Printf("Failed to parse tps: %v", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(tpsDesc, prometheus.GaugeValue, tps, "overall")
+	ch <- prometheus.MustNewConstMetric(tickDurationDesc, prometheus.GaugeValue, mspt/1000)
+}
+
+// execute runs command and logs failures
+func execute(client *rcon.Client, command string) (string, bool) {
+	response, err := client.Execute(command)
+	if err != nil {
+		log.Printf("RCON command %q failed: %v", command, err)
+		return "", false
+	}
+	return response, true
+}
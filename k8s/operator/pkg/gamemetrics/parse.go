@@ -0,0 +1,156 @@
+package gamemetrics
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Commands are the RCON commands used to read in-game metrics for a server type
+// Empty commands are not supported by the server software and are skipped
+type Commands struct {
+	// TPS reports ticks per second (and tick time on Forge-family servers)
+	TPS string
+
+	// MSPT reports the average tick duration in milliseconds
+	MSPT string
+
+	// Chunks reports the number of loaded chunks
+	Chunks string
+
+	// Entities reports the number of loaded entities
+	Entities string
+}
+
+// entitiesCommand counts entities in every dimension; supported by every server since 1.13
+const entitiesCommand = "execute if entity @e"
+
+// CommandsFor returns the commands supported by serverType
+func CommandsFor(serverType string) Commands {
+	switch strings.ToUpper(serverType) {
+	case "PAPER", "PURPUR":
+		return Commands{TPS: "tps", MSPT: "mspt", Chunks: "paper chunkinfo *", Entities: entitiesCommand}
+	case "SPIGOT":
+		return Commands{TPS: "tps", Entities: entitiesCommand}
+	case "FORGE":
+		// forge tps reports both TPS and mean tick time
+		return Commands{TPS: "forge tps", Entities: entitiesCommand}
+	case "NEOFORGE":
+		return Commands{TPS: "neoforge tps", Entities: entitiesCommand}
+	default:
+		return Commands{Entities: entitiesCommand}
+	}
+}
+
+// SupportsTPS reports whether TPS can be read from serverType
+func SupportsTPS(serverType string) bool {
+	return CommandsFor(serverType).TPS != ""
+}
+
+var (
+	// formattingCodes matches legacy section-sign colour and style codes
+	formattingCodes = regexp.MustCompile(`§[0-9a-fk-orA-FK-OR]`)
+
+	// "TPS from last 1m, 5m, 15m: 20.0, 19.98, *20.0"
+	paperTPS = regexp.MustCompile(`TPS from last 1m, 5m, 15m:\s*\*?([\d.]+),\s*\*?([\d.]+),\s*\*?([\d.]+)`)
+
+	// "Server tick times (avg/min/max) from last 5s, 10s, 1m: ◴ 1.2/0.4/3.0, 1.3/0.4/5.1, 1.2/0.3/7.5"
+	paperMSPT = regexp.MustCompile(`([\d.]+)/[\d.]+/[\d.]+`)
+
+	// "Overall: Mean tick time: 0.714 ms. Mean TPS: 20.000"
+	forgeTPS = regexp.MustCompile(`Overall\s*:\s*Mean tick time:\s*([\d.]+)\s*ms\.?\s*Mean TPS:\s*([\d.]+)`)
+
+	// "Overall: 20.000 TPS (0.714 ms/tick)"
+	neoforgeTPS = regexp.MustCompile(`Overall\s*:\s*([\d.]+)\s*TPS\s*\(([\d.]+)\s*ms/tick\)`)
+
+	// "Chunks in all listed worlds: Total: 1234 Inactive: 0 Border: 120 Ticking: 880 Entity: 800"
+	paperChunks = regexp.MustCompile(`Chunks in all listed worlds:\s*Total:\s*(\d+)`)
+
+	// "Test passed, count: 42"
+	entityCount = regexp.MustCompile(`count:\s*(\d+)`)
+)
+
+// StripFormatting removes colour and style codes from command output
+func StripFormatting(output string) string {
+	return formattingCodes.ReplaceAllString(output, "")
+}
+
+// ParsePaperTPS parses the Paper/Spigot "tps" response into the 1m, 5m and 15m averages
+func ParsePaperTPS(response string) ([3]float64, error) {
+	var tps [3]float64
+	matches := paperTPS.FindStringSubmatch(StripFormatting(response))
+	if matches == nil {
+		return tps, fmt.Errorf("could not parse tps: %s", response)
+	}
+	for i := range tps {
+		value, err := strconv.ParseFloat(matches[i+1], 64)
+		if err != nil {
+			return tps, fmt.Errorf("failed to parse tps: %w", err)
+		}
+		tps[i] = value
+	}
+	return tps, nil
+}
+
+// ParsePaperMSPT parses the Paper "mspt" response into the 1m average tick time in milliseconds
+func ParsePaperMSPT(response string) (float64, error) {
+	matches := paperMSPT.FindAllStringSubmatch(StripFormatting(response), -1)
+	if len(matches) == 0 {
+		return 0, fmt.Errorf("could not parse mspt: %s", response)
+	}
+	// Averages are listed for 5s, 10s and 1m; the last one is the steadiest
+	return strconv.ParseFloat(matches[len(matches)-1][1], 64)
+}
+
+// ParseForgeTPS parses the "forge tps" response into the overall TPS and mean tick time in milliseconds
+func ParseForgeTPS(response string) (tps, mspt float64, err error) {
+	matches := forgeTPS.FindStringSubmatch(StripFormatting(response))
+	if matches == nil {
+		return 0, 0, fmt.Errorf("could not parse tps: %s", response)
+	}
+	return parseTPSAndMSPT(matches[2], matches[1])
+}
+
+// ParseNeoForgeTPS parses the "neoforge tps" response into the overall TPS and mean tick time in milliseconds
+func ParseNeoForgeTPS(response string) (tps, mspt float64, err error) {
+	matches := neoforgeTPS.FindStringSubmatch(StripFormatting(response))
+	if matches == nil {
+		return 0, 0, fmt.Errorf("could not parse tps: %s", response)
+	}
+	return parseTPSAndMSPT(matches[1], matches[2])
+}
+
+// parseTPSAndMSPT parses the matched TPS and tick time
+func parseTPSAndMSPT(tpsValue, msptValue string) (tps, mspt float64, err error) {
+	if tps, err = strconv.ParseFloat(tpsValue, 64); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse tps: %w", err)
+	}
+	if mspt, err = strconv.ParseFloat(msptValue, 64); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse tick time: %w", err)
+	}
+	return tps, mspt, nil
+}
+
+// ParsePaperChunks parses the "paper chunkinfo *" response into the total number of loaded chunks
+func ParsePaperChunks(response string) (int, error) {
+	matches := paperChunks.FindStringSubmatch(StripFormatting(response))
+	if matches == nil {
+		return 0, fmt.Errorf("could not parse chunk info: %s", response)
+	}
+	return strconv.Atoi(matches[1])
+}
+
+// ParseEntityCount parses the "execute if entity @e" response
+// The command fails with "Test failed" when no entity matched
+func ParseEntityCount(response string) (int, error) {
+	response = StripFormatting(response)
+	if strings.Contains(response, "Test failed") {
+		return 0, nil
+	}
+	matches := entityCount.FindStringSubmatch(response)
+	if matches == nil {
+		return 0, fmt.Errorf("could not parse entity count: %s", response)
+	}
+	return strconv.Atoi(matches[1])
+}
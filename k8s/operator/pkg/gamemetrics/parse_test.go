@@ -0,0 +1,167 @@
+package gamemetrics
+
+import (
+	"testing"
+)
+
+// Responses captured over RCON, including the colour codes the servers send
+const (
+	paperTPSOutput      = "§6TPS from last 1m, 5m, 15m: §a20.0, §a19.98, §a19.71"
+	paperTPSAboveTwenty = "§6TPS from last 1m, 5m, 15m: §a*20.0, §a*20.0, §a19.99"
+	spigotTPSOutput     = "§6TPS from last 1m, 5m, 15m: §e17.42, §a19.1, §a19.84"
+	paperMSPTOutput     = "§6Server tick times §e(§7avg§e/§7min§e/§7max§e)§6 from last 5s§7,§6 10s§7,§6 1m§e:\n" +
+		"§6◴ §a1.2§7/§a0.4§7/§a3.0§e, §a1.3§7/§a0.4§7/§a5.1§e, §a1.8§7/§a0.3§7/§a7.5"
+	paperChunksOutput = "§9Chunks in §aworld§9:\n§9Total: §a600 §9Inactive: §a0 §9Border: §a80 §9Ticking: §a400 §9Entity: §a120\n" +
+		"§9Chunks in §aworld_nether§9:\n§9Total: §a634 §9Inactive: §a0 §9Border: §a40 §9Ticking: §a480 §9Entity: §a680\n" +
+		"§9Chunks in all listed worlds: §9Total: §a1234 §9Inactive: §a0 §9Border: §a120 §9Ticking: §a880 §9Entity: §a800"
+	forgeTPSOutput = "Dim minecraft:overworld (minecraft:overworld): Mean tick time: 0.813 ms. Mean TPS: 20.000\n" +
+		"Dim minecraft:the_nether (minecraft:the_nether): Mean tick time: 0.051 ms. Mean TPS: 20.000\n" +
+		"Dim minecraft:the_end (minecraft:the_end): Mean tick time: 0.022 ms. Mean TPS: 20.000\n" +
+		"Overall: Mean tick time: 0.914 ms. Mean TPS: 20.000"
+	forgeTPSLagging   = "Overall: Mean tick time: 81.004 ms. Mean TPS: 12.345"
+	neoforgeTPSOutput = "minecraft:overworld: 20.000 TPS (0.812 ms/tick)\n" +
+		"minecraft:the_nether: 20.000 TPS (0.051 ms/tick)\n" +
+		"minecraft:the_end: 20.000 TPS (0.022 ms/tick)\n" +
+		"Overall: 20.000 TPS (0.914 ms/tick)"
+	neoforgeTPSLagging = "minecraft:overworld: 12.345 TPS (81.004 ms/tick)\nOverall: 12.345 TPS (81.004 ms/tick)"
+)
+
+func TestCommandsFor(t *testing.T) {
+	tests := []struct {
+		serverType string
+		expected   Commands
+	}{
+		{"PAPER", Commands{TPS: "tps", MSPT: "mspt", Chunks: "paper chunkinfo *", Entities: entitiesCommand}},
+		{"purpur", Commands{TPS: "tps", MSPT: "mspt", Chunks: "paper chunkinfo *", Entities: entitiesCommand}},
+		{"SPIGOT", Commands{TPS: "tps", Entities: entitiesCommand}},
+		{"FORGE", Commands{TPS: "forge tps", Entities: entitiesCommand}},
+		{"NEOFORGE", Commands{TPS: "neoforge tps", Entities: entitiesCommand}},
+		{"FABRIC", Commands{Entities: entitiesCommand}},
+		{"VANILLA", Commands{Entities: entitiesCommand}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.serverType, func(t *testing.T) {
+			if commands := CommandsFor(tt.serverType); commands != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, commands)
+			}
+		})
+	}
+}
+
+func TestParsePaperTPS(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected [3]float64
+	}{
+		{"paper", paperTPSOutput, [3]float64{20.0, 19.98, 19.71}},
+		{"above twenty", paperTPSAboveTwenty, [3]float64{20.0, 20.0, 19.99}},
+		{"spigot", spigotTPSOutput, [3]float64{17.42, 19.1, 19.84}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tps, err := ParsePaperTPS(tt.response)
+			if err != nil {
+				t.Fatalf("ParsePaperTPS: %v", err)
+			}
+			if tps != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, tps)
+			}
+		})
+	}
+
+	for _, response := range []string{"", "Unknown command. Type \"/help\" for help.", forgeTPSOutput} {
+		if tps, err := ParsePaperTPS(response); err == nil {
+			t.Errorf("expected %q to fail, got %v", response, tps)
+		}
+	}
+}
+
+func TestParsePaperMSPT(t *testing.T) {
+	mspt, err := ParsePaperMSPT(paperMSPTOutput)
+	if err != nil {
+		t.Fatalf("ParsePaperMSPT: %v", err)
+	}
+	if mspt != 1.8 {
+		t.Errorf("expected the 1m average 1.8, got %v", mspt)
+	}
+
+	if mspt, err := ParsePaperMSPT("Unknown command"); err == nil {
+		t.Errorf("expected an error, got %v", mspt)
+	}
+}
+
+func TestParsePaperChunks(t *testing.T) {
+	chunks, err := ParsePaperChunks(paperChunksOutput)
+	if err != nil {
+		t.Fatalf("ParsePaperChunks: %v", err)
+	}
+	if chunks != 1234 {
+		t.Errorf("expected the total of all worlds 1234, got %d", chunks)
+	}
+
+	if chunks, err := ParsePaperChunks("§9Chunks in §aworld§9:\n§9Total: §a600"); err == nil {
+		t.Errorf("expected a single world without the total to fail, got %d", chunks)
+	}
+}
+
+func TestParseForgeTPS(t *testing.T) {
+	tests := []struct {
+		name     string
+		parse    func(string) (float64, float64, error)
+		response string
+		tps      float64
+		mspt     float64
+	}{
+		{"forge", ParseForgeTPS, forgeTPSOutput, 20.0, 0.914},
+		{"forge lagging", ParseForgeTPS, forgeTPSLagging, 12.345, 81.004},
+		{"neoforge", ParseNeoForgeTPS, neoforgeTPSOutput, 20.0, 0.914},
+		{"neoforge lagging", ParseNeoForgeTPS, neoforgeTPSLagging, 12.345, 81.004},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tps, mspt, err := tt.parse(tt.response)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if tps != tt.tps || mspt != tt.mspt {
+				t.Errorf("expected %v TPS at %v ms, got %v TPS at %v ms", tt.tps, tt.mspt, tps, mspt)
+			}
+		})
+	}
+
+	// Each parser only accepts its own format, so a per-dimension line is never taken for the overall one
+	if _, _, err := ParseForgeTPS(neoforgeTPSOutput); err == nil {
+		t.Error("expected ParseForgeTPS to reject NeoForge output")
+	}
+	if _, _, err := ParseNeoForgeTPS(forgeTPSOutput); err == nil {
+		t.Error("expected ParseNeoForgeTPS to reject Forge output")
+	}
+}
+
+func TestParseEntityCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected int
+	}{
+		{"entities", "Test passed, count: 42", 42},
+		{"formatted", "§aTest passed, count: 1337", 1337},
+		{"none", "Test failed", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := ParseEntityCount(tt.response)
+			if err != nil {
+				t.Fatalf("ParseEntityCount: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, count)
+			}
+		})
+	}
+
+	if count, err := ParseEntityCount("Unknown or incomplete command"); err == nil {
+		t.Errorf("expected an error, got %d", count)
+	}
+}
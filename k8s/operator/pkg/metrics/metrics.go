@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Phases reported by minecraft_server_phase; kept here so the package doesn't depend on the API types
+var phases = []string{"Pending", "Starting", "Running", "Stopping", "Stopped", "Error"}
+
+// Reconcile steps timed by minecraft_operator_reconcile_step_duration_seconds
+const (
+	StepConfigMap   = "configmap"
+	StepService     = "service"
+	StepMonitoring  = "monitoring"
+	StepStatefulSet = "statefulset"
+	StepStatus      = "status"
+)
+
+var (
+	// PlayersOnline is the number of players currently online
+	PlayersOnline = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "minecraft_server_players_online",
+		Help: "Number of players currently online",
+	}, []string{"tenant", "server"})
+
+	// ServerPhase is 1 for the server's current phase and 0 for every other phase
+	ServerPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "minecraft_server_phase",
+		Help: "Current server phase (1 for the active phase, 0 otherwise)",
+	}, []string{"tenant", "server", "phase"})
+
+	// RCONLatency is the duration of RCON commands run through rcon-cli
+	RCONLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "minecraft_server_rcon_latency_seconds",
+		Help:    "Latency of RCON commands executed by the operator",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"tenant", "server"})
+
+	// RCONErrors counts failed RCON commands
+	RCONErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "minecraft_server_rcon_errors_total",
+		Help: "Number of RCON commands that failed",
+	}, []string{"tenant", "server"})
+
+	// AutoStops counts servers stopped for inactivity
+	AutoStops = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "minecraft_server_auto_stops_total",
+		Help: "Number of times the server was stopped for inactivity",
+	}, []string{"tenant", "server"})
+
+	// BackupDuration is the run time of finished backup Jobs
+	BackupDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "minecraft_server_backup_duration_seconds",
+		Help:    "Duration of finished backup jobs",
+		Buckets: []float64{5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+	}, []string{"tenant", "server"})
+
+	// Backups counts finished backup Jobs by result (success or failure)
+	Backups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "minecraft_server_backups_total",
+		Help: "Number of finished backup jobs by result",
+	}, []string{"tenant", "server", "result"})
+
+	// LastBackupSuccess is the Unix time of the last successful backup
+	LastBackupSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "minecraft_server_last_backup_success_timestamp_seconds",
+		Help: "Unix time of the last successful backup",
+	}, []string{"tenant", "server"})
+
+	// EventPublishFailures counts NATS events that could not be published
+	EventPublishFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "minecraft_operator_event_publish_failures_total",
+		Help: "Number of operator events that failed to publish to NATS",
+	}, []string{"tenant", "server", "type"})
+
+	// ReconcileStepDuration is the duration of each reconcile step
+	// Labelled by step only; per-server histograms would multiply the series count by every bucket
+	ReconcileStepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "minecraft_operator_reconcile_step_duration_seconds",
+		Help:    "Duration of each MinecraftServer reconcile step",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"step"})
+
+	// RouterConnections counts connections handled by the built-in router by action
+	// (proxied, status, woken, held, rejected or unknown); not labelled by server to keep scanners from adding series
+	RouterConnections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "minecraft_router_connections_total",
+		Help: "Number of connections handled by the built-in router by action",
+	}, []string{"action"})
+)
+
+func init() {
+	// Served on the controller-runtime metrics endpoint (--metrics-bind-address)
+	crmetrics.Registry.MustRegister(
+		PlayersOnline,
+		ServerPhase,
+		RCONLatency,
+		RCONErrors,
+		AutoStops,
+		BackupDuration,
+		Backups,
+		LastBackupSuccess,
+		EventPublishFailures,
+		ReconcileStepDuration,
+		RouterConnections,
+	)
+}
+
+// SetPhase marks phase as the server's active phase
+func SetPhase(tenant, server, phase string) {
+	for _, p := range phases {
+		value := 0.0
+		if p == phase {
+			value = 1
+		}
+		ServerPhase.WithLabelValues(tenant, server, p).Set(value)
+	}
+}
+
+// ObserveStep records the duration of a reconcile step started at start
+func ObserveStep(step string, start time.Time) {
+	ReconcileStepDuration.WithLabelValues(step).Observe(time.Since(start).Seconds())
+}
+
+// ObserveRCON records the latency of an RCON command and counts it as an error if err is set
+func ObserveRCON(tenant, server string, start time.Time, err error) {
+	RCONLatency.WithLabelValues(tenant, server).Observe(time.Since(start).Seconds())
+	if err != nil {
+		RCONErrors.WithLabelValues(tenant, server).Inc()
+	}
+}
+
+// ObserveBackup records a finished backup
+func ObserveBackup(tenant, server string, duration time.Duration, succeeded bool, finishedAt time.Time) {
+	result := "failure"
+	if succeeded {
+		result = "success"
+		LastBackupSuccess.WithLabelValues(tenant, server).Set(float64(finishedAt.Unix()))
+	}
+	BackupDuration.WithLabelValues(tenant, server).Observe(duration.Seconds())
+	Backups.WithLabelValues(tenant, server, result).Inc()
+}
+
+// DeleteServer removes every series of a deleted server
+func DeleteServer(tenant, server string) {
+	labels := prometheus.Labels{"tenant": tenant, "server": server}
+	PlayersOnline.DeletePartialMatch(labels)
+	ServerPhase.DeletePartialMatch(labels)
+	RCONLatency.DeletePartialMatch(labels)
+	RCONErrors.DeletePartialMatch(labels)
+	AutoStops.DeletePartialMatch(labels)
+	BackupDuration.DeletePartialMatch(labels)
+	Backups.DeletePartialMatch(labels)
+	LastBackupSuccess.DeletePartialMatch(labels)
+	EventPublishFailures.DeletePartialMatch(labels)
+}
@@ -0,0 +1,383 @@
+// Package modpack resolves modpack versions from Modrinth and CurseForge
+package modpack
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	modrinthAPI   = "https://api.modrinth.com/v2"
+	curseForgeAPI = "https://api.curseforge.com/v1"
+
+	// curseForgeMinecraft and curseForgeModpacks are CurseForge's game and class IDs for Minecraft modpacks
+	curseForgeMinecraft = 432
+	curseForgeModpacks  = 4471
+
+	// maxPackSize is the largest pack downloaded to read its manifest; bigger packs are resolved without a loader version
+	maxPackSize = 64 << 20
+
+	// cacheTTL is how long a resolution is reused, so an unpinned pack picks up new versions within the hour
+	cacheTTL = time.Hour
+)
+
+// Request is a modpack to resolve
+type Request struct {
+	// Source is Modrinth, CurseForge or URL
+	Source string
+	// Project is the Modrinth project slug or ID, or the CurseForge project slug
+	Project string
+	// URL is a .mrpack for Modrinth or a server pack for URL
+	URL string
+	// Version pins the pack version; empty resolves the newest one for GameVersion and Loader
+	Version string
+	// GameVersion and Loader narrow unpinned lookups; GameVersion is ignored when empty or LATEST
+	GameVersion string
+	Loader      string
+	// APIKey authenticates CurseForge requests
+	APIKey string
+}
+
+// Pack is a resolved modpack version
+type Pack struct {
+	Version       string
+	VersionID     string
+	GameVersion   string
+	Loader        string
+	LoaderVersion string
+}
+
+// Resolver resolves a modpack request to a specific version
+type Resolver interface {
+	Resolve(ctx context.Context, req Request) (*Pack, error)
+}
+
+// HTTPResolver resolves packs through the Modrinth and CurseForge APIs and caches the results
+type HTTPResolver struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[Request]cachedPack
+}
+
+type cachedPack struct {
+	pack    *Pack
+	expires time.Time
+}
+
+// NewHTTPResolver creates a resolver using client, or a client with a 30s timeout when nil
+func NewHTTPResolver(client *http.Client) *HTTPResolver {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &HTTPResolver{client: client, cache: map[Request]cachedPack{}}
+}
+
+// Resolve returns the pack version req selects
+func (r *HTTPResolver) Resolve(ctx context.Context, req Request) (*Pack, error) {
+	r.mu.Lock()
+	cached, ok := r.cache[req]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.pack, nil
+	}
+
+	var pack *Pack
+	var err error
+	switch req.Source {
+	case "Modrinth":
+		pack, err = r.resolveModrinth(ctx, req)
+	case "CurseForge":
+		pack, err = r.resolveCurseForge(ctx, req)
+	case "URL":
+		// Server packs are extracted as they are; the version is whatever the spec calls it
+		pack = &Pack{Version: req.Version, VersionID: req.Version, GameVersion: req.GameVersion, Loader: req.Loader}
+	default:
+		err = fmt.Errorf("unknown modpack source %q", req.Source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[req] = cachedPack{pack: pack, expires: time.Now().Add(cacheTTL)}
+	r.mu.Unlock()
+	return pack, nil
+}
+
+// modrinthVersion is the subset of a Modrinth project version the resolver reads
+type modrinthVersion struct {
+	ID            string   `json:"id"`
+	VersionNumber string   `json:"version_number"`
+	GameVersions  []string `json:"game_versions"`
+	Loaders       []string `json:"loaders"`
+	Files         []struct {
+		URL     string `json:"url"`
+		Primary bool   `json:"primary"`
+	} `json:"files"`
+}
+
+// mrpackIndex is the subset of modrinth.index.json the resolver reads
+type mrpackIndex struct {
+	VersionID    string            `json:"versionId"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// mrpackLoaders maps modrinth.index.json dependency keys to loader names
+var mrpackLoaders = map[string]string{
+	"forge":         "forge",
+	"neoforge":      "neoforge",
+	"fabric-loader": "fabric",
+	"quilt-loader":  "quilt",
+}
+
+// resolveModrinth picks a project version, or reads an .mrpack given by URL, and reads the loader from its index
+func (r *HTTPResolver) resolveModrinth(ctx context.Context, req Request) (*Pack, error) {
+	pack := &Pack{}
+	packURL := req.URL
+
+	if req.Project != "" {
+		query := url.Values{}
+		if req.Version == "" {
+			if req.Loader != "" {
+				query.Set("loaders", fmt.Sprintf("[%q]", req.Loader))
+			}
+			if req.GameVersion != "" && req.GameVersion != "LATEST" {
+				query.Set("game_versions", fmt.Sprintf("[%q]", req.GameVersion))
+			}
+		}
+		var versions []modrinthVersion
+		endpoint := fmt.Sprintf("%s/project/%s/version?%s", modrinthAPI, url.PathEscape(req.Project), query.Encode())
+		if err := r.getJSON(ctx, endpoint, nil, &versions); err != nil {
+			return nil, fmt.Errorf("failed to list versions of %s: %w", req.Project, err)
+		}
+
+		// Versions are listed newest first
+		var selected *modrinthVersion
+		for i := range versions {
+			if req.Version == "" || versions[i].VersionNumber == req.Version || versions[i].ID == req.Version {
+				selected = &versions[i]
+				break
+			}
+		}
+		if selected == nil {
+			if req.Version != "" {
+				return nil, fmt.Errorf("version %s of %s not found", req.Version, req.Project)
+			}
+			return nil, fmt.Errorf("%s has no version for %s %s", req.Project, req.Loader, req.GameVersion)
+		}
+
+		pack.Version = selected.VersionNumber
+		pack.VersionID = selected.ID
+		if len(selected.GameVersions) > 0 {
+			pack.GameVersion = selected.GameVersions[0]
+		}
+		if len(selected.Loaders) > 0 {
+			pack.Loader = selected.Loaders[0]
+		}
+		packURL = ""
+		for _, file := range selected.Files {
+			if file.Primary || packURL == "" {
+				packURL = file.URL
+			}
+		}
+	}
+
+	if packURL == "" {
+		return pack, nil
+	}
+	var index mrpackIndex
+	if err := r.readPackFile(ctx, packURL, "modrinth.index.json", &index); err != nil {
+		if req.Project == "" {
+			return nil, err
+		}
+		// The project API already named the version; only the loader version is missing
+		return pack, nil
+	}
+	if pack.Version == "" {
+		pack.Version = index.VersionID
+		pack.VersionID = index.VersionID
+	}
+	if gameVersion, ok := index.Dependencies["minecraft"]; ok {
+		pack.GameVersion = gameVersion
+	}
+	for key, loader := range mrpackLoaders {
+		if version, ok := index.Dependencies[key]; ok {
+			pack.Loader = loader
+			pack.LoaderVersion = version
+		}
+	}
+	return pack, nil
+}
+
+// curseForgeFile is the subset of a CurseForge file the resolver reads
+type curseForgeFile struct {
+	ID           int      `json:"id"`
+	DisplayName  string   `json:"displayName"`
+	GameVersions []string `json:"gameVersions"`
+	DownloadURL  string   `json:"downloadUrl"`
+	FileLength   int64    `json:"fileLength"`
+}
+
+// curseForgeManifest is the subset of a CurseForge pack's manifest.json the resolver reads
+type curseForgeManifest struct {
+	Minecraft struct {
+		Version    string `json:"version"`
+		ModLoaders []struct {
+			ID      string `json:"id"`
+			Primary bool   `json:"primary"`
+		} `json:"modLoaders"`
+	} `json:"minecraft"`
+}
+
+// resolveCurseForge finds the project by slug and picks a file, reading the loader from its manifest
+func (r *HTTPResolver) resolveCurseForge(ctx context.Context, req Request) (*Pack, error) {
+	if req.APIKey == "" {
+		return nil, fmt.Errorf("a CurseForge API key is required")
+	}
+	headers := map[string]string{"x-api-key": req.APIKey}
+
+	var search struct {
+		Data []struct {
+			ID int `json:"id"`
+		} `json:"data"`
+	}
+	query := url.Values{}
+	query.Set("gameId", strconv.Itoa(curseForgeMinecraft))
+	query.Set("classId", strconv.Itoa(curseForgeModpacks))
+	query.Set("slug", req.Project)
+	if err := r.getJSON(ctx, curseForgeAPI+"/mods/search?"+query.Encode(), headers, &search); err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", req.Project, err)
+	}
+	if len(search.Data) == 0 {
+		return nil, fmt.Errorf("CurseForge modpack %s not found", req.Project)
+	}
+	modID := search.Data[0].ID
+
+	var file curseForgeFile
+	if req.Version != "" {
+		var response struct {
+			Data curseForgeFile `json:"data"`
+		}
+		if err := r.getJSON(ctx, fmt.Sprintf("%s/mods/%d/files/%s", curseForgeAPI, modID, url.PathEscape(req.Version)), headers, &response); err != nil {
+			return nil, fmt.Errorf("file %s of %s not found: %w", req.Version, req.Project, err)
+		}
+		file = response.Data
+	} else {
+		var response struct {
+			Data []curseForgeFile `json:"data"`
+		}
+		query := url.Values{}
+		query.Set("pageSize", "1")
+		if req.GameVersion != "" && req.GameVersion != "LATEST" {
+			query.Set("gameVersion", req.GameVersion)
+		}
+		if err := r.getJSON(ctx, fmt.Sprintf("%s/mods/%d/files?%s", curseForgeAPI, modID, query.Encode()), headers, &response); err != nil {
+			return nil, fmt.Errorf("failed to list files of %s: %w", req.Project, err)
+		}
+		if len(response.Data) == 0 {
+			return nil, fmt.Errorf("%s has no file for %s", req.Project, req.GameVersion)
+		}
+		file = response.Data[0]
+	}
+
+	pack := &Pack{Version: file.DisplayName, VersionID: strconv.Itoa(file.ID)}
+	for _, gameVersion := range file.GameVersions {
+		switch lower := strings.ToLower(gameVersion); {
+		case strings.HasPrefix(lower, "1."):
+			pack.GameVersion = gameVersion
+		case lower == "forge" || lower == "neoforge" || lower == "fabric" || lower == "quilt":
+			pack.Loader = lower
+		}
+	}
+
+	// Some projects disallow third-party downloads; the image then fails with a clear message of its own
+	if file.DownloadURL == "" || file.FileLength > maxPackSize {
+		return pack, nil
+	}
+	var manifest curseForgeManifest
+	if err := r.readPackFile(ctx, file.DownloadURL, "manifest.json", &manifest); err != nil {
+		return pack, nil
+	}
+	if manifest.Minecraft.Version != "" {
+		pack.GameVersion = manifest.Minecraft.Version
+	}
+	for _, loader := range manifest.Minecraft.ModLoaders {
+		// IDs look like forge-47.2.0 or fabric-0.15.11
+		if name, version, ok := strings.Cut(loader.ID, "-"); ok && (loader.Primary || pack.LoaderVersion == "") {
+			pack.Loader = name
+			pack.LoaderVersion = version
+		}
+	}
+	return pack, nil
+}
+
+// getJSON decodes the JSON response of a GET request
+func (r *HTTPResolver) getJSON(ctx context.Context, endpoint string, headers map[string]string, into interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", "minecraft-platform-operator")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", request.URL.Host, response.Status)
+	}
+	return json.NewDecoder(response.Body).Decode(into)
+}
+
+// readPackFile downloads a pack archive and decodes one JSON file from it
+func (r *HTTPResolver) readPackFile(ctx context.Context, packURL, name string, into interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, packURL, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("User-Agent", "minecraft-platform-operator")
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s returned %s", packURL, response.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxPackSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxPackSize {
+		return fmt.Errorf("pack %s is larger than %d MiB", packURL, maxPackSize>>20)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("pack %s is not a zip archive: %w", packURL, err)
+	}
+	file, err := archive.Open(name)
+	if err != nil {
+		return fmt.Errorf("pack %s has no %s", packURL, name)
+	}
+	defer file.Close()
+	return json.NewDecoder(file).Decode(into)
+}
@@ -0,0 +1,239 @@
+package modpack
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// packArchive builds a pack zip holding one JSON file
+func packArchive(t *testing.T, name string, content interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	file, err := archive.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.NewEncoder(file).Encode(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// fakeAPI serves Modrinth, CurseForge and pack downloads from one handler and counts the requests
+type fakeAPI struct {
+	requests atomic.Int32
+	handler  func(w http.ResponseWriter, r *http.Request)
+}
+
+// RoundTrip sends every request to the handler, whatever its host
+func (f *fakeAPI) RoundTrip(r *http.Request) (*http.Response, error) {
+	f.requests.Add(1)
+	recorder := httptest.NewRecorder()
+	f.handler(recorder, r)
+	return recorder.Result(), nil
+}
+
+// newResolver returns a resolver whose requests are answered by handler
+func newResolver(handler func(w http.ResponseWriter, r *http.Request)) (*HTTPResolver, *fakeAPI) {
+	api := &fakeAPI{handler: handler}
+	return NewHTTPResolver(&http.Client{Transport: api}), api
+}
+
+// writeJSON encodes value as the response
+func writeJSON(t *testing.T, w http.ResponseWriter, value interface{}) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		t.Error(err)
+	}
+}
+
+// modrinthVersions is a project version list, newest first
+var modrinthVersions = []map[string]interface{}{
+	{
+		"id": "v3", "version_number": "3.0.0", "game_versions": []string{"1.20.4"}, "loaders": []string{"fabric"},
+		"files": []map[string]interface{}{{"url": "https://cdn.modrinth.com/v3.mrpack", "primary": true}},
+	},
+	{
+		"id": "v2", "version_number": "2.0.0", "game_versions": []string{"1.20.1"}, "loaders": []string{"fabric"},
+		"files": []map[string]interface{}{{"url": "https://cdn.modrinth.com/v2.mrpack", "primary": true}},
+	},
+}
+
+func TestResolveModrinthProject(t *testing.T) {
+	var query url.Values
+	resolver, _ := newResolver(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/project/pack/version":
+			query = r.URL.Query()
+			writeJSON(t, w, modrinthVersions)
+		case strings.HasSuffix(r.URL.Path, ".mrpack"):
+			gameVersion := map[string]string{"/v3.mrpack": "1.20.4", "/v2.mrpack": "1.20.1"}[r.URL.Path]
+			_, _ = w.Write(packArchive(t, "modrinth.index.json", map[string]interface{}{
+				"versionId":    "ignored",
+				"dependencies": map[string]string{"minecraft": gameVersion, "fabric-loader": "0.15.11"},
+			}))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		version  string
+		expected Pack
+	}{
+		{"newest", "", Pack{Version: "3.0.0", VersionID: "v3", GameVersion: "1.20.4", Loader: "fabric", LoaderVersion: "0.15.11"}},
+		{"pinned by number", "2.0.0", Pack{Version: "2.0.0", VersionID: "v2", GameVersion: "1.20.1", Loader: "fabric", LoaderVersion: "0.15.11"}},
+		{"pinned by ID", "v2", Pack{Version: "2.0.0", VersionID: "v2", GameVersion: "1.20.1", Loader: "fabric", LoaderVersion: "0.15.11"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pack, err := resolver.Resolve(context.Background(), Request{
+				Source: "Modrinth", Project: "pack", Version: tt.version, GameVersion: "1.20.4", Loader: "fabric",
+			})
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			if !reflect.DeepEqual(*pack, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, *pack)
+			}
+		})
+	}
+
+	// Only unpinned lookups are narrowed to the server's loader and version
+	if query.Get("loaders") != "" {
+		t.Errorf("pinned lookup filtered by loader: %v", query)
+	}
+	if _, err := resolver.Resolve(context.Background(), Request{Source: "Modrinth", Project: "pack", GameVersion: "LATEST", Loader: "fabric"}); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if query.Get("loaders") != `["fabric"]` || query.Has("game_versions") {
+		t.Errorf("unexpected query for an unpinned LATEST lookup: %v", query)
+	}
+}
+
+func TestResolveModrinthErrors(t *testing.T) {
+	resolver, _ := newResolver(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/project/missing/version" {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(t, w, []interface{}{})
+	})
+
+	tests := []struct {
+		name string
+		req  Request
+		err  string
+	}{
+		{"unknown project", Request{Source: "Modrinth", Project: "missing"}, "failed to list versions of missing"},
+		{"unknown version", Request{Source: "Modrinth", Project: "pack", Version: "9.9.9"}, "version 9.9.9 of pack not found"},
+		{"no version for the server", Request{Source: "Modrinth", Project: "pack", GameVersion: "1.7.10", Loader: "forge"}, "pack has no version for forge 1.7.10"},
+		{"unknown source", Request{Source: "Hangar", Project: "pack"}, `unknown modpack source "Hangar"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolver.Resolve(context.Background(), tt.req)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestResolveMrpackURL(t *testing.T) {
+	resolver, _ := newResolver(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(packArchive(t, "modrinth.index.json", map[string]interface{}{
+			"versionId":    "1.4.2",
+			"dependencies": map[string]string{"minecraft": "1.20.1", "forge": "47.2.0"},
+		}))
+	})
+
+	pack, err := resolver.Resolve(context.Background(), Request{Source: "Modrinth", URL: "https://example.com/pack.mrpack"})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	expected := Pack{Version: "1.4.2", VersionID: "1.4.2", GameVersion: "1.20.1", Loader: "forge", LoaderVersion: "47.2.0"}
+	if *pack != expected {
+		t.Errorf("expected %+v, got %+v", expected, *pack)
+	}
+}
+
+func TestResolveCurseForge(t *testing.T) {
+	resolver, _ := newResolver(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host == "api.curseforge.com" && r.Header.Get("x-api-key") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/mods/search":
+			writeJSON(t, w, map[string]interface{}{"data": []map[string]int{{"id": 123}}})
+		case "/v1/mods/123/files":
+			writeJSON(t, w, map[string]interface{}{"data": []map[string]interface{}{{
+				"id": 4567, "displayName": "Pack 1.2", "gameVersions": []string{"1.20.1", "Forge"},
+				"downloadUrl": "https://edge.forgecdn.net/pack.zip", "fileLength": 1024,
+			}}})
+		case "/pack.zip":
+			_, _ = w.Write(packArchive(t, "manifest.json", map[string]interface{}{
+				"minecraft": map[string]interface{}{
+					"version":    "1.20.1",
+					"modLoaders": []map[string]interface{}{{"id": "forge-47.2.0", "primary": true}},
+				},
+			}))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	if _, err := resolver.Resolve(context.Background(), Request{Source: "CurseForge", Project: "pack"}); err == nil {
+		t.Error("expected an error without an API key")
+	}
+
+	pack, err := resolver.Resolve(context.Background(), Request{Source: "CurseForge", Project: "pack", APIKey: "secret", GameVersion: "1.20.1"})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	expected := Pack{Version: "Pack 1.2", VersionID: "4567", GameVersion: "1.20.1", Loader: "forge", LoaderVersion: "47.2.0"}
+	if *pack != expected {
+		t.Errorf("expected %+v, got %+v", expected, *pack)
+	}
+}
+
+func TestResolveCachesResults(t *testing.T) {
+	resolver, api := newResolver(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, modrinthVersions[:1])
+	})
+	// A pack URL that can't be read still resolves, since the project API named the version
+	req := Request{Source: "Modrinth", Project: "pack"}
+	for i := 0; i < 3; i++ {
+		if _, err := resolver.Resolve(context.Background(), req); err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+	}
+	if requests := api.requests.Load(); requests != 2 {
+		t.Errorf("expected one version lookup and one download, got %d requests", requests)
+	}
+
+	// URL packs need no lookup at all
+	pack, err := resolver.Resolve(context.Background(), Request{Source: "URL", URL: "https://example.com/pack.zip", Version: "7"})
+	if err != nil || pack.VersionID != "7" {
+		t.Errorf("expected version 7, got %+v, %v", pack, err)
+	}
+	if requests := api.requests.Load(); requests != 2 {
+		t.Errorf("URL pack made requests")
+	}
+}
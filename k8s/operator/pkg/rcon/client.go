@@ -3,10 +3,12 @@ package rcon
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,6 +19,9 @@ const (
 	packetTypeAuthResp = 2
 )
 
+// ErrAuthFailed is returned when the server rejects the RCON password
+var ErrAuthFailed = errors.New("authentication failed - wrong password")
+
 type Client struct {
 	conn      net.Conn
 	requestID int32
@@ -61,7 +66,7 @@ func (c *Client) authenticate(password string) error {
 	}
 
 	if respType == -1 {
-		return fmt.Errorf("authentication failed - wrong password")
+		return ErrAuthFailed
 	}
 
 	return nil
@@ -161,6 +166,16 @@ func (c *Client) readPacket() (int32, int32, string, error) {
 	return requestID, packetType, string(payload), nil
 }
 
+// IsAuthError reports whether err is caused by a rejected RCON password
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrAuthFailed)
+}
+
+// IsAuthFailureOutput reports whether rcon-cli output indicates a rejected password
+func IsAuthFailureOutput(output string) bool {
+	return strings.Contains(strings.ToLower(output), "authentication failed")
+}
+
 // PlayerInfo contains player count information
 type PlayerInfo struct {
 	Online  int
@@ -0,0 +1,269 @@
+// Package registry resolves container image tags to manifest digests through the registry v2 API
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// dockerHub is the registry of images without a registry host, such as itzg/minecraft-server
+	dockerHub = "registry-1.docker.io"
+
+	// cacheTTL is how long a resolved digest is reused, so a pushed tag is noticed within this time
+	cacheTTL = 15 * time.Minute
+
+	// maxManifestSize bounds manifests downloaded to compute a digest the registry didn't report
+	maxManifestSize = 4 << 20
+)
+
+// manifestTypes are the manifest formats accepted; indexes come first so multi-arch images resolve to their index
+var manifestTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Reference is a parsed image reference
+type Reference struct {
+	// Registry is the registry host, Repository the path within it
+	Registry   string
+	Repository string
+	// Tag defaults to latest; Digest is set when the reference is already pinned
+	Tag    string
+	Digest string
+}
+
+// ParseReference splits an image reference such as itzg/minecraft-server:java21 into its parts
+func ParseReference(image string) (Reference, error) {
+	var ref Reference
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if !strings.HasPrefix(ref.Digest, "sha256:") {
+			return Reference{}, fmt.Errorf("invalid digest in image %q", image)
+		}
+	}
+	// A colon after the last slash separates the tag; one before it is a registry port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	if ref.Tag == "" {
+		ref.Tag = "latest"
+	}
+
+	ref.Registry, ref.Repository = dockerHub, name
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.Registry, ref.Repository = host, name[i+1:]
+		}
+	}
+	if ref.Repository == "" {
+		return Reference{}, fmt.Errorf("invalid image %q", image)
+	}
+	if ref.Registry == dockerHub && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	return ref, nil
+}
+
+// Resolver resolves an image tag to the digest it currently points at
+type Resolver interface {
+	Resolve(ctx context.Context, image string) (string, error)
+}
+
+// HTTPResolver asks the image's registry for the tag's manifest digest and caches the results
+// Only anonymous pulls are supported, so images in private registries should be pinned by digest in spec.image.
+type HTTPResolver struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedDigest
+}
+
+type cachedDigest struct {
+	digest  string
+	expires time.Time
+}
+
+// NewHTTPResolver creates a resolver using client, or a client with a 30s timeout when nil
+func NewHTTPResolver(client *http.Client) *HTTPResolver {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &HTTPResolver{client: client, cache: map[string]cachedDigest{}}
+}
+
+// Resolve returns the sha256 digest image points at
+func (r *HTTPResolver) Resolve(ctx context.Context, image string) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	r.mu.Lock()
+	cached, ok := r.cache[image]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.digest, nil
+	}
+
+	digest, err := r.fetchDigest(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", image, err)
+	}
+
+	r.mu.Lock()
+	r.cache[image] = cachedDigest{digest: digest, expires: time.Now().Add(cacheTTL)}
+	r.mu.Unlock()
+	return digest, nil
+}
+
+// fetchDigest reads the manifest digest with a HEAD request, falling back to hashing the manifest
+// Registries answer 401 with a token endpoint first; Docker Hub hands out anonymous pull tokens.
+func (r *HTTPResolver) fetchDigest(ctx context.Context, ref Reference) (string, error) {
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.Registry, ref.Repository, ref.Tag)
+
+	var token string
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		resp, err := r.manifestRequest(ctx, method, manifestURL, token)
+		if err != nil {
+			return "", err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && token == "" {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			token, err = r.fetchToken(ctx, challenge, ref)
+			if err != nil {
+				return "", err
+			}
+			resp, err = r.manifestRequest(ctx, method, manifestURL, token)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		digest, err := manifestDigest(resp, method == http.MethodGet)
+		resp.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		if digest != "" {
+			return digest, nil
+		}
+	}
+	return "", fmt.Errorf("registry did not report a manifest digest")
+}
+
+// manifestRequest requests the manifest, authenticated with token when set
+func (r *HTTPResolver) manifestRequest(ctx context.Context, method, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r.client.Do(req)
+}
+
+// manifestDigest returns the digest of a manifest response; the body is only hashed when the header is missing
+func manifestDigest(resp *http.Response, hashBody bool) (string, error) {
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry returned %s", resp.Status)
+	}
+	if digest := resp.Header.Get("Docker-Content-Digest"); strings.HasPrefix(digest, "sha256:") {
+		return digest, nil
+	}
+	if !hashBody {
+		return "", nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxManifestSize {
+		return "", fmt.Errorf("manifest is larger than %d bytes", maxManifestSize)
+	}
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// fetchToken requests an anonymous pull token from the realm of a Bearer challenge
+func (r *HTTPResolver) fetchToken(ctx context.Context, challenge string, ref Reference) (string, error) {
+	params := parseChallenge(challenge)
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry requires authentication")
+	}
+
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid token realm %q: %w", realm, err)
+	}
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid token response: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("token endpoint returned no token")
+}
+
+// parseChallenge reads the parameters of a `Bearer realm="...",service="..."` challenge
+func parseChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+	scheme, rest, ok := strings.Cut(challenge, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return params
+	}
+	for _, part := range strings.Split(rest, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			params[strings.ToLower(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return params
+}
@@ -0,0 +1,146 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		image    string
+		expected Reference
+	}{
+		{"itzg/minecraft-server", Reference{Registry: dockerHub, Repository: "itzg/minecraft-server", Tag: "latest"}},
+		{"itzg/minecraft-server:java21", Reference{Registry: dockerHub, Repository: "itzg/minecraft-server", Tag: "java21"}},
+		{"alpine", Reference{Registry: dockerHub, Repository: "library/alpine", Tag: "latest"}},
+		{"ghcr.io/org/team/server:1.2", Reference{Registry: "ghcr.io", Repository: "org/team/server", Tag: "1.2"}},
+		{"localhost/server", Reference{Registry: "localhost", Repository: "server", Tag: "latest"}},
+		{"registry.local:5000/server", Reference{Registry: "registry.local:5000", Repository: "server", Tag: "latest"}},
+		{"registry.local:5000/server:v2", Reference{Registry: "registry.local:5000", Repository: "server", Tag: "v2"}},
+		{"itzg/minecraft-server@" + digest, Reference{Registry: dockerHub, Repository: "itzg/minecraft-server", Tag: "latest", Digest: digest}},
+		{"itzg/minecraft-server:java21@" + digest, Reference{Registry: dockerHub, Repository: "itzg/minecraft-server", Tag: "java21", Digest: digest}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			ref, err := ParseReference(tt.image)
+			if err != nil {
+				t.Fatalf("ParseReference: %v", err)
+			}
+			if ref != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, ref)
+			}
+		})
+	}
+
+	for _, image := range []string{"", ":latest", "ghcr.io/", "itzg/minecraft-server@md5:abc"} {
+		if ref, err := ParseReference(image); err == nil {
+			t.Errorf("expected %q to be invalid, got %+v", image, ref)
+		}
+	}
+}
+
+// response builds a manifest response
+func response(status int, digestHeader, body string) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+	if digestHeader != "" {
+		resp.Header.Set("Docker-Content-Digest", digestHeader)
+	}
+	return resp
+}
+
+func TestManifestDigest(t *testing.T) {
+	body := `{"schemaVersion":2}`
+	sum := sha256.Sum256([]byte(body))
+	bodyDigest := "sha256:" + hex.EncodeToString(sum[:])
+	headerDigest := "sha256:" + strings.Repeat("b", 64)
+
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		hashBody bool
+		expected string
+		err      bool
+	}{
+		{"header", response(http.StatusOK, headerDigest, body), false, headerDigest, false},
+		{"header wins over body", response(http.StatusOK, headerDigest, body), true, headerDigest, false},
+		{"HEAD without header", response(http.StatusOK, "", ""), false, "", false},
+		{"GET without header hashes the body", response(http.StatusOK, "", body), true, bodyDigest, false},
+		{"non-sha256 header is ignored", response(http.StatusOK, "sha512:abc", body), true, bodyDigest, false},
+		{"error status", response(http.StatusNotFound, headerDigest, ""), true, "", true},
+		{"oversized manifest", response(http.StatusOK, "", strings.Repeat(" ", maxManifestSize+1)), true, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digest, err := manifestDigest(tt.resp, tt.hashBody)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if digest != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, digest)
+			}
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io"`)
+	if params["realm"] != "https://auth.docker.io/token" || params["service"] != "registry.docker.io" {
+		t.Errorf("unexpected params %v", params)
+	}
+	if params := parseChallenge(`Basic realm="registry"`); len(params) != 0 {
+		t.Errorf("expected no params for Basic, got %v", params)
+	}
+}
+
+func TestResolveWithToken(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("c", 64)
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if r.URL.Query().Get("scope") != "repository:itzg/minecraft-server:pull" {
+				http.Error(w, "bad scope", http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token":"pull-token"}`))
+		case "/v2/itzg/minecraft-server/manifests/java21":
+			if r.Header.Get("Authorization") != "Bearer pull-token" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", digest)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewHTTPResolver(server.Client())
+	host := strings.TrimPrefix(server.URL, "https://")
+	resolved, err := resolver.Resolve(context.Background(), host+"/itzg/minecraft-server:java21")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if resolved != digest {
+		t.Errorf("expected %s, got %s", digest, resolved)
+	}
+
+	// Pinned references don't reach the registry
+	pinned := "sha256:" + strings.Repeat("d", 64)
+	if resolved, err := resolver.Resolve(context.Background(), "unreachable.invalid/server@"+pinned); err != nil || resolved != pinned {
+		t.Errorf("expected the pinned digest, got %q, %v", resolved, err)
+	}
+}
@@ -0,0 +1,90 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+)
+
+// maxStatusLength bounds a status response; modded servers list their mods and a favicon in it
+const maxStatusLength = 1024 * 1024
+
+// statusProtocolVersion is sent in the handshake; servers answer a status request for any version
+const statusProtocolVersion = -1
+
+// QueryVersion asks the server at address (host:port) for its version name with a server list ping
+// The name is whatever the server reports, e.g. "1.20.4" or "Paper 1.20.4"
+func QueryVersion(ctx context.Context, address string) (string, error) {
+	host, portValue, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+	port, err := strconv.ParseUint(portValue, 10, 16)
+	if err != nil {
+		return "", fmt.Errorf("invalid port %q", portValue)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	handshake := appendVarInt(nil, statusProtocolVersion)
+	handshake = appendString(handshake, host)
+	handshake = binary.BigEndian.AppendUint16(handshake, uint16(port))
+	handshake = appendVarInt(handshake, stateStatus)
+	if err := writePacket(conn, packetHandshake, handshake); err != nil {
+		return "", err
+	}
+	if err := writePacket(conn, packetStatusRequest, nil); err != nil {
+		return "", err
+	}
+
+	reader := bufio.NewReader(conn)
+	length, err := readVarInt(reader)
+	if err != nil {
+		return "", err
+	}
+	if length <= 0 || length > maxStatusLength {
+		return "", errPacketTooLarge
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return "", err
+	}
+
+	bodyReader := bytes.NewReader(body)
+	id, err := readVarInt(bodyReader)
+	if err != nil {
+		return "", err
+	}
+	if id != packetStatusResponse {
+		return "", fmt.Errorf("expected status response, got packet 0x%02x", id)
+	}
+	payload, err := readString(bodyReader, maxStatusLength)
+	if err != nil {
+		return "", err
+	}
+
+	// Only the version is decoded; the description may be a string or a chat component
+	var status struct {
+		Version struct {
+			Name string `json:"name"`
+		} `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(payload), &status); err != nil {
+		return "", fmt.Errorf("invalid status response: %w", err)
+	}
+	return status.Version.Name, nil
+}
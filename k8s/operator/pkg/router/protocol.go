@@ -0,0 +1,256 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Minecraft Java Edition protocol constants used by the router
+// See https://wiki.vg/Protocol for the packet layouts
+const (
+	// stateStatus and stateLogin are the next states announced in the handshake
+	stateStatus   = 1
+	stateLogin    = 2
+	stateTransfer = 3
+
+	packetHandshake       = 0x00
+	packetStatusRequest   = 0x00
+	packetStatusResponse  = 0x00
+	packetPing            = 0x01
+	packetLoginStart      = 0x00
+	packetLoginDisconnect = 0x00
+
+	// maxPacketLength bounds packets read before the client is routed; handshakes are tiny
+	maxPacketLength = 32 * 1024
+
+	// maxHostnameLength is the protocol limit of the handshake server address
+	maxHostnameLength = 255
+
+	// legacyPingByte starts the pre-1.7 server list ping, which the router doesn't speak
+	legacyPingByte = 0xFE
+)
+
+var (
+	errVarIntTooBig   = errors.New("varint is too big")
+	errPacketTooLarge = errors.New("packet is too large")
+	errLegacyPing     = errors.New("legacy server list ping is not supported")
+)
+
+// handshake is the first packet a client sends
+type handshake struct {
+	ProtocolVersion int32
+	ServerAddress   string
+	ServerPort      uint16
+	NextState       int32
+}
+
+// Hostname returns the server address normalised for routing
+// Forge appends "\x00FML\x00"-style markers and some clients send a trailing dot
+func (h *handshake) Hostname() string {
+	host := h.ServerAddress
+	if i := strings.IndexByte(host, 0); i >= 0 {
+		host = host[:i]
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// packet is a raw packet together with the bytes it was read from, so it can be replayed to a backend
+type packet struct {
+	ID   int32
+	Data []byte
+	Raw  []byte
+}
+
+// readVarInt reads a protocol VarInt
+func readVarInt(r io.ByteReader) (int32, error) {
+	var value uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint32(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, errVarIntTooBig
+}
+
+// appendVarInt appends value encoded as a protocol VarInt
+func appendVarInt(buf []byte, value int32) []byte {
+	v := uint32(value)
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
+
+// readPacket reads one uncompressed packet
+func readPacket(r *bufio.Reader) (*packet, error) {
+	// The very first byte tells a legacy ping apart from a modern handshake
+	if first, err := r.Peek(1); err == nil && first[0] == legacyPingByte {
+		return nil, errLegacyPing
+	}
+
+	var raw bytes.Buffer
+	length, err := readVarInt(teeByteReader{r, &raw})
+	if err != nil {
+		return nil, err
+	}
+	if length <= 0 || length > maxPacketLength {
+		return nil, errPacketTooLarge
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	raw.Write(body)
+
+	bodyReader := bytes.NewReader(body)
+	id, err := readVarInt(bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	data := body[len(body)-bodyReader.Len():]
+
+	return &packet{ID: id, Data: data, Raw: raw.Bytes()}, nil
+}
+
+// teeByteReader copies every byte read into a buffer
+type teeByteReader struct {
+	r   io.ByteReader
+	buf *bytes.Buffer
+}
+
+func (t teeByteReader) ReadByte() (byte, error) {
+	b, err := t.r.ReadByte()
+	if err == nil {
+		t.buf.WriteByte(b)
+	}
+	return b, err
+}
+
+// writePacket writes a packet with the given id and payload
+func writePacket(w io.Writer, id int32, data []byte) error {
+	body := appendVarInt(nil, id)
+	body = append(body, data...)
+	out := appendVarInt(nil, int32(len(body)))
+	out = append(out, body...)
+	_, err := w.Write(out)
+	return err
+}
+
+// readString reads a VarInt-prefixed UTF-8 string of at most maxLength bytes
+func readString(r *bytes.Reader, maxLength int) (string, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || int(length) > maxLength || int(length) > r.Len() {
+		return "", fmt.Errorf("invalid string length %d", length)
+	}
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// appendString appends a VarInt-prefixed string
+func appendString(buf []byte, value string) []byte {
+	buf = appendVarInt(buf, int32(len(value)))
+	return append(buf, value...)
+}
+
+// parseHandshake decodes a handshake packet
+func parseHandshake(p *packet) (*handshake, error) {
+	if p.ID != packetHandshake {
+		return nil, fmt.Errorf("expected handshake, got packet 0x%02x", p.ID)
+	}
+
+	r := bytes.NewReader(p.Data)
+	protocolVersion, err := readVarInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protocol version: %w", err)
+	}
+	// Forge markers can push the address past the hostname limit
+	address, err := readString(r, maxHostnameLength+64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server address: %w", err)
+	}
+	var port uint16
+	if err := binary.Read(r, binary.BigEndian, &port); err != nil {
+		return nil, fmt.Errorf("failed to read server port: %w", err)
+	}
+	nextState, err := readVarInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read next state: %w", err)
+	}
+
+	return &handshake{
+		ProtocolVersion: protocolVersion,
+		ServerAddress:   address,
+		ServerPort:      port,
+		NextState:       nextState,
+	}, nil
+}
+
+// parseLoginStart returns the player name of a login start packet
+func parseLoginStart(p *packet) (string, error) {
+	if p.ID != packetLoginStart {
+		return "", fmt.Errorf("expected login start, got packet 0x%02x", p.ID)
+	}
+	return readString(bytes.NewReader(p.Data), 16*4)
+}
+
+// statusResponse is the JSON shown in the client's server list
+type statusResponse struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int32  `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Max    int `json:"max"`
+		Online int `json:"online"`
+	} `json:"players"`
+	Description chatComponent `json:"description"`
+}
+
+// chatComponent is a plain text chat component; legacy section-sign codes are rendered by the client
+type chatComponent struct {
+	Text string `json:"text"`
+}
+
+// writeStatus answers a status request with a server list entry
+// A protocol of -1 never matches the client, so the version name is shown in place of the ping bars
+func writeStatus(w io.Writer, versionName, motd string, maxPlayers int) error {
+	var status statusResponse
+	status.Version.Name = versionName
+	status.Version.Protocol = -1
+	status.Players.Max = maxPlayers
+	status.Description.Text = motd
+
+	payload, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return writePacket(w, packetStatusResponse, appendString(nil, string(payload)))
+}
+
+// writeDisconnect kicks a client in the login state with message
+func writeDisconnect(w io.Writer, message string) error {
+	payload, err := json.Marshal(chatComponent{Text: message})
+	if err != nil {
+		return err
+	}
+	return writePacket(w, packetLoginDisconnect, appendString(nil, string(payload)))
+}
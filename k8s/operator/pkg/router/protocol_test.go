@@ -0,0 +1,162 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+// handshakeBytes encodes a handshake packet for address with the given next state
+func handshakeBytes(address string, nextState int32) []byte {
+	data := appendVarInt(nil, 765)
+	data = appendString(data, address)
+	data = binary.BigEndian.AppendUint16(data, 25565)
+	data = appendVarInt(data, nextState)
+
+	var buf bytes.Buffer
+	_ = writePacket(&buf, packetHandshake, data)
+	return buf.Bytes()
+}
+
+// loginStartBytes encodes a login start packet for player
+func loginStartBytes(player string) []byte {
+	var buf bytes.Buffer
+	_ = writePacket(&buf, packetLoginStart, appendString(nil, player))
+	return buf.Bytes()
+}
+
+func TestReadVarInt(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected int32
+		err      error
+	}{
+		{"zero", []byte{0x00}, 0, nil},
+		{"one byte", []byte{0x7f}, 127, nil},
+		{"two bytes", []byte{0x80, 0x01}, 128, nil},
+		{"handshake length", []byte{0xdd, 0xc7, 0x01}, 25565, nil},
+		{"max", []byte{0xff, 0xff, 0xff, 0xff, 0x07}, math.MaxInt32, nil},
+		{"negative", []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, -1, nil},
+		{"six bytes", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, 0, errVarIntTooBig},
+		{"truncated", []byte{0x80, 0x80}, 0, io.EOF},
+		{"empty", nil, 0, io.EOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := readVarInt(bytes.NewReader(tt.input))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if value != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, value)
+			}
+			if tt.err == nil && !bytes.Equal(appendVarInt(nil, value), tt.input) {
+				t.Errorf("expected %d to encode as %x, got %x", value, tt.input, appendVarInt(nil, value))
+			}
+		})
+	}
+}
+
+func TestReadPacket(t *testing.T) {
+	handshake := handshakeBytes("mc.example.com", stateStatus)
+	tests := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{"handshake", handshake, nil},
+		{"legacy ping", []byte{legacyPingByte, 0x01, 0xfa}, errLegacyPing},
+		{"oversized length", appendVarInt(nil, maxPacketLength+1), errPacketTooLarge},
+		{"negative length", appendVarInt(nil, -1), errPacketTooLarge},
+		{"empty packet", []byte{0x00}, errPacketTooLarge},
+		{"malformed length", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, errVarIntTooBig},
+		{"truncated body", handshake[:len(handshake)-2], io.ErrUnexpectedEOF},
+		{"malformed packet id", []byte{0x02, 0x80, 0x80}, io.EOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := readPacket(bufio.NewReader(bytes.NewReader(tt.input)))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if err == nil && (p.ID != packetHandshake || !bytes.Equal(p.Raw, tt.input)) {
+				t.Errorf("expected the raw handshake to be kept, got id %d, raw %x", p.ID, p.Raw)
+			}
+		})
+	}
+}
+
+func TestReadPacketAtLimit(t *testing.T) {
+	body := append(appendVarInt(nil, 0x7f), make([]byte, maxPacketLength-1)...)
+	input := append(appendVarInt(nil, int32(len(body))), body...)
+
+	p, err := readPacket(bufio.NewReader(bytes.NewReader(input)))
+	if err != nil {
+		t.Fatalf("readPacket: %v", err)
+	}
+	if p.ID != 0x7f || len(p.Data) != maxPacketLength-1 {
+		t.Errorf("unexpected packet id %d with %d bytes", p.ID, len(p.Data))
+	}
+}
+
+func TestParseHandshake(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		hostname string
+	}{
+		{"plain", "mc.example.com", "mc.example.com"},
+		{"upper case", "MC.Example.com", "mc.example.com"},
+		{"trailing dot", "mc.example.com.", "mc.example.com"},
+		{"forge", "mc.example.com\x00FML\x00", "mc.example.com"},
+		{"forge 1.13+", "mc.example.com\x00FML2\x00", "mc.example.com"},
+		{"forge 1.18+", "mc.example.com.\x00FML3\x00", "mc.example.com"},
+		{"forge at the hostname limit", strings.Repeat("a", maxHostnameLength) + "\x00FML3\x00", strings.Repeat("a", maxHostnameLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := readPacket(bufio.NewReader(bytes.NewReader(handshakeBytes(tt.address, stateLogin))))
+			if err != nil {
+				t.Fatalf("readPacket: %v", err)
+			}
+			hs, err := parseHandshake(p)
+			if err != nil {
+				t.Fatalf("parseHandshake: %v", err)
+			}
+			if hs.Hostname() != tt.hostname || hs.ServerPort != 25565 || hs.NextState != stateLogin || hs.ProtocolVersion != 765 {
+				t.Errorf("unexpected handshake %+v with hostname %q", hs, hs.Hostname())
+			}
+		})
+	}
+
+	invalid := map[string]*packet{
+		"not a handshake":  {ID: 0x01},
+		"address too long": {Data: appendString(appendVarInt(nil, 765), strings.Repeat("a", maxHostnameLength+65))},
+		"missing port":     {Data: appendString(appendVarInt(nil, 765), "mc.example.com")},
+		"address overruns": {Data: append(appendVarInt(appendVarInt(nil, 765), 20), "mc"...)},
+	}
+	for name, p := range invalid {
+		if hs, err := parseHandshake(p); err == nil {
+			t.Errorf("%s: expected an error, got %+v", name, hs)
+		}
+	}
+}
+
+func TestParseLoginStart(t *testing.T) {
+	p, err := readPacket(bufio.NewReader(bytes.NewReader(loginStartBytes("Notch"))))
+	if err != nil {
+		t.Fatalf("readPacket: %v", err)
+	}
+	if player, err := parseLoginStart(p); err != nil || player != "Notch" {
+		t.Errorf("expected Notch, got %q, %v", player, err)
+	}
+	if player, err := parseLoginStart(&packet{ID: packetPing}); err == nil {
+		t.Errorf("expected an error for a ping, got %q", player)
+	}
+}
@@ -0,0 +1,313 @@
+package router
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"minecraft-platform-operator/pkg/metrics"
+)
+
+// Backend is a server the router can route a hostname to
+type Backend struct {
+	// ID identifies the server when waking it
+	ID string
+
+	// DisplayName is shown in the sleeping/starting MOTD
+	DisplayName string
+
+	// Address is the host:port of the server's game port
+	Address string
+
+	// Ready is true when the server accepts players
+	Ready bool
+
+	// Starting is true while the server is starting or waking up
+	Starting bool
+
+	// CanWake is true when a login attempt may start the server (wake-on-connect)
+	CanWake bool
+
+	// MaxPlayers is shown in the server list while the server is not ready
+	MaxPlayers int
+}
+
+// Resolver looks up and wakes the servers behind hostnames
+type Resolver interface {
+	// Resolve returns the backend for hostname, or nil when no server uses it
+	Resolve(ctx context.Context, hostname string) (*Backend, error)
+
+	// Wake starts the server identified by id; player is the name that tried to log in
+	Wake(ctx context.Context, id, player string) error
+}
+
+// Options configures a Router
+type Options struct {
+	// Address the router listens on, e.g. ":25565"
+	Address string
+
+	// HoldTimeout is how long a login to a waking server is held before the player is kicked
+	// The client times out after about 30 seconds, so this should stay below that
+	HoldTimeout time.Duration
+
+	// RetryAfter is the estimated start time shown to kicked players
+	RetryAfter time.Duration
+
+	// HandshakeTimeout bounds the time a client may take to send its handshake and login
+	HandshakeTimeout time.Duration
+
+	// DialTimeout bounds connecting to a backend
+	DialTimeout time.Duration
+}
+
+// Router accepts Minecraft connections, routes them by handshake hostname and wakes sleeping servers
+type Router struct {
+	opts     Options
+	resolver Resolver
+
+	// pollInterval is how often a held login checks whether its server is ready
+	pollInterval time.Duration
+}
+
+// New creates a Router; zero durations in opts are replaced by defaults
+func New(opts Options, resolver Resolver) *Router {
+	if opts.HoldTimeout <= 0 {
+		opts.HoldTimeout = 25 * time.Second
+	}
+	if opts.RetryAfter <= 0 {
+		opts.RetryAfter = 60 * time.Second
+	}
+	if opts.HandshakeTimeout <= 0 {
+		opts.HandshakeTimeout = 10 * time.Second
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+	return &Router{opts: opts, resolver: resolver, pollInterval: 2 * time.Second}
+}
+
+// NeedLeaderElection lets every operator replica serve connections, not only the leader
+func (r *Router) NeedLeaderElection() bool {
+	return false
+}
+
+// Start accepts connections until ctx is done
+func (r *Router) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", r.opts.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", r.opts.Address, err)
+	}
+	log.Printf("Router listening on %s", r.opts.Address)
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	var connections sync.WaitGroup
+	defer connections.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			log.Printf("Router accept failed: %v", err)
+			continue
+		}
+
+		connections.Add(1)
+		go func() {
+			defer connections.Done()
+			r.handle(ctx, conn)
+		}()
+	}
+}
+
+// handle serves one client connection
+func (r *Router) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(r.opts.HandshakeTimeout))
+	reader := bufio.NewReader(conn)
+
+	handshakePacket, err := readPacket(reader)
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			log.Printf("Router: bad handshake from %s: %v", conn.RemoteAddr(), err)
+		}
+		return
+	}
+	hs, err := parseHandshake(handshakePacket)
+	if err != nil {
+		log.Printf("Router: bad handshake from %s: %v", conn.RemoteAddr(), err)
+		return
+	}
+
+	hostname := hs.Hostname()
+	backend, err := r.resolver.Resolve(ctx, hostname)
+	if err != nil {
+		log.Printf("Router: failed to resolve %s: %v", hostname, err)
+		return
+	}
+
+	switch hs.NextState {
+	case stateStatus:
+		r.handleStatus(ctx, conn, reader, handshakePacket, hostname, backend)
+	case stateLogin, stateTransfer:
+		r.handleLogin(ctx, conn, reader, handshakePacket, hostname, backend)
+	default:
+		log.Printf("Router: unknown next state %d from %s", hs.NextState, conn.RemoteAddr())
+	}
+}
+
+// handleStatus proxies a server list ping to a ready server, or answers it for a sleeping one
+func (r *Router) handleStatus(ctx context.Context, conn net.Conn, reader *bufio.Reader, handshakePacket *packet, hostname string, backend *Backend) {
+	if backend != nil && backend.Ready {
+		r.proxy(conn, reader, backend, handshakePacket)
+		return
+	}
+	metrics.RouterConnections.WithLabelValues("status").Inc()
+
+	versionName, motd, maxPlayers := "§7Unknown Host", fmt.Sprintf("§cNo server found for %s", hostname), 0
+	if backend != nil {
+		maxPlayers = backend.MaxPlayers
+		switch {
+		case backend.Starting:
+			versionName, motd = "§eStarting", fmt.Sprintf("§e%s is starting\n§7Join again in a moment", backend.DisplayName)
+		case backend.CanWake:
+			versionName, motd = "§7Sleeping", fmt.Sprintf("§7%s is sleeping\n§eJoin to wake it up", backend.DisplayName)
+		default:
+			versionName, motd = "§cOffline", fmt.Sprintf("§c%s is offline\n§eStart it from the dashboard!", backend.DisplayName)
+		}
+	}
+
+	// Status request, then an optional ping the client times the round trip with
+	for {
+		p, err := readPacket(reader)
+		if err != nil {
+			return
+		}
+		switch p.ID {
+		case packetStatusRequest:
+			if err := writeStatus(conn, versionName, motd, maxPlayers); err != nil {
+				return
+			}
+		case packetPing:
+			_, _ = conn.Write(p.Raw)
+			return
+		default:
+			return
+		}
+	}
+}
+
+// handleLogin proxies a login to a ready server; otherwise it wakes the server and holds or kicks the player
+func (r *Router) handleLogin(ctx context.Context, conn net.Conn, reader *bufio.Reader, handshakePacket *packet, hostname string, backend *Backend) {
+	if backend != nil && backend.Ready {
+		r.proxy(conn, reader, backend, handshakePacket)
+		return
+	}
+
+	loginPacket, err := readPacket(reader)
+	if err != nil {
+		return
+	}
+	player, err := parseLoginStart(loginPacket)
+	if err != nil {
+		log.Printf("Router: bad login from %s: %v", conn.RemoteAddr(), err)
+		return
+	}
+
+	if backend == nil {
+		metrics.RouterConnections.WithLabelValues("unknown").Inc()
+		_ = writeDisconnect(conn, fmt.Sprintf("§cNo server found for %s", hostname))
+		return
+	}
+
+	if !backend.Starting {
+		if !backend.CanWake {
+			metrics.RouterConnections.WithLabelValues("rejected").Inc()
+			_ = writeDisconnect(conn, fmt.Sprintf("§c%s is offline\n§7Start it from the dashboard", backend.DisplayName))
+			return
+		}
+		if err := r.resolver.Wake(ctx, backend.ID, player); err != nil {
+			log.Printf("Router: failed to wake %s for %s: %v", hostname, player, err)
+			_ = writeDisconnect(conn, fmt.Sprintf("§c%s could not be started, try again later", backend.DisplayName))
+			return
+		}
+		metrics.RouterConnections.WithLabelValues("woken").Inc()
+		log.Printf("Router: %s woke %s", player, hostname)
+	}
+
+	// Hold the login while the server starts; the client waits on "Logging in..."
+	_ = conn.SetDeadline(time.Time{})
+	deadline := time.Now().Add(r.opts.HoldTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.pollInterval):
+		}
+
+		current, err := r.resolver.Resolve(ctx, hostname)
+		if err != nil || current == nil {
+			continue
+		}
+		if current.Ready {
+			r.proxy(conn, reader, current, handshakePacket, loginPacket)
+			return
+		}
+	}
+
+	metrics.RouterConnections.WithLabelValues("held").Inc()
+	_ = writeDisconnect(conn, fmt.Sprintf("§e%s is starting\n§7Please reconnect in about %d seconds",
+		backend.DisplayName, int(r.opts.RetryAfter.Seconds())))
+}
+
+// proxy replays the packets already read to the backend and copies traffic both ways until either side closes
+func (r *Router) proxy(conn net.Conn, reader *bufio.Reader, backend *Backend, replay ...*packet) {
+	upstream, err := net.DialTimeout("tcp", backend.Address, r.opts.DialTimeout)
+	if err != nil {
+		log.Printf("Router: failed to connect to %s: %v", backend.Address, err)
+		return
+	}
+	defer upstream.Close()
+	metrics.RouterConnections.WithLabelValues("proxied").Inc()
+
+	_ = conn.SetDeadline(time.Time{})
+	for _, p := range replay {
+		if _, err := upstream.Write(p.Raw); err != nil {
+			return
+		}
+	}
+
+	done := make(chan struct{}, 2)
+	go func() {
+		// The reader may hold bytes the client sent after the packets read so far
+		_, _ = io.Copy(upstream, reader)
+		if tcp, ok := upstream.(*net.TCPConn); ok {
+			_ = tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			_ = tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}()
+	<-done
+	<-done
+}
@@ -0,0 +1,325 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeResolver serves backends from a map; a woken backend becomes ready at readyAddress
+type fakeResolver struct {
+	mu           sync.Mutex
+	backends     map[string]*Backend
+	readyAddress string
+	wakes        []string
+}
+
+func (f *fakeResolver) Resolve(_ context.Context, hostname string) (*Backend, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	backend, ok := f.backends[hostname]
+	if !ok {
+		return nil, nil
+	}
+	copied := *backend
+	return &copied, nil
+}
+
+func (f *fakeResolver) Wake(_ context.Context, id, player string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.wakes = append(f.wakes, id+"/"+player)
+	for _, backend := range f.backends {
+		if backend.ID != id {
+			continue
+		}
+		backend.Starting = true
+		if f.readyAddress != "" {
+			backend.Ready = true
+			backend.Address = f.readyAddress
+		}
+	}
+	return nil
+}
+
+// woken returns the wake calls as id/player
+func (f *fakeResolver) woken() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.wakes...)
+}
+
+// sleepingBackend is a sleeping server players can wake
+func sleepingBackend() *Backend {
+	return &Backend{ID: "server-1", DisplayName: "Survival", CanWake: true, MaxPlayers: 20}
+}
+
+// connect serves one client connection over net.Pipe and returns the client end
+// The returned channel is closed once the router is done with the connection
+func connect(t *testing.T, resolver Resolver, opts Options) (net.Conn, *bufio.Reader, <-chan struct{}) {
+	t.Helper()
+	r := New(opts, resolver)
+	r.pollInterval = 10 * time.Millisecond
+
+	client, server := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.handle(context.Background(), server)
+	}()
+	t.Cleanup(func() {
+		client.Close()
+		<-done
+	})
+	_ = client.SetDeadline(time.Now().Add(5 * time.Second))
+	return client, bufio.NewReader(client), done
+}
+
+// readDisconnect reads a login disconnect and returns its message
+func readDisconnect(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+	p, err := readPacket(reader)
+	if err != nil {
+		t.Fatalf("failed to read disconnect: %v", err)
+	}
+	payload, err := readString(bytes.NewReader(p.Data), len(p.Data))
+	if err != nil || p.ID != packetLoginDisconnect {
+		t.Fatalf("unexpected packet 0x%02x: %v", p.ID, err)
+	}
+	var message chatComponent
+	if err := json.Unmarshal([]byte(payload), &message); err != nil {
+		t.Fatalf("invalid disconnect %q: %v", payload, err)
+	}
+	return message.Text
+}
+
+// expectClosed checks that the router closed the connection without answering
+func expectClosed(t *testing.T, reader *bufio.Reader, done <-chan struct{}) {
+	t.Helper()
+	if _, err := reader.ReadByte(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected the connection to be closed, got %v", err)
+	}
+	<-done
+}
+
+func TestHandleRejectsBadHandshakes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"legacy ping", []byte{legacyPingByte, 0x01, 0xfa}},
+		{"malformed length", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+		{"oversized packet", appendVarInt(nil, maxPacketLength+1)},
+		{"not a handshake", []byte{0x01, 0x01}},
+		{"unknown next state", handshakeBytes("mc.example.com", 7)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &fakeResolver{backends: map[string]*Backend{"mc.example.com": sleepingBackend()}}
+			client, reader, done := connect(t, resolver, Options{})
+			if _, err := client.Write(tt.input); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			expectClosed(t, reader, done)
+			if wakes := resolver.woken(); len(wakes) != 0 {
+				t.Errorf("unexpected wake-ups %v", wakes)
+			}
+		})
+	}
+}
+
+func TestHandleStatusWhileNotReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		backend *Backend
+		version string
+		motd    string
+	}{
+		{"sleeping", "mc.example.com", sleepingBackend(), "§7Sleeping", "§7Survival is sleeping\n§eJoin to wake it up"},
+		{"forge client", "MC.example.com\x00FML3\x00", sleepingBackend(), "§7Sleeping", "§7Survival is sleeping\n§eJoin to wake it up"},
+		{"starting", "mc.example.com", &Backend{DisplayName: "Survival", Starting: true, MaxPlayers: 20}, "§eStarting", "§eSurvival is starting\n§7Join again in a moment"},
+		{"offline", "mc.example.com", &Backend{DisplayName: "Survival", MaxPlayers: 20}, "§cOffline", "§cSurvival is offline\n§eStart it from the dashboard!"},
+		{"unknown hostname", "other.example.com", sleepingBackend(), "§7Unknown Host", "§cNo server found for other.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &fakeResolver{backends: map[string]*Backend{"mc.example.com": tt.backend}}
+			client, reader, done := connect(t, resolver, Options{})
+
+			var request bytes.Buffer
+			request.Write(handshakeBytes(tt.address, stateStatus))
+			_ = writePacket(&request, packetStatusRequest, nil)
+			if _, err := client.Write(request.Bytes()); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+
+			p, err := readPacket(reader)
+			if err != nil || p.ID != packetStatusResponse {
+				t.Fatalf("expected a status response, got %+v, %v", p, err)
+			}
+			payload, err := readString(bytes.NewReader(p.Data), len(p.Data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var status statusResponse
+			if err := json.Unmarshal([]byte(payload), &status); err != nil {
+				t.Fatalf("invalid status %q: %v", payload, err)
+			}
+			if status.Version.Name != tt.version || status.Version.Protocol != -1 || status.Description.Text != tt.motd {
+				t.Errorf("unexpected status %+v", status)
+			}
+
+			// The ping is echoed back and ends the exchange
+			var ping bytes.Buffer
+			_ = writePacket(&ping, packetPing, []byte{0, 0, 0, 0, 0, 0, 0x30, 0x39})
+			if _, err := client.Write(ping.Bytes()); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			pong := make([]byte, ping.Len())
+			if _, err := io.ReadFull(reader, pong); err != nil || !bytes.Equal(pong, ping.Bytes()) {
+				t.Errorf("expected the ping echoed, got %x, %v", pong, err)
+			}
+			expectClosed(t, reader, done)
+
+			if wakes := resolver.woken(); len(wakes) != 0 {
+				t.Errorf("a status ping woke the server: %v", wakes)
+			}
+		})
+	}
+}
+
+func TestHandleLoginKicks(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		backend *Backend
+		message string
+		wakes   []string
+	}{
+		{"unknown hostname", "other.example.com", sleepingBackend(), "§cNo server found for other.example.com", nil},
+		{"offline", "mc.example.com", &Backend{ID: "server-1", DisplayName: "Survival"}, "§cSurvival is offline\n§7Start it from the dashboard", nil},
+		{"wake times out", "mc.example.com", sleepingBackend(), "§eSurvival is starting\n§7Please reconnect in about 45 seconds", []string{"server-1/Notch"}},
+		{"already starting", "mc.example.com", &Backend{ID: "server-1", DisplayName: "Survival", Starting: true}, "§eSurvival is starting\n§7Please reconnect in about 45 seconds", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &fakeResolver{backends: map[string]*Backend{"mc.example.com": tt.backend}}
+			client, reader, done := connect(t, resolver, Options{HoldTimeout: 50 * time.Millisecond, RetryAfter: 45 * time.Second})
+
+			login := append(handshakeBytes(tt.address, stateLogin), loginStartBytes("Notch")...)
+			if _, err := client.Write(login); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if message := readDisconnect(t, reader); message != tt.message {
+				t.Errorf("expected %q, got %q", tt.message, message)
+			}
+			expectClosed(t, reader, done)
+
+			if wakes := resolver.woken(); strings.Join(wakes, ",") != strings.Join(tt.wakes, ",") {
+				t.Errorf("expected wake-ups %v, got %v", tt.wakes, wakes)
+			}
+		})
+	}
+}
+
+// backendServer accepts one connection, checks that it starts with expected and answers with reply
+func backendServer(t *testing.T, expected, reply []byte) (string, <-chan error) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	result := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			result <- err
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		received := make([]byte, len(expected))
+		if _, err := io.ReadFull(conn, received); err != nil {
+			result <- err
+			return
+		}
+		if !bytes.Equal(received, expected) {
+			result <- errors.New("backend received " + string(received))
+			return
+		}
+		_, err = conn.Write(reply)
+		result <- err
+	}()
+	return listener.Addr().String(), result
+}
+
+func TestHandleLoginWakesAndProxies(t *testing.T) {
+	login := append(handshakeBytes("mc.example.com\x00FML2\x00", stateLogin), loginStartBytes("Notch")...)
+	reply := []byte("login success")
+	address, backendResult := backendServer(t, login, reply)
+
+	resolver := &fakeResolver{
+		backends:     map[string]*Backend{"mc.example.com": sleepingBackend()},
+		readyAddress: address,
+	}
+	client, reader, done := connect(t, resolver, Options{HoldTimeout: 5 * time.Second})
+
+	if _, err := client.Write(login); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// The held handshake and login are replayed unchanged once the server is ready
+	received := make([]byte, len(reply))
+	if _, err := io.ReadFull(reader, received); err != nil || !bytes.Equal(received, reply) {
+		t.Fatalf("expected the backend reply, got %q, %v", received, err)
+	}
+	if err := <-backendResult; err != nil {
+		t.Fatalf("backend: %v", err)
+	}
+	client.Close()
+	<-done
+
+	if wakes := resolver.woken(); len(wakes) != 1 || wakes[0] != "server-1/Notch" {
+		t.Errorf("expected one wake-up by Notch, got %v", wakes)
+	}
+}
+
+func TestHandleProxiesReadyServer(t *testing.T) {
+	handshake := handshakeBytes("mc.example.com", stateStatus)
+	reply := []byte("status")
+	address, backendResult := backendServer(t, handshake, reply)
+
+	resolver := &fakeResolver{backends: map[string]*Backend{
+		"mc.example.com": {ID: "server-1", DisplayName: "Survival", Address: address, Ready: true},
+	}}
+	client, reader, done := connect(t, resolver, Options{})
+
+	if _, err := client.Write(handshake); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	received := make([]byte, len(reply))
+	if _, err := io.ReadFull(reader, received); err != nil || !bytes.Equal(received, reply) {
+		t.Fatalf("expected the backend reply, got %q, %v", received, err)
+	}
+	if err := <-backendResult; err != nil {
+		t.Fatalf("backend: %v", err)
+	}
+	client.Close()
+	<-done
+
+	if wakes := resolver.woken(); len(wakes) != 0 {
+		t.Errorf("a ready server was woken: %v", wakes)
+	}
+}
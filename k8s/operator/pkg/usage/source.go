@@ -0,0 +1,171 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/rest"
+)
+
+// Usage is the resource consumption of a single server pod
+type Usage struct {
+	// CPU and Memory come from metrics.k8s.io, or the kubelet stats summary without metrics-server
+	CPU    resource.Quantity
+	Memory resource.Quantity
+
+	// Storage is the used bytes of the data volume, from the kubelet stats summary
+	Storage resource.Quantity
+
+	// RxBytes and TxBytes are cumulative pod network counters, from the kubelet stats summary
+	RxBytes int64
+	TxBytes int64
+}
+
+// Source reads the resource usage of a server pod
+// claimName is the PVC holding the world data; its usage is reported as Storage
+type Source interface {
+	PodUsage(ctx context.Context, pod *corev1.Pod, claimName string) (*Usage, error)
+}
+
+// KubernetesSource reads CPU/memory from metrics.k8s.io and storage/network from the kubelet
+// stats summary, proxied through the API server
+type KubernetesSource struct {
+	client rest.Interface
+}
+
+// NewKubernetesSource creates a Source backed by the API server
+// client is typically clientset.CoreV1().RESTClient()
+func NewKubernetesSource(client rest.Interface) *KubernetesSource {
+	return &KubernetesSource{client: client}
+}
+
+// podMetrics is the subset of metrics.k8s.io/v1beta1 PodMetrics the operator reads
+type podMetrics struct {
+	Containers []struct {
+		Name  string              `json:"name"`
+		Usage corev1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+// statsSummary is the subset of the kubelet /stats/summary response the operator reads
+type statsSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		CPU *struct {
+			UsageNanoCores *uint64 `json:"usageNanoCores"`
+		} `json:"cpu"`
+		Memory *struct {
+			WorkingSetBytes *uint64 `json:"workingSetBytes"`
+		} `json:"memory"`
+		Network *struct {
+			RxBytes *uint64 `json:"rxBytes"`
+			TxBytes *uint64 `json:"txBytes"`
+		} `json:"network"`
+		Volumes []struct {
+			UsedBytes *uint64 `json:"usedBytes"`
+			PVCRef    *struct {
+				Name string `json:"name"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// PodUsage returns the current usage of pod
+// Metrics that are unavailable (no metrics-server, pod not yet scheduled) are left at zero;
+// an error is only returned when no source could be read at all
+func (s *KubernetesSource) PodUsage(ctx context.Context, pod *corev1.Pod, claimName string) (*Usage, error) {
+	usage := &Usage{}
+
+	metricsErr := s.readPodMetrics(ctx, pod, usage)
+	statsErr := s.readStatsSummary(ctx, pod, claimName, metricsErr != nil, usage)
+	if metricsErr != nil && statsErr != nil {
+		return nil, fmt.Errorf("metrics.k8s.io: %v; kubelet stats: %v", metricsErr, statsErr)
+	}
+
+	return usage, nil
+}
+
+// readPodMetrics sums container CPU and memory from metrics.k8s.io
+func (s *KubernetesSource) readPodMetrics(ctx context.Context, pod *corev1.Pod, usage *Usage) error {
+	data, err := s.client.Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", pod.Namespace, "pods", pod.Name).
+		DoRaw(ctx)
+	if err != nil {
+		return err
+	}
+
+	var metrics podMetrics
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		return fmt.Errorf("failed to decode pod metrics: %w", err)
+	}
+
+	for _, container := range metrics.Containers {
+		if cpu, ok := container.Usage[corev1.ResourceCPU]; ok {
+			usage.CPU.Add(cpu)
+		}
+		if memory, ok := container.Usage[corev1.ResourceMemory]; ok {
+			usage.Memory.Add(memory)
+		}
+	}
+
+	return nil
+}
+
+// readStatsSummary reads volume and network usage from the kubelet on the pod's node
+// With withCPUMemory set, CPU and memory are taken from the summary too (used when metrics-server is missing)
+func (s *KubernetesSource) readStatsSummary(ctx context.Context, pod *corev1.Pod, claimName string, withCPUMemory bool, usage *Usage) error {
+	if pod.Spec.NodeName == "" {
+		return fmt.Errorf("pod %s is not scheduled", pod.Name)
+	}
+
+	data, err := s.client.Get().
+		AbsPath("/api/v1/nodes", pod.Spec.NodeName, "proxy/stats/summary").
+		DoRaw(ctx)
+	if err != nil {
+		return err
+	}
+
+	var summary statsSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return fmt.Errorf("failed to decode stats summary: %w", err)
+	}
+
+	for _, podStats := range summary.Pods {
+		if podStats.PodRef.Name != pod.Name || podStats.PodRef.Namespace != pod.Namespace {
+			continue
+		}
+
+		if withCPUMemory {
+			if podStats.CPU != nil && podStats.CPU.UsageNanoCores != nil {
+				usage.CPU = *resource.NewScaledQuantity(int64(*podStats.CPU.UsageNanoCores), resource.Nano)
+			}
+			if podStats.Memory != nil && podStats.Memory.WorkingSetBytes != nil {
+				usage.Memory = *resource.NewQuantity(int64(*podStats.Memory.WorkingSetBytes), resource.BinarySI)
+			}
+		}
+
+		if network := podStats.Network; network != nil {
+			if network.RxBytes != nil {
+				usage.RxBytes = int64(*network.RxBytes)
+			}
+			if network.TxBytes != nil {
+				usage.TxBytes = int64(*network.TxBytes)
+			}
+		}
+
+		for _, volume := range podStats.Volumes {
+			if volume.PVCRef != nil && volume.PVCRef.Name == claimName && volume.UsedBytes != nil {
+				usage.Storage = *resource.NewQuantity(int64(*volume.UsedBytes), resource.BinarySI)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("pod %s not found in stats summary of node %s", pod.Name, pod.Spec.NodeName)
+}
@@ -0,0 +1,135 @@
+package usage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	podMetricsPath   = "/apis/metrics.k8s.io/v1beta1/namespaces/tenant-a/pods/survival-0"
+	statsSummaryPath = "/api/v1/nodes/node-1/proxy/stats/summary"
+)
+
+// fixture reads a captured API response from testdata
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// newTestSource serves responses by path from a fake API server; missing paths return 404
+func newTestSource(t *testing.T, responses map[string][]byte) *KubernetesSource {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	base, _ := url.Parse(srv.URL)
+	client, err := rest.NewRESTClient(base, "", rest.ClientContentConfig{}, nil, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewKubernetesSource(client)
+}
+
+func testPod(nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival-0", Namespace: "tenant-a"},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func TestPodUsage(t *testing.T) {
+	metrics := fixture(t, "pod-metrics.json")
+	summary := fixture(t, "stats-summary.json")
+
+	tests := []struct {
+		name      string
+		responses map[string][]byte
+		nodeName  string
+		cpu       string
+		memory    string
+		storage   string
+		rx, tx    int64
+		wantErr   bool
+	}{
+		{
+			name:      "metrics-server and kubelet",
+			responses: map[string][]byte{podMetricsPath: metrics, statsSummaryPath: summary},
+			nodeName:  "node-1",
+			// Both containers from metrics.k8s.io, the data volume and network from the kubelet
+			cpu: "415m", memory: "2474220Ki", storage: "3Gi", rx: 734003200, tx: 1879048192,
+		},
+		{
+			name:      "without metrics-server",
+			responses: map[string][]byte{statsSummaryPath: summary},
+			nodeName:  "node-1",
+			cpu:       "430m", memory: "2416Mi", storage: "3Gi", rx: 734003200, tx: 1879048192,
+		},
+		{
+			name:      "malformed pod metrics",
+			responses: map[string][]byte{podMetricsPath: []byte(`{"containers":`), statsSummaryPath: summary},
+			nodeName:  "node-1",
+			cpu:       "430m", memory: "2416Mi", storage: "3Gi", rx: 734003200, tx: 1879048192,
+		},
+		{
+			name:      "not scheduled",
+			responses: map[string][]byte{podMetricsPath: metrics},
+			cpu:       "415m", memory: "2474220Ki", storage: "0",
+		},
+		{
+			name:      "pod missing from the summary",
+			responses: map[string][]byte{podMetricsPath: metrics, statsSummaryPath: []byte(`{"pods":[]}`)},
+			nodeName:  "node-1",
+			cpu:       "415m", memory: "2474220Ki", storage: "0",
+		},
+		{
+			name:      "nothing readable",
+			responses: map[string][]byte{statsSummaryPath: []byte(`not json`)},
+			nodeName:  "node-1",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := newTestSource(t, tt.responses)
+			usage, err := source.PodUsage(context.Background(), testPod(tt.nodeName), "minecraft-data-survival-0")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", usage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PodUsage: %v", err)
+			}
+			if usage.CPU.Cmp(resource.MustParse(tt.cpu)) != 0 || usage.Memory.Cmp(resource.MustParse(tt.memory)) != 0 ||
+				usage.Storage.Cmp(resource.MustParse(tt.storage)) != 0 {
+				t.Errorf("got cpu %s, memory %s, storage %s; want %s, %s, %s",
+					usage.CPU.String(), usage.Memory.String(), usage.Storage.String(), tt.cpu, tt.memory, tt.storage)
+			}
+			if usage.RxBytes != tt.rx || usage.TxBytes != tt.tx {
+				t.Errorf("got network %d/%d, want %d/%d", usage.RxBytes, usage.TxBytes, tt.rx, tt.tx)
+			}
+		})
+	}
+}
@@ -80,7 +80,7 @@ build_images() {
 # Install CRDs
 install_crds() {
     log_info "Installing CRDs..."
-    kubectl apply -f "$PROJECT_ROOT/k8s/operator/config/crd/"
+    kubectl apply -f "$PROJECT_ROOT/k8s/operator/config/crd/minecraft.platform.com_minecraftservers.yaml"
     log_success "CRDs installed"
 }
 
@@ -185,7 +185,7 @@ teardown() {
     kubectl delete -f "$PROJECT_ROOT/k8s/manifests/dev/cockroachdb.yaml" 2>/dev/null || true
 
     # Delete CRDs
-    kubectl delete -f "$PROJECT_ROOT/k8s/operator/config/crd/" 2>/dev/null || true
+    kubectl delete -f "$PROJECT_ROOT/k8s/operator/config/crd/minecraft.platform.com_minecraftservers.yaml" 2>/dev/null || true
 
     # Delete namespaces
     kubectl delete -f "$PROJECT_ROOT/k8s/manifests/dev/namespace.yaml" 2>/dev/null || true